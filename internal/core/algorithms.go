@@ -0,0 +1,476 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// Nutri-Score algorithm versions known to the built-in registry
+const (
+	AlgorithmVersion2017         = "2017"          // Original 2017 Santé publique France algorithm for foods
+	AlgorithmVersion2017Beverage = "2017-beverage" // Original 2017 algorithm for beverages
+	AlgorithmVersion2023Food     = "2023-food"     // 2023 revision for solid foods (including cheese)
+	AlgorithmVersion2017FatsOils = "2017-fats"     // Original 2017 algorithm for added fats and oils
+	AlgorithmVersion2023Beverage = "2023-beverage" // 2023 revision for beverages
+	AlgorithmVersion2023FatsOils = "2023-fats"     // 2023 revision for fats, oils, nuts and seeds
+)
+
+// Algorithm families group the versions that belong to the same revision
+// A scorer configured with a family picks the matching version for each score type
+const (
+	AlgorithmFamily2017 = "2017"
+	AlgorithmFamily2023 = "2023"
+)
+
+// DefaultAlgorithmVersion is the algorithm used when no version is requested
+const DefaultAlgorithmVersion = AlgorithmVersion2017
+
+// PointScale maps a nutrient amount onto Nutri-Score points
+// A value strictly greater than Thresholds[i] earns Points[i]; values at or below
+// the first threshold earn 0 points. Inclusive scales already award Points[i] to a
+// value equal to Thresholds[i]
+type PointScale struct {
+	Thresholds []float64 `json:"thresholds"`
+	Points     []int     `json:"points"`
+	Inclusive  bool      `json:"inclusive,omitempty"`
+}
+
+// newLinearScale creates a scale where each exceeded threshold adds one point
+func newLinearScale(thresholds ...float64) PointScale {
+	points := make([]int, len(thresholds))
+	for i := range thresholds {
+		points[i] = i + 1
+	}
+	return PointScale{Thresholds: thresholds, Points: points}
+}
+
+// newInclusiveScale creates a linear scale whose thresholds are scored once reached
+func newInclusiveScale(thresholds ...float64) PointScale {
+	scale := newLinearScale(thresholds...)
+	scale.Inclusive = true
+	return scale
+}
+
+// reaches reports whether a value earns the points of a threshold
+func (ps PointScale) reaches(value, threshold float64) bool {
+	if ps.Inclusive {
+		return value >= threshold
+	}
+	return value > threshold
+}
+
+// Score returns the points earned by the given value
+func (ps PointScale) Score(value float64) int {
+	points := 0
+	for i, threshold := range ps.Thresholds {
+		if !ps.reaches(value, threshold) {
+			break
+		}
+		points = ps.Points[i]
+	}
+	return points
+}
+
+// Component scores a value and records the threshold band it fell into
+func (ps PointScale) Component(value float64) models.ComponentScore {
+	component := models.ComponentScore{
+		Value:         value,
+		MaxPoints:     ps.MaxPoints(),
+		BandInclusive: ps.Inclusive,
+	}
+
+	for i, threshold := range ps.Thresholds {
+		if !ps.reaches(value, threshold) {
+			upper := threshold
+			component.BandUpper = &upper
+			return component
+		}
+		component.Points = ps.Points[i]
+		component.BandLower = threshold
+	}
+
+	return component
+}
+
+// MaxPoints returns the highest number of points the scale can award
+func (ps PointScale) MaxPoints() int {
+	if len(ps.Points) == 0 {
+		return 0
+	}
+	return ps.Points[len(ps.Points)-1]
+}
+
+// GradeBoundary assigns a letter grade to scores up to and including MaxScore
+type GradeBoundary struct {
+	Grade    string `json:"grade"`
+	MaxScore int    `json:"max_score"`
+}
+
+// Algorithm describes one version of the Nutri-Score computation
+// It holds every point scale and grade boundary needed to score a product
+type Algorithm struct {
+	Version     string             `json:"version"`               // Unique version identifier (e.g. "2023-food")
+	Family      string             `json:"family"`                // Revision the version belongs to (e.g. "2023")
+	Description string             `json:"description,omitempty"` // Human-readable description
+	ScoreTypes  []models.ScoreType `json:"score_types"`           // Score types the version applies to
+
+	// Negative components (nutrients to limit), per 100g or 100ml
+	Energy       PointScale `json:"energy"`        // Energy in kJ (or kJ from saturated fat, see EnergyFromSaturates)
+	Sugars       PointScale `json:"sugars"`        // Sugars in g
+	SaturatedFat PointScale `json:"saturated_fat"` // Saturated fatty acids in g (or % of total fat, see SaturatedFatRatio)
+	Sodium       PointScale `json:"sodium"`        // Sodium in mg
+
+	// SaturatedFatRatio scores saturated fat as a percentage of total fat instead of grams
+	// EnergyFromSaturates scores the energy provided by saturated fat instead of total energy
+	// Both are used for fats, oils, nuts and seeds
+	SaturatedFatRatio   bool `json:"saturated_fat_ratio,omitempty"`
+	EnergyFromSaturates bool `json:"energy_from_saturates,omitempty"`
+
+	// Positive components (beneficial nutrients), per 100g or 100ml
+	Fruits  PointScale `json:"fruits"`  // Fruits/vegetables/nuts in percent
+	Fibre   PointScale `json:"fibre"`   // Fibre in g
+	Protein PointScale `json:"protein"` // Protein in g
+
+	// BeveragePositives counts the fibre and protein points of beverages as well as
+	// their fruit points. Earlier versions only count fruit for beverages
+	BeveragePositives bool `json:"beverage_positives,omitempty"`
+
+	// RedMeatProteinCap limits the protein points of red meat products (0 means no cap)
+	RedMeatProteinCap int `json:"red_meat_protein_cap,omitempty"`
+
+	// Protein points are not counted for foods whose negative points reach
+	// ProteinExclusionThreshold (0 disables the rule), unless their fruit points reach
+	// ProteinExclusionFruitExemption (0 means fruit gives no exemption). Cheese is always exempt
+	ProteinExclusionThreshold      int `json:"protein_exclusion_threshold,omitempty"`
+	ProteinExclusionFruitExemption int `json:"protein_exclusion_fruit_exemption,omitempty"`
+
+	// SweetenerPenalty is added to the negative points of beverages containing
+	// non-nutritive sweeteners (0 disables the penalty)
+	SweetenerPenalty int `json:"sweetener_penalty,omitempty"`
+
+	// Grades lists the grade boundaries from best to worst
+	// The last grade catches every score above the previous boundary
+	Grades []GradeBoundary `json:"grades"`
+}
+
+// AppliesTo reports whether the algorithm is meant for the given score type
+func (a *Algorithm) AppliesTo(scoreType models.ScoreType) bool {
+	for _, st := range a.ScoreTypes {
+		if st == scoreType {
+			return true
+		}
+	}
+	return false
+}
+
+// Grade converts a final score into a letter grade using the algorithm's boundaries
+func (a *Algorithm) Grade(score int) string {
+	for i, boundary := range a.Grades {
+		if i == len(a.Grades)-1 || score <= boundary.MaxScore {
+			return boundary.Grade
+		}
+	}
+	return ""
+}
+
+// Thresholds returns the grade boundaries as a map for display purposes
+// Every grade maps to its highest score, except the worst grade which maps to its lowest score
+func (a *Algorithm) Thresholds() map[string]int {
+	thresholds := make(map[string]int, len(a.Grades))
+	for i, boundary := range a.Grades {
+		if i == len(a.Grades)-1 && i > 0 {
+			thresholds[boundary.Grade] = a.Grades[i-1].MaxScore + 1
+			continue
+		}
+		thresholds[boundary.Grade] = boundary.MaxScore
+	}
+	return thresholds
+}
+
+// AlgorithmRegistry stores the available Nutri-Score algorithm versions
+type AlgorithmRegistry struct {
+	algorithms map[string]*Algorithm
+}
+
+// NewAlgorithmRegistry creates an empty algorithm registry
+func NewAlgorithmRegistry() *AlgorithmRegistry {
+	return &AlgorithmRegistry{
+		algorithms: make(map[string]*Algorithm),
+	}
+}
+
+// Register adds an algorithm to the registry
+// Returns an error if the version is empty or already registered
+func (r *AlgorithmRegistry) Register(algorithm *Algorithm) error {
+	if algorithm == nil || algorithm.Version == "" {
+		return models.NewConfigError("Algorithm version is required", "cannot register an algorithm without a version")
+	}
+	if _, exists := r.algorithms[algorithm.Version]; exists {
+		return models.NewConfigError("Algorithm version already registered",
+			fmt.Sprintf("version %q is already registered", algorithm.Version))
+	}
+	r.algorithms[algorithm.Version] = algorithm
+	return nil
+}
+
+// Get returns the algorithm registered under the given version
+func (r *AlgorithmRegistry) Get(version string) (*Algorithm, error) {
+	algorithm, exists := r.algorithms[version]
+	if !exists {
+		return nil, models.NewConfigError(fmt.Sprintf("Unknown algorithm version: %s", version),
+			fmt.Sprintf("available versions: %v", r.Versions()))
+	}
+	return algorithm, nil
+}
+
+// Resolve finds the algorithm to use for a requested version and score type
+// The version can be an exact algorithm version (e.g. "2023-beverage") or a family
+// (e.g. "2023"). When the requested version does not apply to the score type, the
+// member of the same family that does is used instead (e.g. "2017" for a beverage
+// resolves to "2017-beverage")
+func (r *AlgorithmRegistry) Resolve(version string, scoreType models.ScoreType) (*Algorithm, error) {
+	family := version
+	if algorithm, exists := r.algorithms[version]; exists {
+		if algorithm.AppliesTo(scoreType) {
+			return algorithm, nil
+		}
+		family = algorithm.Family
+	}
+
+	for _, v := range r.Versions() {
+		algorithm := r.algorithms[v]
+		if algorithm.Family == family && algorithm.AppliesTo(scoreType) {
+			return algorithm, nil
+		}
+	}
+
+	return nil, models.NewConfigError(
+		fmt.Sprintf("No algorithm for version %s and score type %s", version, scoreType),
+		fmt.Sprintf("available versions: %v", r.Versions()))
+}
+
+// Versions returns all registered versions in sorted order
+func (r *AlgorithmRegistry) Versions() []string {
+	versions := make([]string, 0, len(r.algorithms))
+	for version := range r.algorithms {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// Families returns the families of the registered versions in sorted order
+func (r *AlgorithmRegistry) Families() []string {
+	seen := make(map[string]bool)
+	var families []string
+	for _, algorithm := range r.algorithms {
+		if !seen[algorithm.Family] {
+			seen[algorithm.Family] = true
+			families = append(families, algorithm.Family)
+		}
+	}
+	sort.Strings(families)
+	return families
+}
+
+// defaultRegistry holds the built-in official algorithm versions
+var (
+	defaultRuleset         = DefaultRuleset()
+	defaultRegistry        = mustRegistry(defaultRuleset)
+	defaultRulesetChecksum = defaultRuleset.Checksum()
+)
+
+// DefaultAlgorithmRegistry returns the registry containing the built-in algorithm versions
+func DefaultAlgorithmRegistry() *AlgorithmRegistry {
+	return defaultRegistry
+}
+
+// mustRegistry builds a registry from a ruleset known to be valid
+func mustRegistry(ruleset *Ruleset) *AlgorithmRegistry {
+	registry, err := ruleset.Registry()
+	if err != nil {
+		panic(err)
+	}
+	return registry
+}
+
+// GetAlgorithm returns a built-in algorithm by version
+func GetAlgorithm(version string) (*Algorithm, error) {
+	return defaultRegistry.Get(version)
+}
+
+// AvailableAlgorithmVersions returns the versions of all built-in algorithms
+func AvailableAlgorithmVersions() []string {
+	return defaultRegistry.Versions()
+}
+
+// builtinAlgorithms returns the official algorithm versions compiled into the application
+func builtinAlgorithms() []*Algorithm {
+	return []*Algorithm{
+		newAlgorithm2017(),
+		newAlgorithm2017Beverage(),
+		newAlgorithm2017FatsOils(),
+		newAlgorithm2023Food(),
+		newAlgorithm2023Beverage(),
+		newAlgorithm2023FatsOils(),
+	}
+}
+
+// newAlgorithm2017 returns the original 2017 Nutri-Score algorithm for foods
+func newAlgorithm2017() *Algorithm {
+	return &Algorithm{
+		Version:      AlgorithmVersion2017,
+		Family:       AlgorithmFamily2017,
+		Description:  "Original Nutri-Score algorithm (2017)",
+		ScoreTypes:   []models.ScoreType{models.FoodType, models.CheeseType},
+		Energy:       newLinearScale(335, 670, 1005, 1340, 1675, 2010, 2345, 2680, 3015, 3350),
+		Sugars:       newLinearScale(4.5, 9, 13.5, 18, 22.5, 27, 31, 36, 40, 45),
+		SaturatedFat: newLinearScale(1, 2, 3, 4, 5, 6, 7, 8, 9, 10),
+		Sodium:       newLinearScale(90, 180, 270, 360, 450, 540, 630, 720, 810, 900),
+		Fruits:       PointScale{Thresholds: []float64{40, 60, 80}, Points: []int{1, 2, 5}},
+		Fibre:        newLinearScale(0.9, 1.9, 2.8, 3.7, 4.7),
+		Protein:      newLinearScale(1.6, 3.2, 4.8, 6.4, 8.0),
+
+		ProteinExclusionThreshold:      11,
+		ProteinExclusionFruitExemption: 5,
+
+		Grades: []GradeBoundary{
+			{Grade: "A", MaxScore: -1},
+			{Grade: "B", MaxScore: 2},
+			{Grade: "C", MaxScore: 10},
+			{Grade: "D", MaxScore: 18},
+			{Grade: "E"},
+		},
+	}
+}
+
+// newAlgorithm2017Beverage returns the original 2017 Nutri-Score algorithm for beverages
+// Energy is scored in 30 kJ steps, sugars in 1.5 g steps, fruits/vegetables earn up to
+// 10 points and only water can be graded A
+func newAlgorithm2017Beverage() *Algorithm {
+	return &Algorithm{
+		Version:      AlgorithmVersion2017Beverage,
+		Family:       AlgorithmFamily2017,
+		Description:  "Original Nutri-Score algorithm for beverages (2017)",
+		ScoreTypes:   []models.ScoreType{models.BeverageType, models.WaterType},
+		Energy:       newLinearScale(0, 30, 60, 90, 120, 150, 180, 210, 240, 270),
+		Sugars:       newLinearScale(0, 1.5, 3, 4.5, 6, 7.5, 9, 10.5, 12, 13.5),
+		SaturatedFat: newLinearScale(1, 2, 3, 4, 5, 6, 7, 8, 9, 10),
+		Sodium:       newLinearScale(90, 180, 270, 360, 450, 540, 630, 720, 810, 900),
+		Fruits:       PointScale{Thresholds: []float64{40, 60, 80}, Points: []int{2, 4, 10}},
+		Fibre:        newLinearScale(0.9, 1.9, 2.8, 3.7, 4.7),
+		Protein:      newLinearScale(1.6, 3.2, 4.8, 6.4, 8.0),
+		// Grade A is reserved for water, which is handled before grading
+		Grades: []GradeBoundary{
+			{Grade: "B", MaxScore: 1},
+			{Grade: "C", MaxScore: 5},
+			{Grade: "D", MaxScore: 9},
+			{Grade: "E"},
+		},
+	}
+}
+
+// newAlgorithm2017FatsOils returns the 2017 algorithm for added fats
+// Saturated fat is scored on its share of total fat rather than on absolute grams
+func newAlgorithm2017FatsOils() *Algorithm {
+	algorithm := newAlgorithm2017()
+	algorithm.Version = AlgorithmVersion2017FatsOils
+	algorithm.Description = "Original Nutri-Score algorithm for added fats (2017)"
+	algorithm.ScoreTypes = []models.ScoreType{models.FatsOilsType}
+	algorithm.SaturatedFatRatio = true
+	// A ratio of exactly 10% already scores 1 point
+	algorithm.SaturatedFat = newInclusiveScale(10, 16, 22, 28, 34, 40, 46, 52, 58, 64)
+	return algorithm
+}
+
+// newAlgorithm2023Food returns the 2023 revision for solid foods
+// Sugars and salt use longer scales, protein and fibre are rescaled,
+// red meat products can earn at most 2 protein points and protein is
+// excluded from 11 negative points on regardless of the fruit content
+func newAlgorithm2023Food() *Algorithm {
+	return &Algorithm{
+		Version:     AlgorithmVersion2023Food,
+		Family:      AlgorithmFamily2023,
+		Description: "Nutri-Score 2023 revision for foods",
+		ScoreTypes:  []models.ScoreType{models.FoodType, models.CheeseType},
+		Energy:      newLinearScale(335, 670, 1005, 1340, 1675, 2010, 2345, 2680, 3015, 3350),
+		Sugars: newLinearScale(3.4, 6.8, 10, 14, 17, 20, 24, 27, 31, 34,
+			37, 41, 44, 48, 51),
+		SaturatedFat: newLinearScale(1, 2, 3, 4, 5, 6, 7, 8, 9, 10),
+		// Salt thresholds in 0.2g steps expressed as sodium (1g salt = 400mg sodium)
+		Sodium: newLinearScale(80, 160, 240, 320, 400, 480, 560, 640, 720, 800,
+			880, 960, 1040, 1120, 1200, 1280, 1360, 1440, 1520, 1600),
+		Fruits:            PointScale{Thresholds: []float64{40, 60, 80}, Points: []int{1, 2, 5}},
+		Fibre:             newLinearScale(3.0, 4.1, 5.2, 6.3, 7.4),
+		Protein:           newLinearScale(2.4, 4.8, 7.2, 9.6, 12, 14, 17),
+		RedMeatProteinCap: 2,
+
+		// The 2023 revision dropped the fruit exemption
+		ProteinExclusionThreshold: 11,
+
+		Grades: []GradeBoundary{
+			{Grade: "A", MaxScore: 0},
+			{Grade: "B", MaxScore: 2},
+			{Grade: "C", MaxScore: 10},
+			{Grade: "D", MaxScore: 18},
+			{Grade: "E"},
+		},
+	}
+}
+
+// newAlgorithm2023Beverage returns the 2023 revision for beverages
+// Energy and sugars use beverage-specific scales and only water can be graded A
+func newAlgorithm2023Beverage() *Algorithm {
+	return &Algorithm{
+		Version:      AlgorithmVersion2023Beverage,
+		Family:       AlgorithmFamily2023,
+		Description:  "Nutri-Score 2023 revision for beverages",
+		ScoreTypes:   []models.ScoreType{models.BeverageType, models.WaterType},
+		Energy:       newLinearScale(30, 90, 150, 210, 240, 270, 300, 330, 360, 390),
+		Sugars:       newLinearScale(0.5, 2, 3.5, 5, 6, 7, 8, 9, 10, 11),
+		SaturatedFat: newLinearScale(1, 2, 3, 4, 5, 6, 7, 8, 9, 10),
+		// Salt thresholds in 0.2g steps expressed as sodium (1g salt = 400mg sodium)
+		Sodium: newLinearScale(80, 160, 240, 320, 400, 480, 560, 640, 720, 800,
+			880, 960, 1040, 1120, 1200, 1280, 1360, 1440, 1520, 1600),
+		Fruits:  PointScale{Thresholds: []float64{40, 60, 80}, Points: []int{2, 4, 6}},
+		Fibre:   newLinearScale(3.0, 4.1, 5.2, 6.3, 7.4),
+		Protein: newLinearScale(1.2, 1.5, 1.8, 2.1, 2.4, 2.7, 3.0),
+		// Beverages count fibre and protein as well as fruit
+		BeveragePositives: true,
+		// Non-nutritive sweeteners add 4 negative points
+		SweetenerPenalty: 4,
+		// Grade A is reserved for water, which is handled before grading
+		Grades: []GradeBoundary{
+			{Grade: "B", MaxScore: 2},
+			{Grade: "C", MaxScore: 6},
+			{Grade: "D", MaxScore: 9},
+			{Grade: "E"},
+		},
+	}
+}
+
+// newAlgorithm2023FatsOils returns the 2023 revision for fats, oils, nuts and seeds
+// Energy is replaced by the energy from saturated fat (120 kJ steps), saturated fat
+// is scored on its share of total fat, protein is excluded from 7 negative points on
+// and the A boundary is moved down to -6
+func newAlgorithm2023FatsOils() *Algorithm {
+	algorithm := newAlgorithm2023Food()
+	algorithm.Version = AlgorithmVersion2023FatsOils
+	algorithm.Description = "Nutri-Score 2023 revision for fats, oils, nuts and seeds"
+	algorithm.ScoreTypes = []models.ScoreType{models.FatsOilsType}
+	algorithm.EnergyFromSaturates = true
+	algorithm.Energy = newLinearScale(120, 240, 360, 480, 600, 720, 840, 960, 1080, 1200)
+	algorithm.SaturatedFatRatio = true
+	algorithm.SaturatedFat = newInclusiveScale(10, 16, 22, 28, 34, 40, 46, 52, 58, 64)
+	algorithm.ProteinExclusionThreshold = 7
+	algorithm.Grades = []GradeBoundary{
+		{Grade: "A", MaxScore: -6},
+		{Grade: "B", MaxScore: 2},
+		{Grade: "C", MaxScore: 10},
+		{Grade: "D", MaxScore: 18},
+		{Grade: "E"},
+	}
+	return algorithm
+}
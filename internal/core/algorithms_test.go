@@ -0,0 +1,336 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// TestPointScale_Score tests point assignment at and around scale thresholds
+func TestPointScale_Score(t *testing.T) {
+	linear := newLinearScale(1, 2, 3)
+	fruits := PointScale{Thresholds: []float64{40, 60, 80}, Points: []int{1, 2, 5}}
+	inclusive := newInclusiveScale(10, 16)
+
+	tests := []struct {
+		name     string
+		scale    PointScale
+		value    float64
+		expected int
+	}{
+		{"Linear - Below First Threshold", linear, 0.5, 0},
+		{"Linear - At First Threshold", linear, 1, 0},
+		{"Linear - Just Above First Threshold", linear, 1.1, 1},
+		{"Linear - Above Last Threshold", linear, 10, 3},
+		{"Custom Points - At Threshold", fruits, 60, 1},
+		{"Custom Points - Above Last Threshold", fruits, 81, 5},
+		{"Inclusive - Below First Threshold", inclusive, 9.9, 0},
+		{"Inclusive - At First Threshold", inclusive, 10, 1},
+		{"Inclusive - At Last Threshold", inclusive, 16, 2},
+		{"Empty Scale", PointScale{}, 100, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.scale.Score(tt.value); result != tt.expected {
+				t.Errorf("Score(%v) = %d, want %d", tt.value, result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestAlgorithmRegistry_Resolve tests lookup by exact version and by family
+func TestAlgorithmRegistry_Resolve(t *testing.T) {
+	registry := DefaultAlgorithmRegistry()
+
+	tests := []struct {
+		name      string
+		version   string
+		scoreType models.ScoreType
+		expected  string
+		wantErr   bool
+	}{
+		{"Exact 2017", "2017", models.FoodType, AlgorithmVersion2017, false},
+		{"2017 Beverage Uses Family Member", "2017", models.BeverageType, AlgorithmVersion2017Beverage, false},
+		{"Family Switch From Exact Version", "2023-food", models.BeverageType, AlgorithmVersion2023Beverage, false},
+		{"Exact 2023 Food", "2023-food", models.FoodType, AlgorithmVersion2023Food, false},
+		{"Family 2023 - Food", "2023", models.FoodType, AlgorithmVersion2023Food, false},
+		{"Family 2023 - Cheese", "2023", models.CheeseType, AlgorithmVersion2023Food, false},
+		{"Family 2023 - Beverage", "2023", models.BeverageType, AlgorithmVersion2023Beverage, false},
+		{"Unknown Version", "1999", models.FoodType, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			algorithm, err := registry.Resolve(tt.version, tt.scoreType)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && algorithm.Version != tt.expected {
+				t.Errorf("Resolve() version = %s, want %s", algorithm.Version, tt.expected)
+			}
+		})
+	}
+}
+
+// TestAlgorithmRegistry_Register tests that duplicate versions are rejected
+func TestAlgorithmRegistry_Register(t *testing.T) {
+	registry := NewAlgorithmRegistry()
+
+	if err := registry.Register(newAlgorithm2017()); err != nil {
+		t.Fatalf("Register() unexpected error: %v", err)
+	}
+	if err := registry.Register(newAlgorithm2017()); err == nil {
+		t.Error("Register() should reject a duplicate version")
+	}
+	if err := registry.Register(&Algorithm{}); err == nil {
+		t.Error("Register() should reject an algorithm without a version")
+	}
+}
+
+// TestNewNutritionalScorerWithVersion tests scorer construction with versions and families
+func TestNewNutritionalScorerWithVersion(t *testing.T) {
+	for _, version := range []string{"2017", "2023", "2023-food", "2023-beverage"} {
+		if _, err := NewNutritionalScorerWithVersion(version); err != nil {
+			t.Errorf("NewNutritionalScorerWithVersion(%q) unexpected error: %v", version, err)
+		}
+	}
+
+	if _, err := NewNutritionalScorerWithVersion("2030"); err == nil {
+		t.Error("NewNutritionalScorerWithVersion() should reject an unknown version")
+	}
+}
+
+// TestCalculateScore_AlgorithmVersions tests that the 2023 revision changes scoring
+// and that every result records the version that produced it
+func TestCalculateScore_AlgorithmVersions(t *testing.T) {
+	scorer2017 := NewNutritionalScorer()
+	scorer2023, err := NewNutritionalScorerWithVersion(AlgorithmFamily2023)
+	if err != nil {
+		t.Fatalf("NewNutritionalScorerWithVersion() unexpected error: %v", err)
+	}
+
+	t.Run("Version Is Recorded", func(t *testing.T) {
+		data := models.NutritionalData{Energy: 1000, Sugars: 10, Sodium: 200}
+		for _, tc := range []struct {
+			scorer    *NutritionalScorer
+			scoreType models.ScoreType
+			expected  string
+		}{
+			{scorer2017, models.FoodType, AlgorithmVersion2017},
+			{scorer2017, models.BeverageType, AlgorithmVersion2017Beverage},
+			{scorer2017, models.WaterType, AlgorithmVersion2017Beverage},
+			{scorer2023, models.FoodType, AlgorithmVersion2023Food},
+			{scorer2023, models.BeverageType, AlgorithmVersion2023Beverage},
+			{scorer2023, models.WaterType, AlgorithmVersion2023Beverage},
+		} {
+			result, err := tc.scorer.CalculateScore(data, tc.scoreType)
+			if err != nil {
+				t.Fatalf("CalculateScore() unexpected error: %v", err)
+			}
+			if result.AlgorithmVersion != tc.expected {
+				t.Errorf("%s: AlgorithmVersion = %s, want %s", tc.scoreType, result.AlgorithmVersion, tc.expected)
+			}
+		}
+	})
+
+	t.Run("Sugar And Salt Scales", func(t *testing.T) {
+		// 20g sugars: 4 points in 2017, 5 points in 2023
+		// 1000mg sodium: 10 points in 2017, 12 points in 2023
+		data := models.NutritionalData{Sugars: 20, Sodium: 1000}
+		result2017, _ := scorer2017.CalculateScore(data, models.FoodType)
+		result2023, _ := scorer2023.CalculateScore(data, models.FoodType)
+		if result2017.Negative != 14 {
+			t.Errorf("2017 negative points = %d, want 14", result2017.Negative)
+		}
+		if result2023.Negative != 17 {
+			t.Errorf("2023 negative points = %d, want 17", result2023.Negative)
+		}
+	})
+
+	t.Run("Red Meat Protein Cap", func(t *testing.T) {
+		beef := models.NutritionalData{Energy: 600, SaturatedFattyAcids: 1, Sodium: 60, Protein: 26, RedMeat: true}
+		result, _ := scorer2023.CalculateScore(beef, models.FoodType)
+		if result.Positive != 2 {
+			t.Errorf("2023 red meat positive points = %d, want 2", result.Positive)
+		}
+
+		// The 2017 algorithm has no cap
+		result, _ = scorer2017.CalculateScore(beef, models.FoodType)
+		if result.Positive != 5 {
+			t.Errorf("2017 red meat positive points = %d, want 5", result.Positive)
+		}
+	})
+
+	t.Run("Grade Boundaries", func(t *testing.T) {
+		// A score of 0 is B in 2017 but A in the 2023 food algorithm
+		if grade := scorer2017.GetScoreGrade(0); grade != "B" {
+			t.Errorf("2017 GetScoreGrade(0) = %s, want B", grade)
+		}
+		if grade := scorer2023.GetScoreGrade(0); grade != "A" {
+			t.Errorf("2023 GetScoreGrade(0) = %s, want A", grade)
+		}
+	})
+
+	t.Run("Beverages Cannot Reach Grade A", func(t *testing.T) {
+		flavouredWater := models.NutritionalData{Energy: 0, Sugars: 0, Fruits: 100}
+		result, err := scorer2023.CalculateScore(flavouredWater, models.BeverageType)
+		if err != nil {
+			t.Fatalf("CalculateScore() unexpected error: %v", err)
+		}
+		if result.Grade != "B" {
+			t.Errorf("2023 beverage grade = %s, want B (score: %d)", result.Grade, result.Value)
+		}
+	})
+}
+
+// TestCalculateScore_FatsOils tests saturated fat ratio scoring for fats and oils
+func TestCalculateScore_FatsOils(t *testing.T) {
+	oliveOil := models.NutritionalData{
+		Energy:              models.EnergyKJ(3701),
+		SaturatedFattyAcids: models.SaturatedFattyAcids(13.8),
+		TotalFat:            models.TotalFatGram(100),
+		Sodium:              models.SodiumMilligram(2),
+	}
+	butter := models.NutritionalData{
+		Energy:              models.EnergyKJ(3000),
+		Sugars:              models.SugarGram(0.1),
+		SaturatedFattyAcids: models.SaturatedFattyAcids(51.4),
+		TotalFat:            models.TotalFatGram(81.1),
+		Sodium:              models.SodiumMilligram(643),
+		Protein:             models.ProteinGram(0.9),
+	}
+	// Saturated fat at exactly 10% of total fat is the first ratio point
+	tenPercent := models.NutritionalData{
+		Energy:              models.EnergyKJ(3700),
+		SaturatedFattyAcids: models.SaturatedFattyAcids(10),
+		TotalFat:            models.TotalFatGram(100),
+	}
+	belowTenPercent := tenPercent
+	belowTenPercent.SaturatedFattyAcids = 9.9
+
+	scorer2017 := NewNutritionalScorer()
+	scorer2023, _ := NewNutritionalScorerWithVersion(AlgorithmFamily2023)
+
+	tests := []struct {
+		name           string
+		scorer         *NutritionalScorer
+		data           models.NutritionalData
+		scoreType      models.ScoreType
+		expectedValue  int
+		expectedGrade  string
+		expectedSatFat int
+		version        string
+	}{
+		{"Olive Oil As Food", scorer2017, oliveOil, models.FoodType, 20, "E", 10, AlgorithmVersion2017},
+		{"Olive Oil As Fat", scorer2017, oliveOil, models.FatsOilsType, 11, "D", 1, AlgorithmVersion2017FatsOils},
+		{"Butter As Fat", scorer2017, butter, models.FatsOilsType, 24, "E", 9, AlgorithmVersion2017FatsOils},
+		{"Ratio Exactly 10%", scorer2017, tenPercent, models.FatsOilsType, 11, "D", 1, AlgorithmVersion2017FatsOils},
+		{"Ratio Just Below 10%", scorer2017, belowTenPercent, models.FatsOilsType, 10, "C", 0, AlgorithmVersion2017FatsOils},
+		{"Olive Oil As Fat - 2023", scorer2023, oliveOil, models.FatsOilsType, 5, "C", 1, AlgorithmVersion2023FatsOils},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.scorer.CalculateScore(tt.data, tt.scoreType)
+			if err != nil {
+				t.Fatalf("CalculateScore() unexpected error: %v", err)
+			}
+			if result.Value != tt.expectedValue || result.Grade != tt.expectedGrade {
+				t.Errorf("CalculateScore() = %d (%s), want %d (%s)", result.Value, result.Grade, tt.expectedValue, tt.expectedGrade)
+			}
+			if result.Breakdown.SaturatedFat.Points != tt.expectedSatFat {
+				t.Errorf("Saturated fat points = %d, want %d", result.Breakdown.SaturatedFat.Points, tt.expectedSatFat)
+			}
+			if result.AlgorithmVersion != tt.version {
+				t.Errorf("AlgorithmVersion = %s, want %s", result.AlgorithmVersion, tt.version)
+			}
+		})
+	}
+
+	t.Run("Total Fat Required", func(t *testing.T) {
+		data := oliveOil
+		data.TotalFat = 0
+		if _, err := scorer2017.CalculateScore(data, models.FatsOilsType); err == nil {
+			t.Error("CalculateScore() should require total fat for fats and oils")
+		}
+	})
+
+	t.Run("Saturated Fat Exceeds Total Fat", func(t *testing.T) {
+		data := butter
+		data.TotalFat = 40
+		if _, err := scorer2017.CalculateScore(data, models.FatsOilsType); err == nil {
+			t.Error("CalculateScore() should reject saturated fat above total fat")
+		}
+	})
+}
+
+// TestCalculateScore_SweetenerPenalty tests the 2023 beverage penalty for non-nutritive sweeteners
+func TestCalculateScore_SweetenerPenalty(t *testing.T) {
+	dietSoda := models.NutritionalData{Energy: 1, Sodium: 8, NonNutritiveSweeteners: true}
+	scorer2017 := NewNutritionalScorer()
+	scorer2023, _ := NewNutritionalScorerWithVersion(AlgorithmFamily2023)
+
+	tests := []struct {
+		name           string
+		scorer         *NutritionalScorer
+		data           models.NutritionalData
+		scoreType      models.ScoreType
+		expectedPoints int
+		expectedValue  int
+		expectedGrade  string
+	}{
+		{"2023 Diet Soda", scorer2023, dietSoda, models.BeverageType, 4, 4, "C"},
+		{"2023 Without Sweeteners", scorer2023, models.NutritionalData{Energy: 1, Sodium: 8}, models.BeverageType, 0, 0, "B"},
+		{"2017 Has No Penalty", scorer2017, dietSoda, models.BeverageType, 0, 1, "B"},
+		{"Foods Are Not Penalised", scorer2023, dietSoda, models.FoodType, 0, 0, "A"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.scorer.CalculateScore(tt.data, tt.scoreType)
+			if err != nil {
+				t.Fatalf("CalculateScore() unexpected error: %v", err)
+			}
+			if result.Breakdown.SweetenerPoints != tt.expectedPoints {
+				t.Errorf("SweetenerPoints = %d, want %d", result.Breakdown.SweetenerPoints, tt.expectedPoints)
+			}
+			if result.Value != tt.expectedValue || result.Grade != tt.expectedGrade {
+				t.Errorf("CalculateScore() = %d (%s), want %d (%s)", result.Value, result.Grade, tt.expectedValue, tt.expectedGrade)
+			}
+		})
+	}
+}
+
+// TestCalculateScore_BeveragePositives tests that 2023 beverages count fibre and protein
+func TestCalculateScore_BeveragePositives(t *testing.T) {
+	// A sugared milk drink: 2 energy points and 3 sugar points
+	plain := models.NutritionalData{Energy: 100, Sugars: 4, SaturatedFattyAcids: 0.5, Sodium: 40}
+	withProtein := plain
+	withProtein.Protein = 3.5
+	scorer2017 := NewNutritionalScorer()
+	scorer2023, _ := NewNutritionalScorerWithVersion(AlgorithmFamily2023)
+
+	tests := []struct {
+		name          string
+		scorer        *NutritionalScorer
+		data          models.NutritionalData
+		expectedValue int
+	}{
+		{"2023 Without Protein", scorer2023, plain, 5},
+		{"2023 With Protein", scorer2023, withProtein, -2},
+		{"2017 Ignores Protein", scorer2017, withProtein, 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.scorer.CalculateScore(tt.data, models.BeverageType)
+			if err != nil {
+				t.Fatalf("CalculateScore() unexpected error: %v", err)
+			}
+			if result.Value != tt.expectedValue {
+				t.Errorf("CalculateScore() = %d, want %d (breakdown %+v)", result.Value, tt.expectedValue, result.Breakdown)
+			}
+		})
+	}
+}
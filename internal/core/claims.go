@@ -0,0 +1,218 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// kilojoulesPerGramProtein is the energy conversion factor for protein (EU Regulation 1169/2011)
+const kilojoulesPerGramProtein = 17
+
+// claimCondition is one condition of a nutrition claim
+// AtLeast conditions are met from the limit up, the others up to the limit
+type claimCondition struct {
+	label   string
+	value   float64
+	limit   float64
+	unit    string
+	atLeast bool
+}
+
+// margin returns how far the value is inside the limit
+func (cc claimCondition) margin() float64 {
+	if cc.atLeast {
+		return roundDistance(cc.value - cc.limit)
+	}
+	return roundDistance(cc.limit - cc.value)
+}
+
+// relativeMargin compares margins of conditions with different units
+func (cc claimCondition) relativeMargin() float64 {
+	if cc.limit == 0 {
+		return cc.margin()
+	}
+	return cc.margin() / cc.limit
+}
+
+// rule describes the condition ("fat ≤ 3 g per 100g")
+func (cc claimCondition) rule() string {
+	operator := "≤"
+	if cc.atLeast {
+		operator = "≥"
+	}
+	return fmt.Sprintf("%s %s %s%s", cc.label, operator, formatAmount(cc.limit), unitSuffix(cc.unit))
+}
+
+// unitSuffix separates a unit from its amount, except for percentages ("3 g", "10% of energy")
+func unitSuffix(unit string) string {
+	if strings.HasPrefix(unit, "%") {
+		return unit
+	}
+	return " " + unit
+}
+
+// nutritionClaim is a claim from the Annex of Regulation 1924/2006
+// All requires every condition to be met, otherwise one condition is enough
+type nutritionClaim struct {
+	claim      string
+	nutrient   string
+	all        bool
+	conditions []claimCondition
+}
+
+// result decides the claim and reports the condition that decided it
+// A met "any" claim reports its best condition, a failed one its closest condition;
+// an "all" claim reports its worst condition
+func (nc nutritionClaim) result() models.ClaimResult {
+	deciding := nc.conditions[0]
+	for _, condition := range nc.conditions[1:] {
+		if nc.all && condition.relativeMargin() < deciding.relativeMargin() ||
+			!nc.all && condition.relativeMargin() > deciding.relativeMargin() {
+			deciding = condition
+		}
+	}
+
+	return models.ClaimResult{
+		Claim:    nc.claim,
+		Nutrient: nc.nutrient,
+		Eligible: deciding.margin() >= 0,
+		Rule:     deciding.rule(),
+		Value:    roundDistance(deciding.value),
+		Limit:    deciding.limit,
+		Unit:     deciding.unit,
+		Margin:   deciding.margin(),
+	}
+}
+
+// ClaimChecker checks which nutrition claims of Regulation (EC) 1924/2006 a food qualifies for
+type ClaimChecker struct {
+	validator models.InputValidator
+}
+
+// NewClaimChecker creates a checker for the nutrition claims listed in the regulation's Annex
+func NewClaimChecker() *ClaimChecker {
+	return &ClaimChecker{validator: NewInputValidator()}
+}
+
+// Check evaluates every nutrition claim that applies to the food
+// Liquids use the per 100ml conditions; claims that need the energy content are
+// skipped when no energy is declared
+func (cc *ClaimChecker) Check(food models.Food) (models.ClaimReport, error) {
+	data := food.NutritionalData
+	validation := cc.validator.CheckFoodData(food)
+	if validation.HasErrors() {
+		return models.ClaimReport{}, validation
+	}
+	if err := requireKnownValues(data); err != nil {
+		return models.ClaimReport{}, err
+	}
+	// The fat claims need total fat, which can never be lower than saturated fat
+	if data.TotalFat <= 0 && data.SaturatedFattyAcids > 0 {
+		return models.ClaimReport{}, models.ValidationError{
+			Field:   "total_fat",
+			Value:   float64(data.TotalFat),
+			Message: "Total fat is required for nutrition claims",
+		}
+	}
+	data = data.ResolveSodium()
+
+	liquid := food.IsLiquid()
+	report := models.ClaimReport{
+		FoodID:     food.ID,
+		Regulation: "Regulation (EC) No 1924/2006",
+		Liquid:     liquid,
+	}
+	for _, claim := range nutritionClaims(data, liquid) {
+		report.Claims = append(report.Claims, claim.result())
+	}
+
+	return report, nil
+}
+
+// nutritionClaims builds the claims of the regulation's Annex for the data
+func nutritionClaims(data models.NutritionalData, liquid bool) []nutritionClaim {
+	// solidOrLiquid picks the per 100g or per 100ml limit
+	solidOrLiquid := func(solid, perMillilitre float64) float64 {
+		if liquid {
+			return perMillilitre
+		}
+		return solid
+	}
+	grams := per100Unit("g", liquid)
+	energy := float64(data.Energy)
+	saturates := float64(data.SaturatedFattyAcids) + float64(data.TransFat)
+	sodium := float64(data.Sodium)
+
+	energyCondition := func(limit float64) claimCondition {
+		return claimCondition{label: "energy", value: energy, limit: limit, unit: per100Unit("kJ", liquid)}
+	}
+	fatCondition := func(limit float64) claimCondition {
+		return claimCondition{label: "fat", value: float64(data.TotalFat), limit: limit, unit: grams}
+	}
+	saturatesCondition := func(limit float64) claimCondition {
+		return claimCondition{label: "saturated + trans fat", value: saturates, limit: limit, unit: grams}
+	}
+	sugarsCondition := func(limit float64) claimCondition {
+		return claimCondition{label: "sugars", value: float64(data.Sugars), limit: limit, unit: grams}
+	}
+	sodiumCondition := func(limit float64) claimCondition {
+		return claimCondition{label: "sodium", value: sodium, limit: limit, unit: per100Unit("mg", liquid)}
+	}
+
+	claims := []nutritionClaim{
+		{claim: "LOW ENERGY", nutrient: "energy", conditions: []claimCondition{energyCondition(solidOrLiquid(170, 80))}},
+		{claim: "LOW FAT", nutrient: "total_fat", conditions: []claimCondition{fatCondition(solidOrLiquid(3, 1.5))}},
+		{claim: "FAT-FREE", nutrient: "total_fat", conditions: []claimCondition{fatCondition(0.5)}},
+		{claim: "SATURATED FAT-FREE", nutrient: "saturated_fat", conditions: []claimCondition{saturatesCondition(0.1)}},
+		{claim: "LOW SUGARS", nutrient: "sugars", conditions: []claimCondition{sugarsCondition(solidOrLiquid(5, 2.5))}},
+		{claim: "SUGARS-FREE", nutrient: "sugars", conditions: []claimCondition{sugarsCondition(0.5)}},
+		{claim: "LOW SODIUM/SALT", nutrient: "sodium", conditions: []claimCondition{sodiumCondition(120)}},
+		{claim: "VERY LOW SODIUM/SALT", nutrient: "sodium", conditions: []claimCondition{sodiumCondition(40)}},
+		{claim: "SODIUM-FREE or SALT-FREE", nutrient: "sodium", conditions: []claimCondition{sodiumCondition(5)}},
+	}
+
+	// Energy-free is only defined for liquids (4 kcal per 100ml)
+	if liquid {
+		claims = append(claims, nutritionClaim{claim: "ENERGY-FREE", nutrient: "energy",
+			conditions: []claimCondition{energyCondition(17)}})
+	}
+
+	// Low saturated fat also limits the energy from saturated and trans fat to 10%
+	lowSaturates := nutritionClaim{claim: "LOW SATURATED FAT", nutrient: "saturated_fat", all: true,
+		conditions: []claimCondition{saturatesCondition(solidOrLiquid(1.5, 0.75))}}
+	if energy > 0 {
+		lowSaturates.conditions = append(lowSaturates.conditions, claimCondition{label: "energy from saturated + trans fat",
+			value: saturates * kilojoulesPerGramFat / energy * 100, limit: 10, unit: "% of energy"})
+	}
+	claims = append(claims, lowSaturates)
+
+	// Fibre claims accept either the amount per 100g or per 100 kcal
+	fibreConditions := func(amount, perEnergy float64) []claimCondition {
+		conditions := []claimCondition{{label: "fibre", value: float64(data.Fibre), limit: amount, unit: grams, atLeast: true}}
+		if energy > 0 {
+			conditions = append(conditions, claimCondition{label: "fibre", value: float64(data.Fibre) / kilocalories(data) * 100,
+				limit: perEnergy, unit: "g per 100 kcal", atLeast: true})
+		}
+		return conditions
+	}
+	claims = append(claims,
+		nutritionClaim{claim: "SOURCE OF FIBRE", nutrient: "fibre", conditions: fibreConditions(3, 1.5)},
+		nutritionClaim{claim: "HIGH FIBRE", nutrient: "fibre", conditions: fibreConditions(6, 3)},
+	)
+
+	// Protein claims are based on the share of energy provided by protein
+	if energy > 0 {
+		proteinShare := float64(data.Protein) * kilojoulesPerGramProtein / energy * 100
+		proteinCondition := func(limit float64) claimCondition {
+			return claimCondition{label: "energy from protein", value: proteinShare, limit: limit, unit: "% of energy", atLeast: true}
+		}
+		claims = append(claims,
+			nutritionClaim{claim: "SOURCE OF PROTEIN", nutrient: "protein", conditions: []claimCondition{proteinCondition(12)}},
+			nutritionClaim{claim: "HIGH PROTEIN", nutrient: "protein", conditions: []claimCondition{proteinCondition(20)}},
+		)
+	}
+
+	return claims
+}
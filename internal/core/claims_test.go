@@ -0,0 +1,147 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// findClaim returns the result of a claim from a report
+func findClaim(t *testing.T, report models.ClaimReport, claim string) models.ClaimResult {
+	t.Helper()
+	for _, result := range report.Claims {
+		if result.Claim == claim {
+			return result
+		}
+	}
+	t.Fatalf("claim %q not evaluated", claim)
+	return models.ClaimResult{}
+}
+
+// TestClaimChecker_Check tests eligibility, rules and margins of the nutrition claims
+func TestClaimChecker_Check(t *testing.T) {
+	checker := NewClaimChecker()
+	oats := models.Food{ID: "oats", NutritionalData: models.NutritionalData{
+		Energy: 1560, TotalFat: 7, SaturatedFattyAcids: 1.3, Sugars: 1, Sodium: 5, Fibre: 10, Protein: 13}}
+
+	report, err := checker.Check(oats)
+	if err != nil {
+		t.Fatalf("Check() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		claim    string
+		eligible bool
+		rule     string
+		margin   float64
+	}{
+		{"LOW ENERGY", false, "energy ≤ 170 kJ per 100g", -1390},
+		{"LOW FAT", false, "fat ≤ 3 g per 100g", -4},
+		{"LOW SUGARS", true, "sugars ≤ 5 g per 100g", 4},
+		{"SUGARS-FREE", false, "sugars ≤ 0.5 g per 100g", -0.5},
+		{"LOW SODIUM/SALT", true, "sodium ≤ 120 mg per 100g", 115},
+		{"SODIUM-FREE or SALT-FREE", true, "sodium ≤ 5 mg per 100g", 0},
+		// Both conditions are met; the tighter one is reported
+		{"LOW SATURATED FAT", true, "saturated + trans fat ≤ 1.5 g per 100g", 0.2},
+		// Met per 100g, not per 100 kcal; the met condition is reported
+		{"HIGH FIBRE", true, "fibre ≥ 6 g per 100g", 4},
+		{"SOURCE OF PROTEIN", true, "energy from protein ≥ 12% of energy", 2.1667},
+		{"HIGH PROTEIN", false, "energy from protein ≥ 20% of energy", -5.8333},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.claim, func(t *testing.T) {
+			result := findClaim(t, report, tt.claim)
+			if result.Eligible != tt.eligible {
+				t.Errorf("Eligible = %v, want %v", result.Eligible, tt.eligible)
+			}
+			if result.Rule != tt.rule {
+				t.Errorf("Rule = %q, want %q", result.Rule, tt.rule)
+			}
+			if result.Margin != tt.margin {
+				t.Errorf("Margin = %v, want %v", result.Margin, tt.margin)
+			}
+		})
+	}
+
+	for _, claim := range report.Claims {
+		if claim.Claim == "ENERGY-FREE" {
+			t.Error("ENERGY-FREE only applies to liquids")
+		}
+	}
+	if eligible := report.EligibleClaims(); len(eligible) != 8 {
+		t.Errorf("EligibleClaims() returned %d claims, want 8", len(eligible))
+	}
+}
+
+// TestClaimChecker_Alternatives tests liquids, fibre per 100 kcal and undeclared energy
+func TestClaimChecker_Alternatives(t *testing.T) {
+	checker := NewClaimChecker()
+
+	t.Run("Liquid", func(t *testing.T) {
+		report, err := checker.Check(models.Food{Category: "Beverages", NutritionalData: models.NutritionalData{Energy: 1, Sodium: 8}})
+		if err != nil {
+			t.Fatalf("Check() unexpected error: %v", err)
+		}
+		if !report.Liquid {
+			t.Error("Beverages should be checked per 100ml")
+		}
+		if result := findClaim(t, report, "ENERGY-FREE"); !result.Eligible || result.Rule != "energy ≤ 17 kJ per 100ml" {
+			t.Errorf("ENERGY-FREE = %+v, want eligible per 100ml", result)
+		}
+		if result := findClaim(t, report, "LOW FAT"); result.Limit != 1.5 {
+			t.Errorf("LOW FAT limit = %v, want 1.5 per 100ml", result.Limit)
+		}
+	})
+
+	t.Run("Beverage Profile", func(t *testing.T) {
+		syrup := models.Food{Category: "Beverages", NutritionalData: models.NutritionalData{Energy: 1500, Sugars: 85}}
+		if _, err := checker.Check(syrup); err == nil || !strings.Contains(err.Error(), "Energy cannot exceed 1000.0 kJ") {
+			t.Errorf("Check() error = %v, want the beverage energy limit", err)
+		}
+	})
+
+	t.Run("Fibre Per 100 kcal", func(t *testing.T) {
+		// 1.3g fibre in 14.3 kcal is 9.07g per 100 kcal
+		report, err := checker.Check(models.Food{NutritionalData: models.NutritionalData{Energy: 60, Fibre: 1.3, Protein: 1.4}})
+		if err != nil {
+			t.Fatalf("Check() unexpected error: %v", err)
+		}
+		result := findClaim(t, report, "HIGH FIBRE")
+		if !result.Eligible || result.Unit != "g per 100 kcal" {
+			t.Errorf("HIGH FIBRE = %+v, want eligible per 100 kcal", result)
+		}
+	})
+
+	t.Run("No Energy Declared", func(t *testing.T) {
+		report, err := checker.Check(models.Food{NutritionalData: models.NutritionalData{Protein: 10}})
+		if err != nil {
+			t.Fatalf("Check() unexpected error: %v", err)
+		}
+		for _, claim := range report.Claims {
+			if claim.Nutrient == "protein" {
+				t.Errorf("%s needs the energy content", claim.Claim)
+			}
+		}
+		if result := findClaim(t, report, "LOW SATURATED FAT"); result.Unit != "g per 100g" {
+			t.Errorf("LOW SATURATED FAT unit = %s, want g per 100g", result.Unit)
+		}
+	})
+
+	t.Run("Invalid Data", func(t *testing.T) {
+		if _, err := checker.Check(models.Food{NutritionalData: models.NutritionalData{Sugars: -2}}); err == nil {
+			t.Error("Check() should reject invalid data")
+		}
+	})
+
+	t.Run("Total Fat Undeclared", func(t *testing.T) {
+		// A cheese with saturated fat but no total fat must not pass as LOW FAT or FAT-FREE
+		cheese := models.Food{ID: "cheese", NutritionalData: models.NutritionalData{
+			Energy: 1650, SaturatedFattyAcids: 20, Sugars: 0.5, Sodium: 620, Protein: 25}}
+		_, err := checker.Check(cheese)
+		if validationErr, ok := err.(models.ValidationError); !ok || validationErr.Field != "total_fat" {
+			t.Errorf("Check() error = %v, want a total_fat validation error", err)
+		}
+	})
+}
@@ -0,0 +1,150 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// diaryDateFormat is the format of the dates diary entries are grouped by
+const diaryDateFormat = "2006-01-02"
+
+// AnalyzeDiaryEntry scores the food of a diary entry
+// The serving size of the analysis is the amount eaten. Entries logged as food are
+// scored as beverages when the food is a liquid
+func AnalyzeDiaryEntry(scorer models.NutritionalScorer, entry models.DiaryEntry, food models.Food) (models.NutritionalAnalysis, error) {
+	if entry.Grams <= 0 {
+		return models.NutritionalAnalysis{}, models.NewValidationError("grams",
+			fmt.Sprintf("Diary entry %s must weigh more than 0 g", entry.ID))
+	}
+
+	scoreType := entry.ScoreType
+	if scoreType == models.FoodType && food.IsLiquid() {
+		scoreType = models.BeverageType
+	}
+	score, err := scorer.CalculateScore(food.NutritionalData, scoreType)
+	if err != nil {
+		return models.NutritionalAnalysis{}, err
+	}
+
+	return models.NutritionalAnalysis{
+		ID:          entry.ID,
+		Food:        food,
+		Score:       score,
+		AnalyzedAt:  entry.EatenAt,
+		Notes:       entry.Notes,
+		ServingSize: entry.Grams,
+	}, nil
+}
+
+// SummarizeDiary scores diary entries and aggregates them per meal and per calendar day
+// The foods map holds the referenced foods keyed by food ID. Days are returned in
+// chronological order, using the time zone each entry was logged in
+func SummarizeDiary(scorer models.NutritionalScorer, entries []models.DiaryEntry, foods map[string]models.Food) ([]models.DailySummary, error) {
+	days := make(map[string][]models.DiaryEntry)
+	for _, entry := range entries {
+		date := entry.EatenAt.Format(diaryDateFormat)
+		days[date] = append(days[date], entry)
+	}
+
+	dates := make([]string, 0, len(days))
+	for date := range days {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	summaries := make([]models.DailySummary, 0, len(dates))
+	for _, date := range dates {
+		summary, err := summarizeDay(scorer, date, days[date], foods)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, nil
+}
+
+// summarizeDay aggregates the entries of one day for the whole day and per meal
+func summarizeDay(scorer models.NutritionalScorer, date string, entries []models.DiaryEntry, foods map[string]models.Food) (models.DailySummary, error) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].EatenAt.Before(entries[j].EatenAt)
+	})
+
+	summary := models.DailySummary{Date: date}
+	meals := make(map[models.MealType][]models.NutritionalAnalysis)
+	var mealOrder []models.MealType
+	for _, entry := range entries {
+		food, found := foods[entry.FoodID]
+		if !found {
+			return models.DailySummary{}, models.NewValidationError("food_id",
+				fmt.Sprintf("Food %s of diary entry %s was not found", entry.FoodID, entry.ID))
+		}
+		analysis, err := AnalyzeDiaryEntry(scorer, entry, food)
+		if err != nil {
+			return models.DailySummary{}, err
+		}
+
+		summary.Analyses = append(summary.Analyses, analysis)
+		if _, seen := meals[entry.Meal]; !seen {
+			mealOrder = append(mealOrder, entry.Meal)
+		}
+		meals[entry.Meal] = append(meals[entry.Meal], analysis)
+	}
+
+	// Known meals are reported in the order of the day, others in the order they were eaten
+	rank := func(meal models.MealType) int {
+		for i, known := range models.Meals {
+			if meal == known {
+				return i
+			}
+		}
+		return len(models.Meals)
+	}
+	sort.SliceStable(mealOrder, func(i, j int) bool {
+		return rank(mealOrder[i]) < rank(mealOrder[j])
+	})
+
+	summary.Total = summarizeIntake("", summary.Analyses)
+	for _, meal := range mealOrder {
+		summary.Meals = append(summary.Meals, summarizeIntake(meal, meals[meal]))
+	}
+
+	return summary, nil
+}
+
+// summarizeIntake adds up the amounts eaten and averages the scores weighted by energy
+// When none of the entries provided energy the scores are weighted by the amount eaten
+func summarizeIntake(meal models.MealType, analyses []models.NutritionalAnalysis) models.IntakeSummary {
+	summary := models.IntakeSummary{Meal: meal, Entries: len(analyses)}
+
+	energy, energyWeightedScore, gramWeightedScore := 0.0, 0.0, 0.0
+	for _, analysis := range analyses {
+		eaten := scaleNutrients(analysis.Food.NutritionalData.ResolveSodium(), analysis.ServingSize/100)
+		summary.Intake = addNutrients(summary.Intake, eaten)
+		summary.Grams += analysis.ServingSize
+
+		energy += float64(eaten.Energy)
+		energyWeightedScore += float64(analysis.Score.Value) * float64(eaten.Energy)
+		gramWeightedScore += float64(analysis.Score.Value) * analysis.ServingSize
+	}
+	if summary.Grams == 0 {
+		return summary
+	}
+
+	summary.Density = roundNutrients(scaleNutrients(summary.Intake, 100/summary.Grams))
+	summary.Intake = roundNutrients(summary.Intake)
+	summary.Grams = roundDistance(summary.Grams)
+	summary.Intake.SodiumDeclaredAs = models.DeclaredAsSodium
+	summary.Density.SodiumDeclaredAs = models.DeclaredAsSodium
+
+	if energy > 0 {
+		summary.AverageScore = roundDistance(energyWeightedScore / energy)
+		summary.EnergyWeighted = true
+	} else {
+		summary.AverageScore = roundDistance(gramWeightedScore / summary.Grams)
+	}
+
+	return summary
+}
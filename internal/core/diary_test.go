@@ -0,0 +1,117 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// diaryFoods are the foods referenced by the diary tests
+var diaryFoods = map[string]models.Food{
+	"oats":   recipeIngredients["oats"],
+	"banana": recipeIngredients["banana"],
+	"cola": {ID: "cola", Category: "Beverages", NutritionalData: models.NutritionalData{
+		Energy: 180, Sugars: 10.6, Sodium: 4}},
+	"water": {ID: "water", Liquid: true, NutritionalData: models.NutritionalData{}},
+}
+
+// TestAnalyzeDiaryEntry tests the serving size and score type of diary entry analyses
+func TestAnalyzeDiaryEntry(t *testing.T) {
+	scorer := NewNutritionalScorer()
+	entry := models.DiaryEntry{ID: "e1", FoodID: "cola", Grams: 330, Meal: models.Lunch}
+
+	analysis, err := AnalyzeDiaryEntry(scorer, entry, diaryFoods["cola"])
+	if err != nil {
+		t.Fatalf("AnalyzeDiaryEntry() unexpected error: %v", err)
+	}
+	if analysis.ServingSize != 330 {
+		t.Errorf("ServingSize = %v, want 330", analysis.ServingSize)
+	}
+	if analysis.Score.ScoreType != models.BeverageType {
+		t.Errorf("ScoreType = %v, want Beverage for a liquid", analysis.Score.ScoreType)
+	}
+
+	entry.Grams = 0
+	if _, err := AnalyzeDiaryEntry(scorer, entry, diaryFoods["cola"]); err == nil {
+		t.Error("AnalyzeDiaryEntry() expected error for an empty amount, got nil")
+	}
+}
+
+// TestSummarizeDiary tests daily and per-meal aggregates of diary entries
+func TestSummarizeDiary(t *testing.T) {
+	scorer := NewNutritionalScorer()
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	entries := []models.DiaryEntry{
+		{ID: "snack", FoodID: "water", Grams: 500, Meal: models.Snack, EatenAt: day.Add(15 * time.Hour)},
+		{ID: "oats", FoodID: "oats", Grams: 50, Meal: models.Breakfast, EatenAt: day.Add(8 * time.Hour)},
+		{ID: "banana", FoodID: "banana", Grams: 100, Meal: models.Breakfast, EatenAt: day.Add(8 * time.Hour)},
+		{ID: "cola", FoodID: "cola", Grams: 200, Meal: models.Lunch, EatenAt: day.Add(13 * time.Hour)},
+		{ID: "next-day", FoodID: "oats", Grams: 40, Meal: models.Breakfast, EatenAt: day.Add(32 * time.Hour)},
+	}
+
+	summaries, err := SummarizeDiary(scorer, entries, diaryFoods)
+	if err != nil {
+		t.Fatalf("SummarizeDiary() unexpected error: %v", err)
+	}
+	if len(summaries) != 2 {
+		t.Fatalf("SummarizeDiary() returned %d days, want 2", len(summaries))
+	}
+	if summaries[0].Date != "2025-03-10" || summaries[1].Date != "2025-03-11" {
+		t.Errorf("dates = %s, %s, want 2025-03-10, 2025-03-11", summaries[0].Date, summaries[1].Date)
+	}
+
+	first := summaries[0]
+	if len(first.Analyses) != 4 {
+		t.Errorf("Analyses = %d, want 4", len(first.Analyses))
+	}
+	var meals []models.MealType
+	for _, meal := range first.Meals {
+		meals = append(meals, meal.Meal)
+	}
+	if len(meals) != 3 || meals[0] != models.Breakfast || meals[1] != models.Lunch || meals[2] != models.Snack {
+		t.Errorf("meals = %v, want [breakfast lunch snack]", meals)
+	}
+
+	// Breakfast: 750 kJ from oats and 370 kJ from banana in 150 g
+	breakfast := first.Meals[0]
+	if breakfast.Grams != 150 || breakfast.Intake.Energy != 1120 {
+		t.Errorf("breakfast = %v g, %v kJ, want 150 g, 1120 kJ", breakfast.Grams, breakfast.Intake.Energy)
+	}
+	if breakfast.Density.Energy != 746.6667 {
+		t.Errorf("breakfast density = %v kJ per 100g, want 746.6667", breakfast.Density.Energy)
+	}
+	if breakfast.Intake.Fruits != 100 || breakfast.Density.Fruits != 66.6667 {
+		t.Errorf("breakfast fruit = %v g (%v%%), want 100 g (66.6667%%)", breakfast.Intake.Fruits, breakfast.Density.Fruits)
+	}
+
+	oatsScore := first.Analyses[0].Score.Value
+	bananaScore := first.Analyses[1].Score.Value
+	wantBreakfast := roundDistance(float64(oatsScore*750+bananaScore*370) / 1120)
+	if !breakfast.EnergyWeighted || breakfast.AverageScore != wantBreakfast {
+		t.Errorf("breakfast average = %v (energy weighted %v), want %v", breakfast.AverageScore, breakfast.EnergyWeighted, wantBreakfast)
+	}
+
+	// Water provides no energy, so its meal falls back to weighting by grams
+	snack := first.Meals[2]
+	if snack.EnergyWeighted {
+		t.Error("snack average should be weighted by grams")
+	}
+	if snack.AverageScore != float64(first.Analyses[3].Score.Value) {
+		t.Errorf("snack average = %v, want %d", snack.AverageScore, first.Analyses[3].Score.Value)
+	}
+
+	// The whole day adds up every meal
+	if first.Total.Entries != 4 || first.Total.Grams != 850 || first.Total.Intake.Energy != 1480 {
+		t.Errorf("total = %d entries, %v g, %v kJ, want 4, 850 g, 1480 kJ",
+			first.Total.Entries, first.Total.Grams, first.Total.Intake.Energy)
+	}
+}
+
+// TestSummarizeDiary_MissingFood tests entries referencing unknown foods
+func TestSummarizeDiary_MissingFood(t *testing.T) {
+	entries := []models.DiaryEntry{{ID: "e1", FoodID: "kale", Grams: 80, Meal: models.Dinner}}
+	if _, err := SummarizeDiary(NewNutritionalScorer(), entries, diaryFoods); err == nil {
+		t.Error("SummarizeDiary() expected error for an unknown food, got nil")
+	}
+}
@@ -0,0 +1,208 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// Explain computes the score and describes how every nutrient contributed to it
+// Each component states its points, the band it fell into and the nearest change that gains a point
+func (ns *NutritionalScorer) Explain(data models.NutritionalData, foodType models.ScoreType) (models.ScoreExplanation, error) {
+	result, err := ns.CalculateScore(data, foodType)
+	if err != nil {
+		return models.ScoreExplanation{}, err
+	}
+
+	algorithm, err := ns.registry.Get(result.AlgorithmVersion)
+	if err != nil {
+		return models.ScoreExplanation{}, err
+	}
+
+	return ExplainScore(result, algorithm, data), nil
+}
+
+// ExplainScore builds the explanation of a score produced by the given algorithm
+// The nutritional data is only used to describe how the sodium value was declared
+func ExplainScore(result models.NutritionalScore, algorithm *Algorithm, data models.NutritionalData) models.ScoreExplanation {
+	explanation := models.ScoreExplanation{
+		Value:            result.Value,
+		Grade:            result.Grade,
+		ScoreType:        result.ScoreType,
+		AlgorithmVersion: result.AlgorithmVersion,
+	}
+
+	if result.ScoreType == models.WaterType {
+		explanation.Summary = fmt.Sprintf("Water always scores 0 (%s)", ratingText(result))
+		explanation.Narrative = explanation.Summary
+		return explanation
+	}
+
+	breakdown := result.Breakdown
+	// Beverages only count fruit points unless the algorithm counts every positive component for them
+	fruitOnly := breakdown.FruitOnly
+
+	energyLabel, energyUnit := "Energy", "kJ"
+	if algorithm.EnergyFromSaturates {
+		energyLabel = "Energy from saturated fat"
+	}
+	satFatLabel, satFatUnit := "Saturated fat", "g"
+	if algorithm.SaturatedFatRatio {
+		satFatLabel, satFatUnit = "Saturated fat ratio", "% of total fat"
+	}
+
+	components := []struct {
+		nutrient string
+		label    string
+		unit     string
+		negative bool
+		counted  bool
+		score    models.ComponentScore
+		scale    PointScale
+	}{
+		{"energy", energyLabel, energyUnit, true, true, breakdown.Energy, algorithm.Energy},
+		{"sugars", "Sugars", "g", true, true, breakdown.Sugars, algorithm.Sugars},
+		{"saturated_fat", satFatLabel, satFatUnit, true, true, breakdown.SaturatedFat, algorithm.SaturatedFat},
+		{"sodium", "Sodium", "mg", true, true, breakdown.Sodium, algorithm.Sodium},
+		{"fruits", "Fruits, vegetables and nuts", "%", false, true, breakdown.Fruits, algorithm.Fruits},
+		{"fibre", "Fibre", "g", false, !fruitOnly, breakdown.Fibre, algorithm.Fibre},
+		{"protein", "Protein", "g", false, !fruitOnly && !breakdown.ProteinExcluded, breakdown.Protein, algorithm.Protein},
+	}
+
+	for _, c := range components {
+		component := models.ComponentExplanation{
+			Nutrient: c.nutrient,
+			Label:    c.label,
+			Unit:     c.unit,
+			Negative: c.negative,
+			Counted:  c.counted,
+			Score:    c.score,
+		}
+		if c.counted {
+			component.Improvement = nextImprovement(c.scale, c.score, c.negative)
+		}
+		component.Text = componentText(component)
+		explanation.Components = append(explanation.Components, component)
+	}
+
+	explanation.Notes = scoreNotes(result, data.ResolveSodium(), fruitOnly)
+	explanation.Summary = fmt.Sprintf("Score %d (%s) with algorithm %s: %d negative points, %d positive points",
+		result.Value, ratingText(result), result.AlgorithmVersion, result.Negative, result.Positive)
+
+	lines := []string{explanation.Summary}
+	for _, component := range explanation.Components {
+		lines = append(lines, component.Text)
+	}
+	lines = append(lines, explanation.Notes...)
+	explanation.Narrative = strings.Join(lines, "\n")
+
+	return explanation
+}
+
+// nextImprovement finds the nearest value that gains at least one point
+// Negative nutrients must drop to the lower bound of their band (below it for inclusive
+// bands), positive nutrients must rise above the upper bound of theirs
+func nextImprovement(scale PointScale, score models.ComponentScore, negative bool) *models.ScoreImprovement {
+	if negative {
+		if score.Points == 0 {
+			return nil
+		}
+		target := score.BandLower
+		if score.BandInclusive {
+			target = math.Nextafter(target, math.Inf(-1))
+		}
+		gain := score.Points - scale.Score(target)
+		return &models.ScoreImprovement{TargetValue: score.BandLower, PointsGain: gain}
+	}
+
+	if score.BandUpper == nil {
+		return nil
+	}
+	gain := scale.Score(math.Nextafter(*score.BandUpper, math.Inf(1))) - score.Points
+	if gain <= 0 {
+		return nil
+	}
+	return &models.ScoreImprovement{TargetValue: *score.BandUpper, PointsGain: gain}
+}
+
+// componentText renders a component explanation as a sentence
+// e.g. "Sugars 22 g scored 4/10 (band 18–22.5 g); reducing to 18 g would gain 1 point"
+func componentText(component models.ComponentExplanation) string {
+	score := component.Score
+	text := fmt.Sprintf("%s %s %s scored %d/%d (%s)", component.Label, formatAmount(score.Value), component.Unit,
+		score.Points, score.MaxPoints, bandText(score, component.Unit))
+
+	if !component.Counted {
+		return text + "; not counted towards the score"
+	}
+
+	if improvement := component.Improvement; improvement != nil {
+		verb := "reducing to"
+		if component.Score.BandInclusive {
+			verb = "reducing below"
+		}
+		if !component.Negative {
+			verb = "increasing above"
+		}
+		text += fmt.Sprintf("; %s %s %s would gain %s", verb, formatAmount(improvement.TargetValue),
+			component.Unit, pluralPoints(improvement.PointsGain))
+	}
+
+	return text
+}
+
+// bandText describes the threshold band a component fell into
+func bandText(score models.ComponentScore, unit string) string {
+	if score.BandUpper == nil {
+		return fmt.Sprintf("band above %s %s", formatAmount(score.BandLower), unit)
+	}
+	return fmt.Sprintf("band %s–%s %s", formatAmount(score.BandLower), formatAmount(*score.BandUpper), unit)
+}
+
+// scoreNotes lists the rules that changed how the points were combined
+func scoreNotes(result models.NutritionalScore, data models.NutritionalData, fruitOnly bool) []string {
+	var notes []string
+
+	if result.Breakdown.ProteinExcluded {
+		notes = append(notes, fmt.Sprintf("Protein points are not counted because the product has %d negative points",
+			result.Breakdown.NegativePoints()))
+	}
+	if fruitOnly {
+		notes = append(notes, fmt.Sprintf("Beverages scored with algorithm %s only count their fruit, vegetable and nut points",
+			result.AlgorithmVersion))
+	}
+	if result.Breakdown.SweetenerPoints > 0 {
+		notes = append(notes, fmt.Sprintf("Non-nutritive sweeteners add %s",
+			pluralPoints(result.Breakdown.SweetenerPoints)))
+	}
+	if data.SodiumDeclaredAs == models.DeclaredAsSalt {
+		notes = append(notes, fmt.Sprintf("Sodium was derived from %s g salt (salt = sodium x %s)",
+			formatAmount(float64(data.Salt)), formatAmount(models.SaltToSodiumFactor)))
+	}
+
+	return notes
+}
+
+// ratingText describes the rating of a score in the terms of its scheme ("grade B", "3.5 stars")
+func ratingText(result models.NutritionalScore) string {
+	if result.Scheme == models.SchemeHealthStarRating {
+		return fmt.Sprintf("%s stars", formatAmount(result.Stars))
+	}
+	return fmt.Sprintf("grade %s", result.Grade)
+}
+
+// formatAmount formats a nutrient amount without trailing zeros
+func formatAmount(value float64) string {
+	return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+}
+
+// pluralPoints formats a number of points ("1 point", "3 points")
+func pluralPoints(points int) string {
+	if points == 1 {
+		return "1 point"
+	}
+	return fmt.Sprintf("%d points", points)
+}
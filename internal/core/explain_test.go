@@ -0,0 +1,142 @@
+package core
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// TestNutritionalScorer_Explain tests the narrative and structured explanation of a score
+func TestNutritionalScorer_Explain(t *testing.T) {
+	scorer := NewNutritionalScorer()
+	data := models.NutritionalData{
+		Energy:              models.EnergyKJ(1500),
+		Sugars:              models.SugarGram(22),
+		SaturatedFattyAcids: models.SaturatedFattyAcids(3),
+		Sodium:              models.SodiumMilligram(80),
+		Fruits:              models.FruitsPercent(45),
+		Fibre:               models.FibreGram(2),
+		Protein:             models.ProteinGram(4),
+	}
+
+	explanation, err := scorer.Explain(data, models.FoodType)
+	if err != nil {
+		t.Fatalf("Explain() unexpected error: %v", err)
+	}
+
+	result, _ := scorer.CalculateScore(data, models.FoodType)
+	if explanation.Value != result.Value || explanation.Grade != result.Grade {
+		t.Errorf("Explain() = %d (%s), want %d (%s)", explanation.Value, explanation.Grade, result.Value, result.Grade)
+	}
+	if len(explanation.Components) != 7 {
+		t.Fatalf("Explain() components = %d, want 7", len(explanation.Components))
+	}
+
+	sugars := explanation.Components[1]
+	expectedText := "Sugars 22 g scored 4/10 (band 18–22.5 g); reducing to 18 g would gain 1 point"
+	if sugars.Text != expectedText {
+		t.Errorf("Sugars text = %q, want %q", sugars.Text, expectedText)
+	}
+	if sugars.Improvement == nil || sugars.Improvement.TargetValue != 18 || sugars.Improvement.PointsGain != 1 {
+		t.Errorf("Sugars improvement = %+v, want target 18 gaining 1 point", sugars.Improvement)
+	}
+
+	fruits := explanation.Components[4]
+	if fruits.Improvement == nil || fruits.Improvement.TargetValue != 60 || fruits.Improvement.PointsGain != 1 {
+		t.Errorf("Fruits improvement = %+v, want above 60 gaining 1 point", fruits.Improvement)
+	}
+
+	// Components that already score best have no improvement
+	if explanation.Components[3].Improvement != nil {
+		t.Error("Sodium in the lowest band should have no improvement")
+	}
+
+	if !strings.Contains(explanation.Narrative, expectedText) || !strings.HasPrefix(explanation.Narrative, explanation.Summary) {
+		t.Errorf("Narrative should contain the summary and the component texts, got %q", explanation.Narrative)
+	}
+
+	// The structured form carries the same content
+	encoded, err := json.Marshal(explanation)
+	if err != nil {
+		t.Fatalf("json.Marshal() unexpected error: %v", err)
+	}
+	var decoded models.ScoreExplanation
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() unexpected error: %v", err)
+	}
+	if decoded.Narrative != explanation.Narrative || decoded.Components[1].Text != expectedText {
+		t.Error("Explanation should round-trip through JSON")
+	}
+}
+
+// TestNutritionalScorer_ExplainRules tests the notes for rules that change the score
+func TestNutritionalScorer_ExplainRules(t *testing.T) {
+	scorer := NewNutritionalScorer()
+	scorer2023, _ := NewNutritionalScorerWithVersion(AlgorithmFamily2023)
+
+	t.Run("Protein Excluded", func(t *testing.T) {
+		data := models.NutritionalData{Energy: 2000, Sugars: 20, SaturatedFattyAcids: 8, Sodium: 600, Protein: 10}
+		explanation, err := scorer.Explain(data, models.FoodType)
+		if err != nil {
+			t.Fatalf("Explain() unexpected error: %v", err)
+		}
+		protein := explanation.Components[6]
+		if protein.Counted || !strings.HasSuffix(protein.Text, "not counted towards the score") {
+			t.Errorf("Protein should not be counted, got %q", protein.Text)
+		}
+		if len(explanation.Notes) == 0 || !strings.Contains(explanation.Notes[0], "Protein points are not counted") {
+			t.Errorf("Notes should mention the protein exclusion, got %v", explanation.Notes)
+		}
+	})
+
+	t.Run("Beverage With Sweeteners", func(t *testing.T) {
+		data := models.NutritionalData{Energy: 1, Salt: 0.02, NonNutritiveSweeteners: true}
+		explanation, err := scorer2023.Explain(data, models.BeverageType)
+		if err != nil {
+			t.Fatalf("Explain() unexpected error: %v", err)
+		}
+		if !explanation.Components[5].Counted || !explanation.Components[6].Counted {
+			t.Error("2023 beverages should count fibre and protein")
+		}
+		notes := strings.Join(explanation.Notes, "\n")
+		if strings.Contains(notes, "only count their fruit") {
+			t.Errorf("2023 beverage notes should not mention the fruit-only rule, got %v", explanation.Notes)
+		}
+		for _, expected := range []string{"sweeteners add 4 points", "derived from 0.02 g salt"} {
+			if !strings.Contains(notes, expected) {
+				t.Errorf("Notes should contain %q, got %v", expected, explanation.Notes)
+			}
+		}
+	})
+
+	t.Run("2017 Beverage", func(t *testing.T) {
+		explanation, err := scorer.Explain(models.NutritionalData{Energy: 1, Protein: 3}, models.BeverageType)
+		if err != nil {
+			t.Fatalf("Explain() unexpected error: %v", err)
+		}
+		if explanation.Components[5].Counted || explanation.Components[6].Counted {
+			t.Error("2017 beverages should not count fibre or protein")
+		}
+		if !strings.Contains(strings.Join(explanation.Notes, "\n"), "only count their fruit") {
+			t.Errorf("Notes should mention the fruit-only rule, got %v", explanation.Notes)
+		}
+	})
+
+	t.Run("Water", func(t *testing.T) {
+		explanation, err := scorer.Explain(models.NutritionalData{}, models.WaterType)
+		if err != nil {
+			t.Fatalf("Explain() unexpected error: %v", err)
+		}
+		if explanation.Grade != "A" || len(explanation.Components) != 0 || explanation.Narrative == "" {
+			t.Errorf("Water explanation = %+v, want grade A without components", explanation)
+		}
+	})
+
+	t.Run("Invalid Data", func(t *testing.T) {
+		if _, err := scorer.Explain(models.NutritionalData{Energy: -1}, models.FoodType); err == nil {
+			t.Error("Explain() should return validation errors")
+		}
+	})
+}
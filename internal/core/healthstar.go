@@ -0,0 +1,270 @@
+package core
+
+import (
+	"strconv"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// HSRCategory identifies a Health Star Rating food category
+type HSRCategory string
+
+// Health Star Rating categories
+const (
+	HSRCategory1  HSRCategory = "1"  // Non-dairy beverages
+	HSRCategory1D HSRCategory = "1D" // Dairy beverages
+	HSRCategory2  HSRCategory = "2"  // Foods other than those in categories 1, 1D, 2D, 3 and 3D
+	HSRCategory2D HSRCategory = "2D" // Dairy foods other than those in category 3D
+	HSRCategory3  HSRCategory = "3"  // Oils and spreads
+	HSRCategory3D HSRCategory = "3D" // Cheese and processed cheese with calcium above 320mg per 100g
+)
+
+// HSRCategories lists all Health Star Rating categories
+var HSRCategories = []HSRCategory{HSRCategory1, HSRCategory1D, HSRCategory2, HSRCategory2D, HSRCategory3, HSRCategory3D}
+
+// HSRAlgorithmFamily groups the Health Star Rating calculator versions (as revised in 2020)
+const HSRAlgorithmFamily = "hsr-2020"
+
+// HSRAlgorithmVersion returns the algorithm version used for an HSR category
+func HSRAlgorithmVersion(category HSRCategory) string {
+	return HSRAlgorithmFamily + "-" + string(category)
+}
+
+// HSRCategoryForScoreType maps a score type onto its default HSR category
+// Dairy categories cannot be told apart by score type; use CalculateCategoryScore for them
+func HSRCategoryForScoreType(scoreType models.ScoreType) HSRCategory {
+	switch scoreType {
+	case models.BeverageType, models.WaterType:
+		return HSRCategory1
+	case models.CheeseType:
+		return HSRCategory3D
+	case models.FatsOilsType:
+		return HSRCategory3
+	default:
+		return HSRCategory2
+	}
+}
+
+// HealthStarRatingScorer implements the Australian/New Zealand Health Star Rating
+// Baseline points (energy, saturated fat, sugars, sodium) minus modifying points
+// (fruit/vegetable, protein, fibre) are mapped onto 0.5 to 5 stars per category
+type HealthStarRatingScorer struct {
+	registry  *AlgorithmRegistry
+	validator models.InputValidator
+}
+
+// NewHealthStarRatingScorer creates a scorer using the 2020 Health Star Rating calculator
+func NewHealthStarRatingScorer() *HealthStarRatingScorer {
+	registry := NewAlgorithmRegistry()
+	for _, algorithm := range hsrAlgorithms() {
+		if err := registry.Register(algorithm); err != nil {
+			panic(err)
+		}
+	}
+
+	return &HealthStarRatingScorer{
+		registry:  registry,
+		validator: NewInputValidator(),
+	}
+}
+
+// CalculateScore computes the Health Star Rating using the default category of the score type
+func (hs *HealthStarRatingScorer) CalculateScore(data models.NutritionalData, foodType models.ScoreType) (models.NutritionalScore, error) {
+	return hs.calculate(data, foodType, HSRCategoryForScoreType(foodType))
+}
+
+// CalculateCategoryScore computes the Health Star Rating for an explicit HSR category
+// Use this for dairy products (categories 1D and 2D)
+func (hs *HealthStarRatingScorer) CalculateCategoryScore(data models.NutritionalData, category HSRCategory) (models.NutritionalScore, error) {
+	scoreType := models.FoodType
+	switch category {
+	case HSRCategory1, HSRCategory1D:
+		scoreType = models.BeverageType
+	case HSRCategory3:
+		scoreType = models.FatsOilsType
+	case HSRCategory3D:
+		scoreType = models.CheeseType
+	}
+	return hs.calculate(data, scoreType, category)
+}
+
+// calculate scores the data with the algorithm of the HSR category
+func (hs *HealthStarRatingScorer) calculate(data models.NutritionalData, foodType models.ScoreType, category HSRCategory) (models.NutritionalScore, error) {
+	validation := hs.validator.CheckNutritionalDataFor(data, foodType)
+	if validation.HasErrors() {
+		return models.NutritionalScore{}, validation
+	}
+
+	if err := requireKnownValues(data); err != nil {
+		return models.NutritionalScore{}, err
+	}
+
+	algorithm, err := hs.registry.Get(HSRAlgorithmVersion(category))
+	if err != nil {
+		return models.NutritionalScore{}, err
+	}
+
+	// Plain water automatically receives 5 stars
+	if foodType == models.WaterType {
+		return models.NutritionalScore{
+			Grade:            "5",
+			Stars:            5,
+			ScoreType:        foodType,
+			AlgorithmVersion: algorithm.Version,
+			Scheme:           models.SchemeHealthStarRating,
+			Warnings:         validation.GetWarnings(),
+		}, nil
+	}
+
+	// HSR has no beverage or cheese specific combination rules, so the breakdown is
+	// always combined like a regular food: baseline points minus modifying points
+	calculator := NewScoreCalculatorWithAlgorithm(algorithm)
+	breakdown := calculator.CalculateBreakdown(data, models.FoodType)
+	finalScore := breakdown.NegativePoints() - breakdown.PositivePoints()
+	grade := algorithm.Grade(finalScore)
+
+	return models.NutritionalScore{
+		Value:            finalScore,
+		Grade:            grade,
+		Stars:            starsFromGrade(grade),
+		Positive:         breakdown.PositivePoints(),
+		Negative:         breakdown.NegativePoints(),
+		ScoreType:        foodType,
+		AlgorithmVersion: algorithm.Version,
+		Scheme:           models.SchemeHealthStarRating,
+		Breakdown:        breakdown,
+		Warnings:         validation.GetWarnings(),
+	}, nil
+}
+
+// Explain computes the Health Star Rating and describes how every nutrient contributed
+func (hs *HealthStarRatingScorer) Explain(data models.NutritionalData, foodType models.ScoreType) (models.ScoreExplanation, error) {
+	result, err := hs.CalculateScore(data, foodType)
+	if err != nil {
+		return models.ScoreExplanation{}, err
+	}
+
+	algorithm, err := hs.registry.Get(result.AlgorithmVersion)
+	if err != nil {
+		return models.ScoreExplanation{}, err
+	}
+
+	return ExplainScore(result, algorithm, data), nil
+}
+
+// ValidateNutritionalData checks if nutritional data is within acceptable ranges
+func (hs *HealthStarRatingScorer) ValidateNutritionalData(data models.NutritionalData) []models.ValidationError {
+	return hs.validator.ValidateNutritionalData(data)
+}
+
+// GetScoreGrade converts a category 2 (general foods) score into a star rating
+func (hs *HealthStarRatingScorer) GetScoreGrade(score int) string {
+	algorithm, err := hs.registry.Get(HSRAlgorithmVersion(HSRCategory2))
+	if err != nil {
+		return ""
+	}
+	return algorithm.Grade(score)
+}
+
+// GetScoreThresholds returns the highest score for every star rating per score type
+// The lowest rating (0.5 stars) maps to its lowest score
+func (hs *HealthStarRatingScorer) GetScoreThresholds() map[models.ScoreType]map[string]int {
+	thresholds := make(map[models.ScoreType]map[string]int)
+	for _, scoreType := range []models.ScoreType{models.FoodType, models.BeverageType, models.CheeseType, models.FatsOilsType} {
+		algorithm, err := hs.registry.Get(HSRAlgorithmVersion(HSRCategoryForScoreType(scoreType)))
+		if err != nil {
+			continue
+		}
+		thresholds[scoreType] = algorithm.Thresholds()
+	}
+	return thresholds
+}
+
+// starsFromGrade converts a star rating grade ("3.5") into its numeric value
+func starsFromGrade(grade string) float64 {
+	stars, err := strconv.ParseFloat(grade, 64)
+	if err != nil {
+		return 0
+	}
+	return stars
+}
+
+// hsrStarBoundaries builds the star rating boundaries from 5 stars down to 1 star
+// Scores above the last boundary receive 0.5 stars
+func hsrStarBoundaries(maxScores ...int) []GradeBoundary {
+	stars := []string{"5", "4.5", "4", "3.5", "3", "2.5", "2", "1.5", "1"}
+	boundaries := make([]GradeBoundary, 0, len(stars)+1)
+	for i, maxScore := range maxScores {
+		boundaries = append(boundaries, GradeBoundary{Grade: stars[i], MaxScore: maxScore})
+	}
+	return append(boundaries, GradeBoundary{Grade: "0.5"})
+}
+
+// hsrAlgorithms returns the Health Star Rating calculator for every category
+// Point tables follow the HSR Guide for Industry as revised in 2020
+func hsrAlgorithms() []*Algorithm {
+	// Baseline points shared by all categories, extended for high fat and high sodium products
+	energy := newLinearScale(335, 670, 1005, 1340, 1675, 2010, 2345, 2680, 3015, 3350)
+	saturatedFat := newLinearScale(1, 2, 3, 4, 5, 6, 7, 8, 9, 10,
+		11.2, 12.5, 14, 15.7, 17.6, 19.7, 22, 24.6, 27.5, 30.8,
+		34.5, 38.6, 43.2, 48.3, 54, 60.4, 67.6, 75.6, 84.5, 94.5)
+	sugars := newLinearScale(5, 8.9, 12.8, 16.8, 20.7, 24.6, 28.5, 32.4, 36.3, 40.3,
+		44.2, 48.1, 52, 55.9, 59.8, 63.8, 67.7, 71.6, 75.5, 79.4,
+		83.3, 87.3, 91.2, 95.1, 99)
+	sodium := newLinearScale(90, 180, 270, 360, 450, 540, 630, 720, 810, 900,
+		990, 1080, 1170, 1260, 1350, 1440, 1530, 1620, 1710, 1800,
+		1890, 1980, 2070, 2160, 2250, 2340, 2430, 2520, 2610, 2700)
+
+	// Modifying points
+	fruits := PointScale{Thresholds: []float64{40, 60, 67, 80, 95}, Points: []int{1, 2, 5, 8, 10}}
+	protein := newLinearScale(1.6, 3.2, 4.8, 6.4, 8.0, 9.6, 11.6, 13.9, 16.7, 20.0,
+		24.0, 28.9, 34.7, 41.6, 50.0)
+	fibre := newLinearScale(0.9, 1.9, 2.8, 3.7, 4.7, 5.4, 6.3, 7.3, 8.4, 9.7,
+		11.2, 13.0, 15.1, 17.5, 20.3)
+
+	newCategory := func(category HSRCategory, description string, scoreType models.ScoreType, grades []GradeBoundary) *Algorithm {
+		return &Algorithm{
+			Version:      HSRAlgorithmVersion(category),
+			Family:       HSRAlgorithmFamily,
+			Description:  "Health Star Rating category " + string(category) + ": " + description,
+			ScoreTypes:   []models.ScoreType{scoreType},
+			Energy:       energy,
+			Sugars:       sugars,
+			SaturatedFat: saturatedFat,
+			Sodium:       sodium,
+			Fruits:       fruits,
+			Fibre:        fibre,
+			Protein:      protein,
+			// Protein points only count below 13 baseline points, or with 5 or more fruit/vegetable points
+			ProteinExclusionThreshold:      13,
+			ProteinExclusionFruitExemption: 5,
+			Grades:                         grades,
+		}
+	}
+
+	// Beverages use finer energy, sugar and fruit/vegetable scales
+	beverage := newCategory(HSRCategory1, "non-dairy beverages", models.BeverageType,
+		hsrStarBoundaries(-2, 0, 1, 2, 3, 4, 5, 6, 7))
+	beverage.Energy = newLinearScale(30, 90, 150, 210, 240, 270, 300, 330, 360, 390)
+	beverage.Sugars = newLinearScale(0, 1.5, 3, 4.5, 6, 7.5, 9, 10.5, 12, 13.5)
+	beverage.Fruits = newLinearScale(25, 33, 41, 49, 57, 65, 73, 81, 89, 96)
+
+	cheese := newCategory(HSRCategory3D, "cheese and processed cheese", models.CheeseType,
+		hsrStarBoundaries(22, 24, 26, 28, 30, 32, 34, 36, 38))
+	// Cheese always counts its protein
+	cheese.ProteinExclusionThreshold = 0
+	cheese.ProteinExclusionFruitExemption = 0
+
+	return []*Algorithm{
+		beverage,
+		newCategory(HSRCategory1D, "dairy beverages", models.BeverageType,
+			hsrStarBoundaries(-2, -1, 0, 1, 2, 3, 4, 5, 6)),
+		newCategory(HSRCategory2, "foods", models.FoodType,
+			hsrStarBoundaries(-11, -7, -2, 2, 6, 11, 15, 20, 24)),
+		newCategory(HSRCategory2D, "dairy foods", models.FoodType,
+			hsrStarBoundaries(-2, 0, 2, 4, 6, 8, 10, 12, 14)),
+		newCategory(HSRCategory3, "oils and spreads", models.FatsOilsType,
+			hsrStarBoundaries(13, 16, 20, 23, 27, 30, 34, 37, 41)),
+		cheese,
+	}
+}
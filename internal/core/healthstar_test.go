@@ -0,0 +1,164 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// TestHealthStarRatingScorer_CalculateScore tests star ratings for the default categories
+func TestHealthStarRatingScorer_CalculateScore(t *testing.T) {
+	scorer := NewHealthStarRatingScorer()
+
+	tests := []struct {
+		name          string
+		data          models.NutritionalData
+		scoreType     models.ScoreType
+		expectedValue int
+		expectedStars float64
+		version       string
+	}{
+		{
+			name:          "Apple - Category 2",
+			data:          models.NutritionalData{Energy: 218, Sugars: 10.4, SaturatedFattyAcids: 0.1, Sodium: 1, Fruits: 100, Fibre: 2.4, Protein: 0.3},
+			scoreType:     models.FoodType,
+			expectedValue: -10, // 2 baseline points - 10 fruit - 2 fibre
+			expectedStars: 4.5,
+			version:       "hsr-2020-2",
+		},
+		{
+			name:          "Cola - Category 1",
+			data:          models.NutritionalData{Energy: 180, Sugars: 10.6, Sodium: 4},
+			scoreType:     models.BeverageType,
+			expectedValue: 11, // 3 energy + 8 sugars
+			expectedStars: 0.5,
+			version:       "hsr-2020-1",
+		},
+		{
+			name:          "Cheddar - Category 3D",
+			data:          models.NutritionalData{Energy: 1700, Sugars: 0.5, SaturatedFattyAcids: 21, Sodium: 650, Protein: 25},
+			scoreType:     models.CheeseType,
+			expectedValue: 17, // 5 energy + 16 saturated fat + 7 sodium - 11 protein
+			expectedStars: 5,
+			version:       "hsr-2020-3D",
+		},
+		{
+			name:          "Water",
+			data:          models.NutritionalData{},
+			scoreType:     models.WaterType,
+			expectedValue: 0,
+			expectedStars: 5,
+			version:       "hsr-2020-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := scorer.CalculateScore(tt.data, tt.scoreType)
+			if err != nil {
+				t.Fatalf("CalculateScore() unexpected error: %v", err)
+			}
+			if result.Value != tt.expectedValue || result.Stars != tt.expectedStars {
+				t.Errorf("CalculateScore() = %d (%v stars), want %d (%v stars)", result.Value, result.Stars, tt.expectedValue, tt.expectedStars)
+			}
+			if result.Scheme != models.SchemeHealthStarRating {
+				t.Errorf("Scheme = %s, want %s", result.Scheme, models.SchemeHealthStarRating)
+			}
+			if result.AlgorithmVersion != tt.version {
+				t.Errorf("AlgorithmVersion = %s, want %s", result.AlgorithmVersion, tt.version)
+			}
+		})
+	}
+}
+
+// TestHealthStarRatingScorer_Categories tests explicit categories and the protein rule
+func TestHealthStarRatingScorer_Categories(t *testing.T) {
+	scorer := NewHealthStarRatingScorer()
+
+	t.Run("Dairy Food - Category 2D", func(t *testing.T) {
+		yoghurt := models.NutritionalData{Energy: 400, Sugars: 4.5, SaturatedFattyAcids: 2.5, Sodium: 50, Protein: 4}
+		result, err := scorer.CalculateCategoryScore(yoghurt, HSRCategory2D)
+		if err != nil {
+			t.Fatalf("CalculateCategoryScore() unexpected error: %v", err)
+		}
+		// 1 energy + 2 saturated fat - 2 protein = 1
+		if result.Value != 1 || result.Stars != 4 {
+			t.Errorf("CalculateCategoryScore() = %d (%v stars), want 1 (4 stars)", result.Value, result.Stars)
+		}
+	})
+
+	t.Run("Every Category Scores", func(t *testing.T) {
+		data := models.NutritionalData{Energy: 1000, Sugars: 10, SaturatedFattyAcids: 5, Sodium: 300, Protein: 5}
+		for _, category := range HSRCategories {
+			result, err := scorer.CalculateCategoryScore(data, category)
+			if err != nil {
+				t.Fatalf("CalculateCategoryScore(%s) unexpected error: %v", category, err)
+			}
+			if result.Stars < 0.5 || result.Stars > 5 {
+				t.Errorf("CalculateCategoryScore(%s) stars = %v, want 0.5 to 5", category, result.Stars)
+			}
+			if result.AlgorithmVersion != HSRAlgorithmVersion(category) {
+				t.Errorf("AlgorithmVersion = %s, want %s", result.AlgorithmVersion, HSRAlgorithmVersion(category))
+			}
+		}
+	})
+
+	t.Run("Protein Not Counted From 13 Baseline Points", func(t *testing.T) {
+		sausage := models.NutritionalData{Energy: 1200, Sugars: 1, SaturatedFattyAcids: 9, Sodium: 800, Protein: 14}
+		result, err := scorer.CalculateScore(sausage, models.FoodType)
+		if err != nil {
+			t.Fatalf("CalculateScore() unexpected error: %v", err)
+		}
+		if !result.Breakdown.ProteinExcluded || result.Positive != 0 {
+			t.Errorf("Protein should not count with %d baseline points (positive: %d)", result.Negative, result.Positive)
+		}
+	})
+
+	t.Run("Algorithms Are Valid", func(t *testing.T) {
+		ruleset := &Ruleset{Name: "Health Star Rating", Algorithms: hsrAlgorithms()}
+		if err := ruleset.Validate(); err != nil {
+			t.Errorf("HSR algorithms should pass ruleset validation: %v", err)
+		}
+	})
+}
+
+// TestHealthStarRatingScorer_Explain tests that explanations use stars and count all beverage points
+func TestHealthStarRatingScorer_Explain(t *testing.T) {
+	scorer := NewHealthStarRatingScorer()
+	juice := models.NutritionalData{Energy: 180, Sugars: 9, Fruits: 100, Fibre: 1, Protein: 0.5}
+
+	explanation, err := scorer.Explain(juice, models.BeverageType)
+	if err != nil {
+		t.Fatalf("Explain() unexpected error: %v", err)
+	}
+	if !strings.Contains(explanation.Summary, "stars") {
+		t.Errorf("Summary should describe the rating in stars, got %q", explanation.Summary)
+	}
+	if !explanation.Components[5].Counted {
+		t.Error("HSR beverages count their fibre points")
+	}
+}
+
+// TestNewScorer tests the scoring scheme factory
+func TestNewScorer(t *testing.T) {
+	data := models.NutritionalData{Energy: 1000, Sugars: 10, Sodium: 200}
+
+	for _, scheme := range AvailableSchemes() {
+		scorer, err := NewScorer(scheme)
+		if err != nil {
+			t.Fatalf("NewScorer(%q) unexpected error: %v", scheme, err)
+		}
+		result, err := scorer.CalculateScore(data, models.FoodType)
+		if err != nil {
+			t.Fatalf("CalculateScore() unexpected error: %v", err)
+		}
+		if result.Scheme != scheme {
+			t.Errorf("NewScorer(%q) result scheme = %s", scheme, result.Scheme)
+		}
+	}
+
+	if _, err := NewScorer("traffic-lights"); err == nil {
+		t.Error("NewScorer() should reject an unknown scheme")
+	}
+}
@@ -0,0 +1,186 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// UK Nutrient Profiling Model versions
+// Foods and drinks share the point scales but use different "less healthy" cut-offs
+const (
+	UKNPMAlgorithmFamily       = "uk-npm-2004"
+	UKNPMAlgorithmVersionFood  = "uk-npm-2004-food"
+	UKNPMAlgorithmVersionDrink = "uk-npm-2004-drink"
+)
+
+// HFSS grades assigned by the UK Nutrient Profiling Model algorithms
+const (
+	GradeNonHFSS = "non-HFSS"
+	GradeHFSS    = "HFSS"
+)
+
+// HFSSClassifier classifies products as high in fat, salt or sugar (HFSS) with the
+// UK Nutrient Profiling Model 2004/05 used by the UK advertising rules
+type HFSSClassifier struct {
+	registry  *AlgorithmRegistry
+	validator models.InputValidator
+}
+
+// NewHFSSClassifier creates a classifier using the 2004/05 UK Nutrient Profiling Model
+func NewHFSSClassifier() *HFSSClassifier {
+	registry := NewAlgorithmRegistry()
+	for _, algorithm := range ukNPMAlgorithms() {
+		if err := registry.Register(algorithm); err != nil {
+			panic(err)
+		}
+	}
+
+	return &HFSSClassifier{
+		registry:  registry,
+		validator: NewInputValidator(),
+	}
+}
+
+// Classify scores the product and returns whether it is HFSS with the reasons
+// Beverages and water are classified with the drink cut-off
+func (hc *HFSSClassifier) Classify(data models.NutritionalData, scoreType models.ScoreType) (models.HFSSVerdict, error) {
+	validation := hc.validator.CheckNutritionalDataFor(data, scoreType)
+	if validation.HasErrors() {
+		return models.HFSSVerdict{}, validation
+	}
+	if err := requireKnownValues(data); err != nil {
+		return models.HFSSVerdict{}, err
+	}
+
+	drink := scoreType == models.BeverageType || scoreType == models.WaterType
+	version := UKNPMAlgorithmVersionFood
+	if drink {
+		version = UKNPMAlgorithmVersionDrink
+	}
+	algorithm, err := hc.registry.Get(version)
+	if err != nil {
+		return models.HFSSVerdict{}, err
+	}
+
+	// The model has no beverage or cheese specific rules: every product is
+	// scored as A points minus C points
+	calculator := NewScoreCalculatorWithAlgorithm(algorithm)
+	breakdown := calculator.CalculateBreakdown(data, models.FoodType)
+	finalScore := breakdown.NegativePoints() - breakdown.PositivePoints()
+	grade := algorithm.Grade(finalScore)
+
+	verdict := models.HFSSVerdict{
+		Score: models.NutritionalScore{
+			Value:            finalScore,
+			Grade:            grade,
+			Positive:         breakdown.PositivePoints(),
+			Negative:         breakdown.NegativePoints(),
+			ScoreType:        scoreType,
+			AlgorithmVersion: algorithm.Version,
+			Scheme:           models.SchemeUKNPM,
+			Breakdown:        breakdown,
+		},
+		Drink:     drink,
+		Threshold: algorithm.Grades[0].MaxScore + 1,
+		HFSS:      grade == GradeHFSS,
+	}
+	verdict.Compliant = !verdict.HFSS
+	verdict.Reasons = hfssReasons(verdict)
+
+	return verdict, nil
+}
+
+// hfssReasons explains the verdict: the score against the cut-off, then every
+// component that earned points
+func hfssReasons(verdict models.HFSSVerdict) []string {
+	kind := "food"
+	if verdict.Drink {
+		kind = "drink"
+	}
+
+	score := verdict.Score
+	var reasons []string
+	if verdict.HFSS {
+		reasons = append(reasons, fmt.Sprintf("Score %d reaches the %s cut-off of %s: the product is HFSS",
+			score.Value, kind, pluralPoints(verdict.Threshold)))
+	} else {
+		reasons = append(reasons, fmt.Sprintf("Score %d is below the %s cut-off of %s: the product is not HFSS",
+			score.Value, kind, pluralPoints(verdict.Threshold)))
+	}
+
+	breakdown := score.Breakdown
+	components := []struct {
+		label    string
+		unit     string
+		negative bool
+		counted  bool
+		score    models.ComponentScore
+	}{
+		{"Energy", "kJ", true, true, breakdown.Energy},
+		{"Saturated fat", "g", true, true, breakdown.SaturatedFat},
+		{"Sugars", "g", true, true, breakdown.Sugars},
+		{"Sodium", "mg", true, true, breakdown.Sodium},
+		{"Fruits, vegetables and nuts", "%", false, true, breakdown.Fruits},
+		{"Fibre", "g", false, true, breakdown.Fibre},
+		{"Protein", "g", false, !breakdown.ProteinExcluded, breakdown.Protein},
+	}
+	for _, c := range components {
+		if c.score.Points == 0 || !c.counted {
+			continue
+		}
+		sign := "+"
+		if !c.negative {
+			sign = "-"
+		}
+		reasons = append(reasons, fmt.Sprintf("%s %s %s: %s%s", c.label, formatAmount(c.score.Value), c.unit,
+			sign, pluralPoints(c.score.Points)))
+	}
+
+	if breakdown.ProteinExcluded {
+		reasons = append(reasons, fmt.Sprintf("Protein points are not counted because the product has %d A points and fewer than 5 fruit, vegetable and nut points",
+			breakdown.NegativePoints()))
+	}
+
+	return reasons
+}
+
+// ukNPMAlgorithms returns the UK Nutrient Profiling Model for foods and drinks
+// Point tables follow the 2011 technical guidance; fibre uses the AOAC method found on labels
+func ukNPMAlgorithms() []*Algorithm {
+	newModel := func(version, description string, scoreTypes []models.ScoreType, cutOff int) *Algorithm {
+		return &Algorithm{
+			Version:     version,
+			Family:      UKNPMAlgorithmFamily,
+			Description: description,
+			ScoreTypes:  scoreTypes,
+
+			// A points
+			Energy:       newLinearScale(335, 670, 1005, 1340, 1675, 2010, 2345, 2680, 3015, 3350),
+			SaturatedFat: newLinearScale(1, 2, 3, 4, 5, 6, 7, 8, 9, 10),
+			Sugars:       newLinearScale(4.5, 9, 13.5, 18, 22.5, 27, 31, 36, 40, 45),
+			Sodium:       newLinearScale(90, 180, 270, 360, 450, 540, 630, 720, 810, 900),
+
+			// C points
+			Fruits:  PointScale{Thresholds: []float64{40, 60, 80}, Points: []int{1, 2, 5}},
+			Fibre:   newLinearScale(0.9, 1.9, 2.8, 3.7, 4.7),
+			Protein: newLinearScale(1.6, 3.2, 4.8, 6.4, 8.0),
+
+			// Protein is not counted from 11 A points unless the product scores 5 fruit points
+			ProteinExclusionThreshold:      11,
+			ProteinExclusionFruitExemption: 5,
+
+			Grades: []GradeBoundary{
+				{Grade: GradeNonHFSS, MaxScore: cutOff - 1},
+				{Grade: GradeHFSS},
+			},
+		}
+	}
+
+	return []*Algorithm{
+		newModel(UKNPMAlgorithmVersionFood, "UK Nutrient Profiling Model 2004/05 for foods (HFSS from 4 points)",
+			[]models.ScoreType{models.FoodType, models.CheeseType, models.FatsOilsType}, 4),
+		newModel(UKNPMAlgorithmVersionDrink, "UK Nutrient Profiling Model 2004/05 for drinks (HFSS from 1 point)",
+			[]models.ScoreType{models.BeverageType, models.WaterType}, 1),
+	}
+}
@@ -0,0 +1,154 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// TestHFSSClassifier_Classify tests the food and drink cut-offs
+func TestHFSSClassifier_Classify(t *testing.T) {
+	classifier := NewHFSSClassifier()
+
+	tests := []struct {
+		name          string
+		data          models.NutritionalData
+		scoreType     models.ScoreType
+		expectedScore int
+		expectedHFSS  bool
+	}{
+		{
+			name:          "Apple",
+			data:          models.NutritionalData{Energy: 218, Sugars: 10.4, Sodium: 1, Fruits: 100, Fibre: 2.4, Protein: 0.3},
+			scoreType:     models.FoodType,
+			expectedScore: -5, // 2 sugars - 5 fruit - 2 fibre
+			expectedHFSS:  false,
+		},
+		{
+			name:          "Food Below Cut-off",
+			data:          models.NutritionalData{Sugars: 15},
+			scoreType:     models.FoodType,
+			expectedScore: 3,
+			expectedHFSS:  false,
+		},
+		{
+			name:          "Food At Cut-off",
+			data:          models.NutritionalData{Sugars: 20},
+			scoreType:     models.FoodType,
+			expectedScore: 4,
+			expectedHFSS:  true,
+		},
+		{
+			name:          "Cheese Is Scored Like Any Food",
+			data:          models.NutritionalData{Energy: 1700, Sugars: 0.5, SaturatedFattyAcids: 21, TotalFat: 34, Sodium: 650, Protein: 25},
+			scoreType:     models.CheeseType,
+			expectedScore: 22, // protein not counted
+			expectedHFSS:  true,
+		},
+		{
+			name:          "Cola",
+			data:          models.NutritionalData{Energy: 180, Sugars: 10.6, Sodium: 4},
+			scoreType:     models.BeverageType,
+			expectedScore: 2,
+			expectedHFSS:  true,
+		},
+		{
+			name:          "Drink At Cut-off",
+			data:          models.NutritionalData{Sugars: 5},
+			scoreType:     models.BeverageType,
+			expectedScore: 1,
+			expectedHFSS:  true,
+		},
+		{
+			name:          "Water",
+			data:          models.NutritionalData{},
+			scoreType:     models.WaterType,
+			expectedScore: 0,
+			expectedHFSS:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, err := classifier.Classify(tt.data, tt.scoreType)
+			if err != nil {
+				t.Fatalf("Classify() unexpected error: %v", err)
+			}
+			if verdict.Score.Value != tt.expectedScore {
+				t.Errorf("Score = %d, want %d", verdict.Score.Value, tt.expectedScore)
+			}
+			if verdict.HFSS != tt.expectedHFSS || verdict.Compliant == tt.expectedHFSS {
+				t.Errorf("HFSS = %v (compliant %v), want HFSS %v", verdict.HFSS, verdict.Compliant, tt.expectedHFSS)
+			}
+			if verdict.Score.Scheme != models.SchemeUKNPM {
+				t.Errorf("Scheme = %s, want %s", verdict.Score.Scheme, models.SchemeUKNPM)
+			}
+			if len(verdict.Reasons) == 0 {
+				t.Error("Verdict should list its reasons")
+			}
+		})
+	}
+}
+
+// TestHFSSClassifier_Reasons tests the reasons given for a verdict
+func TestHFSSClassifier_Reasons(t *testing.T) {
+	classifier := NewHFSSClassifier()
+
+	verdict, err := classifier.Classify(models.NutritionalData{Energy: 1700, Sugars: 0.5, SaturatedFattyAcids: 21, TotalFat: 34, Sodium: 650, Protein: 25}, models.CheeseType)
+	if err != nil {
+		t.Fatalf("Classify() unexpected error: %v", err)
+	}
+
+	if verdict.Threshold != 4 || verdict.Drink {
+		t.Errorf("Threshold = %d (drink %v), want the food cut-off of 4", verdict.Threshold, verdict.Drink)
+	}
+
+	expected := []string{
+		"Score 22 reaches the food cut-off of 4 points: the product is HFSS",
+		"Energy 1700 kJ: +5 points",
+		"Saturated fat 21 g: +10 points",
+		"Sodium 650 mg: +7 points",
+	}
+	for i, reason := range expected {
+		if verdict.Reasons[i] != reason {
+			t.Errorf("Reasons[%d] = %q, want %q", i, verdict.Reasons[i], reason)
+		}
+	}
+	if last := verdict.Reasons[len(verdict.Reasons)-1]; !strings.HasPrefix(last, "Protein points are not counted") {
+		t.Errorf("Last reason should explain the protein exclusion, got %q", last)
+	}
+
+	drink, err := classifier.Classify(models.NutritionalData{}, models.WaterType)
+	if err != nil {
+		t.Fatalf("Classify() unexpected error: %v", err)
+	}
+	if drink.Reasons[0] != "Score 0 is below the drink cut-off of 1 point: the product is not HFSS" {
+		t.Errorf("Reasons[0] = %q", drink.Reasons[0])
+	}
+}
+
+// TestHFSSClassifier_Profiles tests that drinks are validated with the beverage profile
+func TestHFSSClassifier_Profiles(t *testing.T) {
+	data := models.NutritionalData{Energy: 1500, Sugars: 85}
+	classifier := NewHFSSClassifier()
+
+	if _, err := classifier.Classify(data, models.FoodType); err != nil {
+		t.Fatalf("Classify() unexpected error for a food: %v", err)
+	}
+	if _, err := classifier.Classify(data, models.BeverageType); err == nil || !strings.Contains(err.Error(), "Energy cannot exceed 1000.0 kJ") {
+		t.Errorf("Classify() error = %v, want the beverage energy limit", err)
+	}
+}
+
+// TestHFSSClassifier_Algorithms tests the model definitions
+func TestHFSSClassifier_Algorithms(t *testing.T) {
+	ruleset := &Ruleset{Name: "UK Nutrient Profiling Model", Algorithms: ukNPMAlgorithms()}
+	if err := ruleset.Validate(); err != nil {
+		t.Errorf("UK NPM algorithms should pass ruleset validation: %v", err)
+	}
+
+	if _, err := NewHFSSClassifier().Classify(models.NutritionalData{Sugars: -1}, models.FoodType); err == nil {
+		t.Error("Classify() should reject invalid data")
+	}
+}
@@ -0,0 +1,232 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// imputableNutrients lists the nutrients that can be marked unknown and estimated
+// The scored nutrients come first; fats that are not scored by every algorithm have no component
+var imputableNutrients = append(append([]reformulableNutrient{}, reformulableNutrients...),
+	reformulableNutrient{"total_fat", "g", true,
+		func(d models.NutritionalData) float64 { return float64(d.TotalFat) },
+		func(d *models.NutritionalData, v float64) { d.TotalFat = models.TotalFatGram(v) },
+		nil},
+	reformulableNutrient{"trans_fat", "g", true,
+		func(d models.NutritionalData) float64 { return float64(d.TransFat) },
+		func(d *models.NutritionalData, v float64) { d.TransFat = models.TransFatGram(v) },
+		nil},
+)
+
+// imputableNutrient returns the accessor of a nutrient that can be estimated
+func imputableNutrient(key string) (reformulableNutrient, bool) {
+	for _, nutrient := range imputableNutrients {
+		if nutrient.key == key {
+			return nutrient, true
+		}
+	}
+	return reformulableNutrient{}, false
+}
+
+// nutrientStats summarises the known values of a nutrient among reference foods
+type nutrientStats struct {
+	median  float64
+	min     float64
+	max     float64
+	samples int
+}
+
+// newNutrientStats computes the median and range of the values
+func newNutrientStats(values []float64) nutrientStats {
+	sort.Float64s(values)
+	middle := len(values) / 2
+	median := values[middle]
+	if len(values)%2 == 0 {
+		median = (values[middle-1] + values[middle]) / 2
+	}
+	return nutrientStats{
+		median:  roundDistance(median),
+		min:     values[0],
+		max:     values[len(values)-1],
+		samples: len(values),
+	}
+}
+
+// Imputer estimates unknown nutrient values from reference foods of the same category
+type Imputer struct {
+	categories map[string]map[string]nutrientStats
+	overall    map[string]nutrientStats
+}
+
+// NewImputer computes the nutrient medians per category of the reference foods, usually
+// the embedded database. Values marked unknown in the reference foods are left out
+func NewImputer(foods []models.Food) *Imputer {
+	categoryValues := make(map[string]map[string][]float64)
+	overallValues := make(map[string][]float64)
+	for _, food := range foods {
+		category := strings.ToLower(strings.TrimSpace(food.Category))
+		if categoryValues[category] == nil {
+			categoryValues[category] = make(map[string][]float64)
+		}
+		data := food.NutritionalData.ResolveSodium()
+		for _, nutrient := range imputableNutrients {
+			if data.IsUnknown(nutrient.key) {
+				continue
+			}
+			value := nutrient.get(data)
+			categoryValues[category][nutrient.key] = append(categoryValues[category][nutrient.key], value)
+			overallValues[nutrient.key] = append(overallValues[nutrient.key], value)
+		}
+	}
+
+	imputer := &Imputer{
+		categories: make(map[string]map[string]nutrientStats, len(categoryValues)),
+		overall:    make(map[string]nutrientStats, len(overallValues)),
+	}
+	for category, nutrients := range categoryValues {
+		imputer.categories[category] = make(map[string]nutrientStats, len(nutrients))
+		for key, values := range nutrients {
+			imputer.categories[category][key] = newNutrientStats(values)
+		}
+	}
+	for key, values := range overallValues {
+		imputer.overall[key] = newNutrientStats(values)
+	}
+	return imputer
+}
+
+// requireKnownValues rejects data with unknown nutrients, which would otherwise score as 0
+func requireKnownValues(data models.NutritionalData) error {
+	if len(data.Unknown) == 0 {
+		return nil
+	}
+	return models.NewValidationError("unknown",
+		fmt.Sprintf("Nutrients with unknown values cannot be scored: %s", strings.Join(data.Unknown, ", ")),
+		"Enter the values from the label", "Use Imputer.Score to estimate the unknown values")
+}
+
+// Impute replaces the unknown values of a food with the median of its category
+// Categories without reference values for a nutrient fall back to the median of all
+// reference foods. The returned data has no unknown nutrients left
+func (im *Imputer) Impute(food models.Food) (models.NutritionalData, []models.ImputedValue, error) {
+	data := food.NutritionalData
+	var imputed []models.ImputedValue
+	for _, key := range data.Unknown {
+		nutrient, found := imputableNutrient(key)
+		if !found {
+			return models.NutritionalData{}, nil, models.NewValidationError("unknown",
+				fmt.Sprintf("Unknown nutrient %q cannot be estimated", key),
+				"Mark energy, sugars, saturated_fat, total_fat, trans_fat, sodium, fruits, fibre or protein as unknown")
+		}
+
+		source := "category " + food.Category
+		stats, found := im.categories[strings.ToLower(strings.TrimSpace(food.Category))][key]
+		if !found {
+			source = "all foods"
+			stats, found = im.overall[key]
+		}
+		if !found {
+			return models.NutritionalData{}, nil, models.NewValidationError(key,
+				fmt.Sprintf("No reference values to estimate %s", key), "Enter the value from the label")
+		}
+
+		nutrient.set(&data, stats.median)
+		imputed = append(imputed, models.ImputedValue{
+			Nutrient: key,
+			Unit:     nutrient.unit,
+			Value:    stats.median,
+			Min:      stats.min,
+			Max:      stats.max,
+			Source:   source,
+			Samples:  stats.samples,
+		})
+	}
+	data.Unknown = nil
+
+	return data, imputed, nil
+}
+
+// Score scores a food with its unknown values estimated by Impute
+// The confidence is the share of the maximum points of the scored nutrients that rest on
+// known values. The score range tries every unknown value at both ends of its reference range
+func (im *Imputer) Score(scorer models.NutritionalScorer, food models.Food, scoreType models.ScoreType) (models.ImputedScore, error) {
+	data, imputed, err := im.Impute(food)
+	if err != nil {
+		return models.ImputedScore{}, err
+	}
+	score, err := scorer.CalculateScore(data, scoreType)
+	if err != nil {
+		return models.ImputedScore{}, err
+	}
+
+	result := models.ImputedScore{
+		Score:      score,
+		Imputed:    imputed,
+		Confidence: scoreConfidence(score.Breakdown, food.NutritionalData),
+		MinScore:   score.Value,
+		MaxScore:   score.Value,
+	}
+
+	// Score every combination of the unknown values at their lowest and highest
+	grades := map[string]int{score.Grade: score.Value}
+	for corner := 0; corner < 1<<len(imputed); corner++ {
+		variant := data
+		for i, value := range imputed {
+			nutrient, _ := imputableNutrient(value.Nutrient)
+			if corner&(1<<i) == 0 {
+				nutrient.set(&variant, value.Min)
+			} else {
+				nutrient.set(&variant, value.Max)
+			}
+		}
+		variantScore, err := scorer.CalculateScore(variant, scoreType)
+		if err != nil {
+			// Combinations such as saturated fat above total fat are not possible foods
+			continue
+		}
+		if variantScore.Value < result.MinScore {
+			result.MinScore = variantScore.Value
+		}
+		if variantScore.Value > result.MaxScore {
+			result.MaxScore = variantScore.Value
+		}
+		if value, seen := grades[variantScore.Grade]; !seen || variantScore.Value < value {
+			grades[variantScore.Grade] = variantScore.Value
+		}
+	}
+
+	for grade := range grades {
+		result.Grades = append(result.Grades, grade)
+	}
+	sort.Slice(result.Grades, func(i, j int) bool {
+		return grades[result.Grades[i]] < grades[result.Grades[j]]
+	})
+
+	return result, nil
+}
+
+// scoreConfidence weighs the scored nutrients by their maximum points
+// Without a breakdown every scored nutrient counts the same
+func scoreConfidence(breakdown models.ScoreBreakdown, data models.NutritionalData) float64 {
+	known, total := 0.0, 0.0
+	for _, nutrient := range reformulableNutrients {
+		weight := float64(nutrient.component(breakdown).MaxPoints)
+		total += weight
+		if !data.IsUnknown(nutrient.key) {
+			known += weight
+		}
+	}
+	if total == 0 {
+		for _, nutrient := range reformulableNutrients {
+			total++
+			if !data.IsUnknown(nutrient.key) {
+				known++
+			}
+		}
+	}
+	return math.Round(known/total*100) / 100
+}
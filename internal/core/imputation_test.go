@@ -0,0 +1,206 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// imputationFoods are the reference foods of the imputation tests
+var imputationFoods = []models.Food{
+	{Category: "Fruits", NutritionalData: models.NutritionalData{Energy: 218, Sugars: 10.4, Sodium: 1, Fruits: 100, Fibre: 2.4, Protein: 0.3}},
+	{Category: "Fruits", NutritionalData: models.NutritionalData{Energy: 371, Sugars: 12.2, Sodium: 1, Fruits: 100, Fibre: 2.6, Protein: 1.1}},
+	{Category: "Fruits", NutritionalData: models.NutritionalData{Energy: 197, Sugars: 9.4, Sodium: 0, Fruits: 100, Fibre: 2.0, Protein: 0.9}},
+	{Category: "Grains", NutritionalData: models.NutritionalData{Energy: 1050, Sugars: 4, Sodium: 450, Fruits: 0, Fibre: 7, Protein: 13}},
+	{Category: "Grains", NutritionalData: models.NutritionalData{Energy: 1500, Sugars: 0.1, Sodium: 5, Fibre: 0.4, Protein: 2.7,
+		Unknown: []string{"fruits"}}},
+}
+
+// TestImputer_Impute tests estimates from the category and from all foods
+func TestImputer_Impute(t *testing.T) {
+	imputer := NewImputer(imputationFoods)
+
+	tests := []struct {
+		name        string
+		food        models.Food
+		wantFibre   models.FibreGram
+		wantImputed models.ImputedValue
+		wantErr     bool
+	}{
+		{
+			name:        "category median",
+			food:        models.Food{Category: "Fruits", NutritionalData: models.NutritionalData{Energy: 250, Unknown: []string{"fibre"}}},
+			wantFibre:   2.4,
+			wantImputed: models.ImputedValue{Nutrient: "fibre", Unit: "g", Value: 2.4, Min: 2, Max: 2.6, Source: "category Fruits", Samples: 3},
+		},
+		{
+			name:        "category median ignores unknown reference values",
+			food:        models.Food{Category: "grains", NutritionalData: models.NutritionalData{Unknown: []string{"fruits"}}},
+			wantImputed: models.ImputedValue{Nutrient: "fruits", Unit: "%", Value: 0, Min: 0, Max: 0, Source: "category grains", Samples: 1},
+		},
+		{
+			name:        "median of all foods without category values",
+			food:        models.Food{Category: "Snacks", NutritionalData: models.NutritionalData{Unknown: []string{"fibre"}}},
+			wantFibre:   2.4,
+			wantImputed: models.ImputedValue{Nutrient: "fibre", Unit: "g", Value: 2.4, Min: 0.4, Max: 7, Source: "all foods", Samples: 5},
+		},
+		{
+			name:    "nutrient that cannot be estimated",
+			food:    models.Food{Category: "Fruits", NutritionalData: models.NutritionalData{Unknown: []string{"vitamin_c"}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, imputed, err := imputer.Impute(tt.food)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Impute() expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Impute() unexpected error: %v", err)
+			}
+			if len(data.Unknown) != 0 {
+				t.Errorf("Unknown = %v, want none left", data.Unknown)
+			}
+			if data.Fibre != tt.wantFibre {
+				t.Errorf("Fibre = %v, want %v", data.Fibre, tt.wantFibre)
+			}
+			if len(imputed) != 1 || imputed[0] != tt.wantImputed {
+				t.Errorf("imputed = %+v, want %+v", imputed, tt.wantImputed)
+			}
+		})
+	}
+}
+
+// TestImputer_Score tests the confidence and grade range of scores with unknown values
+func TestImputer_Score(t *testing.T) {
+	imputer := NewImputer(imputationFoods)
+	scorer := NewNutritionalScorer()
+
+	// Everything known: full confidence and a single grade
+	known := models.Food{Category: "Grains", NutritionalData: models.NutritionalData{
+		Energy: 1050, Sugars: 4, Sodium: 450, Fibre: 7, Protein: 13}}
+	result, err := imputer.Score(scorer, known, models.FoodType)
+	if err != nil {
+		t.Fatalf("Score() unexpected error: %v", err)
+	}
+	if result.Confidence != 1 || len(result.Grades) != 1 || result.MinScore != result.MaxScore || len(result.Imputed) != 0 {
+		t.Errorf("Score() = confidence %v, grades %v, range %d..%d, want 1, one grade, no range",
+			result.Confidence, result.Grades, result.MinScore, result.MaxScore)
+	}
+
+	// Unknown fibre in a category with a wide fibre range: 50 of 55 points are known
+	partial := known
+	partial.NutritionalData.Fibre = 0
+	partial.NutritionalData.Unknown = []string{"fibre"}
+	result, err = imputer.Score(scorer, partial, models.FoodType)
+	if err != nil {
+		t.Fatalf("Score() unexpected error: %v", err)
+	}
+	if result.Confidence != 0.91 {
+		t.Errorf("Confidence = %v, want 0.91", result.Confidence)
+	}
+
+	low := known
+	low.NutritionalData.Fibre = 0.4
+	high := known
+	high.NutritionalData.Fibre = 7
+	lowScore, _ := scorer.CalculateScore(low.NutritionalData, models.FoodType)
+	highScore, _ := scorer.CalculateScore(high.NutritionalData, models.FoodType)
+	if result.MinScore != highScore.Value || result.MaxScore != lowScore.Value {
+		t.Errorf("score range = %d..%d, want %d..%d", result.MinScore, result.MaxScore, highScore.Value, lowScore.Value)
+	}
+	if result.Grades[0] != highScore.Grade || result.Grades[len(result.Grades)-1] != lowScore.Grade {
+		t.Errorf("Grades = %v, want %s to %s", result.Grades, highScore.Grade, lowScore.Grade)
+	}
+	if result.Score.Breakdown.Fibre.Value != 3.7 {
+		t.Errorf("scored fibre = %v, want the category median 3.7", result.Score.Breakdown.Fibre.Value)
+	}
+}
+
+// TestCalculateScore_Unknown tests that unknown values are not silently scored as 0
+func TestCalculateScore_Unknown(t *testing.T) {
+	data := models.NutritionalData{Energy: 1050, Sugars: 4, Sodium: 450, Protein: 13, Unknown: []string{"fibre"}}
+
+	scorers := []struct {
+		name   string
+		scorer models.NutritionalScorer
+	}{
+		{"Nutri-Score", NewNutritionalScorer()},
+		{"Health Star Rating", NewHealthStarRatingScorer()},
+	}
+	for _, s := range scorers {
+		t.Run(s.name, func(t *testing.T) {
+			_, err := s.scorer.CalculateScore(data, models.FoodType)
+			if err == nil {
+				t.Fatal("CalculateScore() should reject unknown values")
+			}
+			nutritionalErr, ok := err.(models.NutritionalError)
+			if !ok || nutritionalErr.Field != "unknown" || !strings.Contains(strings.Join(nutritionalErr.Suggestions, " "), "Imputer.Score") {
+				t.Errorf("CalculateScore() error = %#v, want an unknown field error pointing to Imputer.Score", err)
+			}
+		})
+	}
+
+	imputer := NewImputer(imputationFoods)
+	food := models.Food{Category: "Grains", NutritionalData: data}
+	if _, err := imputer.Score(NewNutritionalScorer(), food, models.FoodType); err != nil {
+		t.Errorf("Imputer.Score() unexpected error: %v", err)
+	}
+}
+
+// TestEvaluators_Unknown tests that the labelling schemes reject unknown values like the scorers
+func TestEvaluators_Unknown(t *testing.T) {
+	food := models.Food{ID: "yoghurt", NutritionalData: models.NutritionalData{
+		Energy: 400, TotalFat: 3, SaturatedFattyAcids: 2, Sugars: 12, Sodium: 50, Protein: 4, Unknown: []string{"fibre"}}}
+
+	evaluators := []struct {
+		name     string
+		evaluate func() error
+	}{
+		{"Traffic Lights", func() error {
+			_, err := NewTrafficLightEvaluator().Evaluate(food.NutritionalData, models.FoodType, 0)
+			return err
+		}},
+		{"HFSS", func() error {
+			_, err := NewHFSSClassifier().Classify(food.NutritionalData, models.FoodType)
+			return err
+		}},
+		{"Claims", func() error {
+			_, err := NewClaimChecker().Check(food)
+			return err
+		}},
+		{"Chile Warnings", func() error {
+			_, err := NewChileWarningEvaluator().Evaluate(food)
+			return err
+		}},
+		{"Mexico Warnings", func() error {
+			_, err := NewMexicoWarningEvaluator().Evaluate(food)
+			return err
+		}},
+	}
+	for _, e := range evaluators {
+		t.Run(e.name, func(t *testing.T) {
+			err := e.evaluate()
+			if nutritionalErr, ok := err.(models.NutritionalError); !ok || nutritionalErr.Field != "unknown" {
+				t.Errorf("error = %#v, want an unknown field error", err)
+			}
+		})
+	}
+}
+
+// TestValidateNutritionalData_Unknown tests the names of unknown nutrients
+func TestValidateNutritionalData_Unknown(t *testing.T) {
+	validator := NewInputValidator()
+	if errs := validator.ValidateNutritionalData(models.NutritionalData{Unknown: []string{"fibre", "fruits"}}); len(errs) != 0 {
+		t.Errorf("ValidateNutritionalData() = %v, want no errors", errs)
+	}
+	if errs := validator.ValidateNutritionalData(models.NutritionalData{Unknown: []string{"vitamin_c"}}); len(errs) != 1 {
+		t.Errorf("ValidateNutritionalData() = %v, want 1 error", errs)
+	}
+}
@@ -0,0 +1,295 @@
+package core
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// ProfileScheme is a profiling scheme the Profiler runs on every food
+// Apply stores its result in the report and returns an error if the food cannot be evaluated
+type ProfileScheme struct {
+	Name  string
+	Apply func(food models.Food, scoreType models.ScoreType, report *models.ProfileReport) error
+}
+
+// Profiler runs every registered profiling scheme on a food and consolidates the results
+type Profiler struct {
+	schemes []ProfileScheme
+}
+
+// NewProfiler creates a profiler with every built-in scheme registered: each Nutri-Score
+// family, the Health Star Rating, UK traffic lights, the UK HFSS classification,
+// EU nutrition claims and the Chilean and Mexican warning labels
+func NewProfiler() *Profiler {
+	profiler := &Profiler{}
+
+	for _, family := range DefaultAlgorithmRegistry().Families() {
+		family := family
+		profiler.mustRegister(ProfileScheme{
+			Name: models.SchemeNutriScore + "-" + family,
+			Apply: func(food models.Food, scoreType models.ScoreType, report *models.ProfileReport) error {
+				scorer, err := NewNutritionalScorerWithVersion(family)
+				if err != nil {
+					return err
+				}
+				return appendScore(scorer, food, scoreType, report)
+			},
+		})
+	}
+
+	profiler.mustRegister(ProfileScheme{
+		Name: models.SchemeHealthStarRating,
+		Apply: func(food models.Food, scoreType models.ScoreType, report *models.ProfileReport) error {
+			return appendScore(NewHealthStarRatingScorer(), food, scoreType, report)
+		},
+	})
+
+	profiler.mustRegister(ProfileScheme{
+		Name: "traffic-lights",
+		Apply: func(food models.Food, scoreType models.ScoreType, report *models.ProfileReport) error {
+			label, err := NewTrafficLightEvaluator().Evaluate(food.NutritionalData, scoreType, ServingSize(food))
+			if err != nil {
+				return err
+			}
+			report.TrafficLights = &label
+			return nil
+		},
+	})
+
+	profiler.mustRegister(ProfileScheme{
+		Name: models.SchemeUKNPM,
+		Apply: func(food models.Food, scoreType models.ScoreType, report *models.ProfileReport) error {
+			verdict, err := NewHFSSClassifier().Classify(food.NutritionalData, scoreType)
+			if err != nil {
+				return err
+			}
+			report.HFSS = &verdict
+			return nil
+		},
+	})
+
+	profiler.mustRegister(ProfileScheme{
+		Name: "eu-claims",
+		Apply: func(food models.Food, scoreType models.ScoreType, report *models.ProfileReport) error {
+			claims, err := NewClaimChecker().Check(food)
+			if err != nil {
+				return err
+			}
+			report.Claims = &claims
+			return nil
+		},
+	})
+
+	for _, evaluator := range []struct {
+		name      string
+		evaluator models.WarningLabelEvaluator
+	}{
+		{"warning-labels-cl", NewChileWarningEvaluator()},
+		{"warning-labels-mx", NewMexicoWarningEvaluator()},
+	} {
+		evaluator := evaluator
+		profiler.mustRegister(ProfileScheme{
+			Name: evaluator.name,
+			Apply: func(food models.Food, scoreType models.ScoreType, report *models.ProfileReport) error {
+				result, err := evaluator.evaluator.Evaluate(food)
+				if err != nil {
+					return err
+				}
+				report.WarningLabels = append(report.WarningLabels, result)
+				return nil
+			},
+		})
+	}
+
+	return profiler
+}
+
+// appendScore adds the score of a scorer to the report
+func appendScore(scorer models.NutritionalScorer, food models.Food, scoreType models.ScoreType, report *models.ProfileReport) error {
+	score, err := scorer.CalculateScore(food.NutritionalData, scoreType)
+	if err != nil {
+		return err
+	}
+	report.Scores = append(report.Scores, score)
+	return nil
+}
+
+// Register adds a profiling scheme
+// Returns an error if the name is empty or already registered
+func (p *Profiler) Register(scheme ProfileScheme) error {
+	if scheme.Name == "" || scheme.Apply == nil {
+		return models.NewConfigError("Profiling scheme name and function are required",
+			"cannot register an incomplete profiling scheme")
+	}
+	for _, registered := range p.schemes {
+		if registered.Name == scheme.Name {
+			return models.NewConfigError("Profiling scheme already registered",
+				fmt.Sprintf("scheme %q is already registered", scheme.Name))
+		}
+	}
+	p.schemes = append(p.schemes, scheme)
+	return nil
+}
+
+// mustRegister registers a built-in scheme known to be valid
+func (p *Profiler) mustRegister(scheme ProfileScheme) {
+	if err := p.Register(scheme); err != nil {
+		panic(err)
+	}
+}
+
+// Schemes returns the names of the registered schemes in the order they run
+func (p *Profiler) Schemes() []string {
+	names := make([]string, 0, len(p.schemes))
+	for _, scheme := range p.schemes {
+		names = append(names, scheme.Name)
+	}
+	return names
+}
+
+// Profile runs every registered scheme on the food
+// A scheme that fails records its error in the report; the other schemes still run.
+// Foods with a declared serving also get their nutrients per serving
+func (p *Profiler) Profile(food models.Food, scoreType models.ScoreType) models.ProfileReport {
+	report := models.ProfileReport{
+		FoodID:      food.ID,
+		FoodName:    food.Name,
+		ScoreType:   scoreType,
+		GeneratedAt: time.Now(),
+	}
+	if ServingSize(food) > 0 {
+		if table, err := NewServingTable(food, 0); err == nil {
+			report.Nutrition = &table
+		}
+	}
+
+	for _, scheme := range p.schemes {
+		if err := scheme.Apply(food, scoreType, &report); err != nil {
+			if report.Errors == nil {
+				report.Errors = make(map[string]string)
+			}
+			report.Errors[scheme.Name] = err.Error()
+		}
+	}
+
+	return report
+}
+
+// profileCSVHeader lists the columns of a profile report CSV export
+var profileCSVHeader = []string{"food_id", "scheme", "item", "result", "value", "detail"}
+
+// ExportProfileReport exports a profile report as JSON or CSV
+// The CSV export has one row per nutrient per serving, score, traffic light, claim and warning seal
+func ExportProfileReport(report models.ProfileReport, format models.ExportFormat) ([]byte, error) {
+	switch format {
+	case models.JSON:
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return nil, models.NewExportError("Failed to export profile report", err.Error())
+		}
+		return data, nil
+	case models.CSV:
+		var buf bytes.Buffer
+		writer := csv.NewWriter(&buf)
+		if err := writer.Write(profileCSVHeader); err != nil {
+			return nil, models.NewExportError("Failed to export profile report", err.Error())
+		}
+		for _, row := range ProfileReportRows(report) {
+			if err := writer.Write(append([]string{report.FoodID}, row...)); err != nil {
+				return nil, models.NewExportError("Failed to export profile report", err.Error())
+			}
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return nil, models.NewExportError("Failed to export profile report", err.Error())
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, models.NewExportError(fmt.Sprintf("Unsupported export format: %s", format),
+			"profile reports can be exported as JSON or CSV")
+	}
+}
+
+// ProfileReportRows flattens a profile report into scheme, item, result, value and detail columns
+// The rows are used by the CSV export and the CLI profile view
+func ProfileReportRows(report models.ProfileReport) [][]string {
+	var rows [][]string
+	formatFloat := func(value float64) string {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+
+	if table := report.Nutrition; table != nil {
+		serving := fmt.Sprintf("per %s serving", formatAmount(table.ServingSize)+unitSuffix(string(servingUnit(table.Liquid))))
+		for _, nutrient := range table.Nutrients {
+			rows = append(rows, []string{"nutrition", nutrient.Nutrient,
+				formatFloat(nutrient.Per100) + unitSuffix(per100Unit(nutrient.Unit, table.Liquid)),
+				formatFloat(nutrient.PerServing), nutrient.Unit + " " + serving})
+		}
+	}
+
+	for _, score := range report.Scores {
+		result := "grade " + score.Grade
+		if score.Scheme == models.SchemeHealthStarRating {
+			result = formatAmount(score.Stars) + " stars"
+		}
+		rows = append(rows, []string{score.Scheme, score.AlgorithmVersion, result, strconv.Itoa(score.Value), ""})
+	}
+
+	if label := report.TrafficLights; label != nil {
+		drink := label.ScoreType == models.BeverageType || label.ScoreType == models.WaterType
+		for _, light := range label.Lights {
+			rows = append(rows, []string{"traffic-lights", light.Nutrient, string(light.Colour), formatFloat(light.Per100g),
+				per100Unit("g", drink)})
+		}
+	}
+
+	if verdict := report.HFSS; verdict != nil {
+		result := "not HFSS"
+		if verdict.HFSS {
+			result = "HFSS"
+		}
+		rows = append(rows, []string{models.SchemeUKNPM, verdict.Score.AlgorithmVersion, result,
+			strconv.Itoa(verdict.Score.Value), verdict.Reasons[0]})
+	}
+
+	if claims := report.Claims; claims != nil {
+		for _, claim := range claims.Claims {
+			result := "not eligible"
+			if claim.Eligible {
+				result = "eligible"
+			}
+			rows = append(rows, []string{"eu-claims", claim.Claim, result, formatFloat(claim.Margin), claim.Rule})
+		}
+	}
+
+	for _, warning := range report.WarningLabels {
+		scheme := "warning-labels-" + strings.ToLower(warning.Market)
+		if len(warning.Seals) == 0 {
+			rows = append(rows, []string{scheme, "-", "no seals", "", warning.Regulation})
+			continue
+		}
+		for _, seal := range warning.Seals {
+			rows = append(rows, []string{scheme, seal.Seal, "required", formatFloat(seal.Value),
+				fmt.Sprintf("limit %s%s", formatFloat(seal.Limit), unitSuffix(seal.Unit))})
+		}
+	}
+
+	schemes := make([]string, 0, len(report.Errors))
+	for scheme := range report.Errors {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	for _, scheme := range schemes {
+		rows = append(rows, []string{scheme, "-", "error", "", report.Errors[scheme]})
+	}
+
+	return rows
+}
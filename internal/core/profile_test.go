@@ -0,0 +1,121 @@
+package core
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// TestProfiler_Profile tests that every registered scheme runs on a food
+func TestProfiler_Profile(t *testing.T) {
+	profiler := NewProfiler()
+	cookies := models.Food{ID: "cookies-001", Name: "Chocolate Cookies", NutritionalData: models.NutritionalData{
+		Energy: 2000, Sugars: 30, SaturatedFattyAcids: 10, TotalFat: 20, Sodium: 300, Fibre: 3, Protein: 6}}
+
+	report := profiler.Profile(cookies, models.FoodType)
+
+	if len(report.Errors) > 0 {
+		t.Fatalf("Profile() errors: %v", report.Errors)
+	}
+	if report.FoodID != "cookies-001" || report.FoodName != "Chocolate Cookies" {
+		t.Errorf("Profile() food = %s (%s)", report.FoodID, report.FoodName)
+	}
+
+	// One score per Nutri-Score family plus the Health Star Rating
+	families := DefaultAlgorithmRegistry().Families()
+	if len(report.Scores) != len(families)+1 {
+		t.Fatalf("Scores = %d, want %d", len(report.Scores), len(families)+1)
+	}
+	for i, family := range families {
+		if score := report.Scores[i]; score.Scheme != models.SchemeNutriScore || !strings.HasPrefix(score.AlgorithmVersion, family) {
+			t.Errorf("Scores[%d] = %s %s, want Nutri-Score %s", i, score.Scheme, score.AlgorithmVersion, family)
+		}
+	}
+	if hsr := report.Scores[len(families)]; hsr.Scheme != models.SchemeHealthStarRating {
+		t.Errorf("last score scheme = %s, want %s", hsr.Scheme, models.SchemeHealthStarRating)
+	}
+
+	if report.TrafficLights == nil || report.HFSS == nil || report.Claims == nil {
+		t.Fatal("Profile() should include traffic lights, HFSS and claims")
+	}
+	if !report.HFSS.HFSS {
+		t.Error("Cookies should be HFSS")
+	}
+	if len(report.WarningLabels) != 2 || report.WarningLabels[0].Market != "CL" || report.WarningLabels[1].Market != "MX" {
+		t.Errorf("WarningLabels = %+v, want CL and MX", report.WarningLabels)
+	}
+}
+
+// TestProfiler_Errors tests that a failing scheme does not stop the others
+func TestProfiler_Errors(t *testing.T) {
+	profiler := NewProfiler()
+	if err := profiler.Register(ProfileScheme{Name: models.SchemeHealthStarRating, Apply: func(models.Food, models.ScoreType, *models.ProfileReport) error {
+		return nil
+	}}); err == nil {
+		t.Error("Register() should reject a duplicate scheme")
+	}
+
+	// Saturated fat without total fat cannot be scored as a fat, given traffic lights or fat claims
+	butter := models.Food{ID: "butter", NutritionalData: models.NutritionalData{Energy: 3000, SaturatedFattyAcids: 50}}
+	report := profiler.Profile(butter, models.FatsOilsType)
+
+	for _, scheme := range []string{"nutriscore-2017", "nutriscore-2023", "traffic-lights", "eu-claims"} {
+		if _, failed := report.Errors[scheme]; !failed {
+			t.Errorf("Errors should contain %s, got %v", scheme, report.Errors)
+		}
+	}
+	if report.HFSS == nil {
+		t.Error("Schemes that can evaluate the food should still run")
+	}
+}
+
+// TestExportProfileReport tests the JSON and CSV exports
+func TestExportProfileReport(t *testing.T) {
+	cola := models.Food{ID: "cola", Category: "Beverages", NutritionalData: models.NutritionalData{Energy: 180, Sugars: 10.6, Sodium: 4}}
+	report := NewProfiler().Profile(cola, models.BeverageType)
+
+	data, err := ExportProfileReport(report, models.JSON)
+	if err != nil {
+		t.Fatalf("ExportProfileReport(JSON) unexpected error: %v", err)
+	}
+	var decoded models.ProfileReport
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("JSON export does not decode: %v", err)
+	}
+	if decoded.FoodID != "cola" || len(decoded.Scores) != len(report.Scores) {
+		t.Errorf("JSON export lost data: %+v", decoded)
+	}
+
+	data, err = ExportProfileReport(report, models.CSV)
+	if err != nil {
+		t.Fatalf("ExportProfileReport(CSV) unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if lines[0] != "food_id,scheme,item,result,value,detail" {
+		t.Errorf("CSV header = %q", lines[0])
+	}
+	expected := []string{
+		"cola,nutriscore,2017-beverage,grade E,",
+		"cola,traffic-lights,sugars,amber,10.6,g per 100ml",
+		"cola,uk-npm,uk-npm-2004-drink,HFSS,2,",
+		"cola,warning-labels-cl,ALTO EN AZÚCARES,required,10.6,limit 5 g per 100ml",
+	}
+	for _, prefix := range expected {
+		found := false
+		for _, line := range lines[1:] {
+			if strings.HasPrefix(line, prefix) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("CSV export has no row starting with %q", prefix)
+		}
+	}
+
+	if _, err := ExportProfileReport(report, models.XML); err == nil {
+		t.Error("ExportProfileReport() should reject XML")
+	}
+}
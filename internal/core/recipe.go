@@ -0,0 +1,123 @@
+package core
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// CalculateRecipe derives the nutritional data per 100g of a finished dish
+// Nutrient amounts of all ingredients are added up and divided by the cooked weight, so
+// cooking losses (yield factor below 1) concentrate the nutrients. The fruit, vegetable
+// and nut percentage is taken from the raw ingredient proportions
+// The ingredients map holds the referenced foods keyed by food ID
+func CalculateRecipe(recipe models.Recipe, ingredients map[string]models.Food) (models.Food, error) {
+	if len(recipe.Ingredients) == 0 {
+		return models.Food{}, models.NewValidationError("ingredients", "Recipe has no ingredients",
+			"Add at least one ingredient with its weight in grams")
+	}
+	if recipe.YieldFactor < 0 {
+		return models.Food{}, models.NewValidationError("yield_factor", "Yield factor cannot be negative",
+			"Use the cooked weight divided by the raw weight, e.g. 0.8 for a 20% cooking loss")
+	}
+
+	var total models.NutritionalData
+	for _, ingredient := range recipe.Ingredients {
+		if ingredient.Grams <= 0 {
+			return models.Food{}, models.NewValidationError("grams",
+				fmt.Sprintf("Ingredient %s must weigh more than 0 g", ingredient.FoodID))
+		}
+		food, found := ingredients[ingredient.FoodID]
+		if !found {
+			return models.Food{}, models.NewValidationError("food_id",
+				fmt.Sprintf("Ingredient %s was not found", ingredient.FoodID))
+		}
+
+		// The summed Fruits field holds the grams of fruit, vegetables and nuts
+		total = addNutrients(total, scaleNutrients(food.NutritionalData.ResolveSodium(), ingredient.Grams/100))
+	}
+
+	// Scale the totals to 100g of the finished dish
+	dish := roundNutrients(scaleNutrients(total, 100/recipe.CookedWeight()))
+	dish.Fruits = models.FruitsPercent(roundDistance(math.Min(float64(total.Fruits)/recipe.RawWeight()*100, 100)))
+	dish.SodiumDeclaredAs = models.DeclaredAsSodium
+
+	return models.Food{
+		ID:              recipe.ID,
+		Name:            recipe.Name,
+		Category:        recipe.Category,
+		NutritionalData: dish,
+		IsUserDefined:   true,
+		CreatedAt:       recipe.CreatedAt,
+		UpdatedAt:       recipe.UpdatedAt,
+		Source:          "Recipe",
+		Liquid:          recipe.ScoreType == models.BeverageType || recipe.ScoreType == models.WaterType,
+	}, nil
+}
+
+// ScoreRecipe derives the nutritional data of a recipe and scores it as the recipe's score type
+func ScoreRecipe(scorer models.NutritionalScorer, recipe models.Recipe, ingredients map[string]models.Food) (models.NutritionalScore, error) {
+	dish, err := CalculateRecipe(recipe, ingredients)
+	if err != nil {
+		return models.NutritionalScore{}, err
+	}
+	return scorer.CalculateScore(dish.NutritionalData, recipe.ScoreType)
+}
+
+// scaleNutrients multiplies every nutrient amount, including the fruit percentage, by a factor
+func scaleNutrients(data models.NutritionalData, factor float64) models.NutritionalData {
+	data.Energy = models.EnergyKJ(float64(data.Energy) * factor)
+	data.Sugars = models.SugarGram(float64(data.Sugars) * factor)
+	data.SaturatedFattyAcids = models.SaturatedFattyAcids(float64(data.SaturatedFattyAcids) * factor)
+	data.TotalFat = models.TotalFatGram(float64(data.TotalFat) * factor)
+	data.TransFat = models.TransFatGram(float64(data.TransFat) * factor)
+	data.Carbohydrate = models.CarbohydrateGram(float64(data.Carbohydrate) * factor)
+	data.Sodium = models.SodiumMilligram(float64(data.Sodium) * factor)
+	data.Salt = models.SaltGram(float64(data.Salt) * factor)
+	data.Fruits = models.FruitsPercent(float64(data.Fruits) * factor)
+	data.Fibre = models.FibreGram(float64(data.Fibre) * factor)
+	data.Protein = models.ProteinGram(float64(data.Protein) * factor)
+	return data
+}
+
+// addNutrients sums the nutrient amounts of two foods
+// A flag set on either food, such as red meat, is set on the sum, and a nutrient unknown
+// for either food is unknown for the sum
+func addNutrients(total, data models.NutritionalData) models.NutritionalData {
+	total.Energy += data.Energy
+	total.Sugars += data.Sugars
+	total.SaturatedFattyAcids += data.SaturatedFattyAcids
+	total.TotalFat += data.TotalFat
+	total.TransFat += data.TransFat
+	total.Carbohydrate += data.Carbohydrate
+	total.Sodium += data.Sodium
+	total.Salt += data.Salt
+	total.Fruits += data.Fruits
+	total.Fibre += data.Fibre
+	total.Protein += data.Protein
+	total.RedMeat = total.RedMeat || data.RedMeat
+	total.NonNutritiveSweeteners = total.NonNutritiveSweeteners || data.NonNutritiveSweeteners
+	for _, nutrient := range data.Unknown {
+		if !total.IsUnknown(nutrient) {
+			total.Unknown = append(total.Unknown, nutrient)
+		}
+	}
+	return total
+}
+
+// roundNutrients rounds every nutrient amount to 4 decimals
+func roundNutrients(data models.NutritionalData) models.NutritionalData {
+	data.Energy = models.EnergyKJ(roundDistance(float64(data.Energy)))
+	data.Sugars = models.SugarGram(roundDistance(float64(data.Sugars)))
+	data.SaturatedFattyAcids = models.SaturatedFattyAcids(roundDistance(float64(data.SaturatedFattyAcids)))
+	data.TotalFat = models.TotalFatGram(roundDistance(float64(data.TotalFat)))
+	data.TransFat = models.TransFatGram(roundDistance(float64(data.TransFat)))
+	data.Carbohydrate = models.CarbohydrateGram(roundDistance(float64(data.Carbohydrate)))
+	data.Sodium = models.SodiumMilligram(roundDistance(float64(data.Sodium)))
+	data.Salt = models.SaltGram(roundDistance(float64(data.Salt)))
+	data.Fruits = models.FruitsPercent(roundDistance(float64(data.Fruits)))
+	data.Fibre = models.FibreGram(roundDistance(float64(data.Fibre)))
+	data.Protein = models.ProteinGram(roundDistance(float64(data.Protein)))
+	return data
+}
@@ -0,0 +1,132 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// recipeIngredients are the foods referenced by the recipe tests
+var recipeIngredients = map[string]models.Food{
+	"oats": {ID: "oats", NutritionalData: models.NutritionalData{
+		Energy: 1500, Sugars: 1, SaturatedFattyAcids: 1, TotalFat: 7, Sodium: 5, Fibre: 10, Protein: 13}},
+	"banana": {ID: "banana", NutritionalData: models.NutritionalData{
+		Energy: 370, Sugars: 12, SaturatedFattyAcids: 0.1, TotalFat: 0.3, Sodium: 1, Fruits: 100, Fibre: 2.6, Protein: 1.1}},
+	"bacon": {ID: "bacon", NutritionalData: models.NutritionalData{
+		Energy: 1500, SaturatedFattyAcids: 10, TotalFat: 30, Sodium: 1500, Protein: 15, RedMeat: true}},
+}
+
+// TestCalculateRecipe tests the weighted nutrition per 100g of a recipe
+func TestCalculateRecipe(t *testing.T) {
+	tests := []struct {
+		name        string
+		recipe      models.Recipe
+		wantEnergy  models.EnergyKJ
+		wantSugars  models.SugarGram
+		wantFruits  models.FruitsPercent
+		wantRedMeat bool
+	}{
+		{
+			name: "ingredients averaged by weight",
+			recipe: models.Recipe{Name: "Porridge", Ingredients: []models.RecipeIngredient{
+				{FoodID: "oats", Grams: 50}, {FoodID: "banana", Grams: 50}}},
+			wantEnergy: 935,
+			wantSugars: 6.5,
+			wantFruits: 50,
+		},
+		{
+			name: "cooking loss concentrates nutrients",
+			recipe: models.Recipe{Name: "Baked porridge", YieldFactor: 0.5, Ingredients: []models.RecipeIngredient{
+				{FoodID: "oats", Grams: 50}, {FoodID: "banana", Grams: 50}}},
+			wantEnergy: 1870,
+			wantSugars: 13,
+			wantFruits: 50,
+		},
+		{
+			name: "fruit share from raw proportions",
+			recipe: models.Recipe{Name: "Banana oats", Ingredients: []models.RecipeIngredient{
+				{FoodID: "oats", Grams: 25}, {FoodID: "banana", Grams: 75}}},
+			wantEnergy: 652.5,
+			wantSugars: 9.25,
+			wantFruits: 75,
+		},
+		{
+			name: "red meat flag of one ingredient",
+			recipe: models.Recipe{Name: "Savoury oats", Ingredients: []models.RecipeIngredient{
+				{FoodID: "oats", Grams: 80}, {FoodID: "bacon", Grams: 20}}},
+			wantEnergy:  1500,
+			wantSugars:  0.8,
+			wantRedMeat: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dish, err := CalculateRecipe(tt.recipe, recipeIngredients)
+			if err != nil {
+				t.Fatalf("CalculateRecipe() unexpected error: %v", err)
+			}
+			data := dish.NutritionalData
+			if data.Energy != tt.wantEnergy {
+				t.Errorf("Energy = %v, want %v", data.Energy, tt.wantEnergy)
+			}
+			if data.Sugars != tt.wantSugars {
+				t.Errorf("Sugars = %v, want %v", data.Sugars, tt.wantSugars)
+			}
+			if data.Fruits != tt.wantFruits {
+				t.Errorf("Fruits = %v, want %v", data.Fruits, tt.wantFruits)
+			}
+			if data.RedMeat != tt.wantRedMeat {
+				t.Errorf("RedMeat = %v, want %v", data.RedMeat, tt.wantRedMeat)
+			}
+			if dish.Name != tt.recipe.Name || dish.Source != "Recipe" {
+				t.Errorf("dish = %q from %q, want %q from Recipe", dish.Name, dish.Source, tt.recipe.Name)
+			}
+		})
+	}
+}
+
+// TestCalculateRecipe_Errors tests recipes that cannot be calculated
+func TestCalculateRecipe_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		recipe models.Recipe
+	}{
+		{"no ingredients", models.Recipe{Name: "Empty"}},
+		{"zero weight", models.Recipe{Name: "Weightless", Ingredients: []models.RecipeIngredient{{FoodID: "oats"}}}},
+		{"unknown ingredient", models.Recipe{Name: "Mystery", Ingredients: []models.RecipeIngredient{{FoodID: "kale", Grams: 10}}}},
+		{"negative yield", models.Recipe{Name: "Shrunk", YieldFactor: -1, Ingredients: []models.RecipeIngredient{{FoodID: "oats", Grams: 10}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := CalculateRecipe(tt.recipe, recipeIngredients); err == nil {
+				t.Error("CalculateRecipe() expected error, got nil")
+			}
+		})
+	}
+}
+
+// TestScoreRecipe tests that a recipe scores like a food with its derived data
+func TestScoreRecipe(t *testing.T) {
+	scorer := NewNutritionalScorer()
+	recipe := models.Recipe{Name: "Porridge", ScoreType: models.FoodType, Ingredients: []models.RecipeIngredient{
+		{FoodID: "oats", Grams: 50}, {FoodID: "banana", Grams: 50}}}
+
+	dish, err := CalculateRecipe(recipe, recipeIngredients)
+	if err != nil {
+		t.Fatalf("CalculateRecipe() unexpected error: %v", err)
+	}
+	want, err := scorer.CalculateScore(dish.NutritionalData, models.FoodType)
+	if err != nil {
+		t.Fatalf("CalculateScore() unexpected error: %v", err)
+	}
+
+	got, err := ScoreRecipe(scorer, recipe, recipeIngredients)
+	if err != nil {
+		t.Fatalf("ScoreRecipe() unexpected error: %v", err)
+	}
+	if got.Value != want.Value || got.Grade != want.Grade {
+		t.Errorf("ScoreRecipe() = %d (%s), want %d (%s)", got.Value, got.Grade, want.Value, want.Grade)
+	}
+}
@@ -0,0 +1,372 @@
+package core
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// maxReformulationStates limits how many candidate formulations the advisor evaluates
+const maxReformulationStates = 200000
+
+// reformulableNutrient describes how the advisor reads, changes and scores one nutrient
+type reformulableNutrient struct {
+	key       string
+	unit      string
+	negative  bool
+	get       func(data models.NutritionalData) float64
+	set       func(data *models.NutritionalData, value float64)
+	component func(breakdown models.ScoreBreakdown) models.ComponentScore
+}
+
+// reformulableNutrients lists the nutrients the advisor may change, in search order
+var reformulableNutrients = []reformulableNutrient{
+	{"energy", "kJ", true,
+		func(d models.NutritionalData) float64 { return float64(d.Energy) },
+		func(d *models.NutritionalData, v float64) { d.Energy = models.EnergyKJ(v) },
+		func(b models.ScoreBreakdown) models.ComponentScore { return b.Energy }},
+	{"sugars", "g", true,
+		func(d models.NutritionalData) float64 { return float64(d.Sugars) },
+		func(d *models.NutritionalData, v float64) { d.Sugars = models.SugarGram(v) },
+		func(b models.ScoreBreakdown) models.ComponentScore { return b.Sugars }},
+	{"saturated_fat", "g", true,
+		func(d models.NutritionalData) float64 { return float64(d.SaturatedFattyAcids) },
+		func(d *models.NutritionalData, v float64) { d.SaturatedFattyAcids = models.SaturatedFattyAcids(v) },
+		func(b models.ScoreBreakdown) models.ComponentScore { return b.SaturatedFat }},
+	{"sodium", "mg", true,
+		func(d models.NutritionalData) float64 { return float64(d.Sodium) },
+		func(d *models.NutritionalData, v float64) {
+			// Keep a declared salt value consistent with the new sodium
+			d.Sodium = models.SodiumMilligram(v)
+			d.Salt = d.Sodium.ToSalt()
+		},
+		func(b models.ScoreBreakdown) models.ComponentScore { return b.Sodium }},
+	{"fruits", "%", false,
+		func(d models.NutritionalData) float64 { return float64(d.Fruits) },
+		func(d *models.NutritionalData, v float64) { d.Fruits = models.FruitsPercent(v) },
+		func(b models.ScoreBreakdown) models.ComponentScore { return b.Fruits }},
+	{"fibre", "g", false,
+		func(d models.NutritionalData) float64 { return float64(d.Fibre) },
+		func(d *models.NutritionalData, v float64) { d.Fibre = models.FibreGram(v) },
+		func(b models.ScoreBreakdown) models.ComponentScore { return b.Fibre }},
+	{"protein", "g", false,
+		func(d models.NutritionalData) float64 { return float64(d.Protein) },
+		func(d *models.NutritionalData, v float64) { d.Protein = models.ProteinGram(v) },
+		func(b models.ScoreBreakdown) models.ComponentScore { return b.Protein }},
+}
+
+// Reformulate searches for the smallest nutrient changes that reach the target grade
+// Plans changing fewer nutrients are preferred, then plans with the smallest relative changes.
+// Candidate values are the band edges of the algorithm's point scales, so every change gains points
+func (ns *NutritionalScorer) Reformulate(data models.NutritionalData, foodType models.ScoreType, options models.ReformulationOptions) (models.ReformulationPlan, error) {
+	original, err := ns.CalculateScore(data, foodType)
+	if err != nil {
+		return models.ReformulationPlan{}, err
+	}
+
+	algorithm, err := ns.registry.Get(original.AlgorithmVersion)
+	if err != nil {
+		return models.ReformulationPlan{}, err
+	}
+
+	targetRank := gradeRank(algorithm, options.TargetGrade)
+	if targetRank < 0 && foodType != models.WaterType {
+		return models.ReformulationPlan{}, models.NewValidationError("target_grade",
+			fmt.Sprintf("Grade %q is not used by algorithm %s", options.TargetGrade, algorithm.Version),
+			"Use one of the grades A, B, C, D or E")
+	}
+
+	data = data.ResolveSodium()
+	plan := models.ReformulationPlan{
+		TargetGrade:  options.TargetGrade,
+		Original:     original,
+		Reformulated: original,
+		Data:         data,
+	}
+
+	// Water always has the best grade and there is nothing to reformulate
+	if foodType == models.WaterType || gradeRank(algorithm, original.Grade) <= targetRank {
+		plan.Achievable = true
+		return plan, nil
+	}
+
+	advisor := &reformulationAdvisor{
+		calculator: NewScoreCalculatorWithAlgorithm(algorithm),
+		algorithm:  algorithm,
+		foodType:   foodType,
+		targetRank: targetRank,
+		data:       data,
+	}
+	advisor.candidates = advisor.candidateValues(options, ns.validator.RulesFor("", foodType))
+
+	levels, found, truncated := advisor.search()
+	if !found {
+		return plan, nil
+	}
+
+	reformulated := advisor.apply(levels)
+	result, err := ns.CalculateScore(reformulated, foodType)
+	if err != nil {
+		return models.ReformulationPlan{}, err
+	}
+
+	plan.Achievable = true
+	plan.Truncated = truncated
+	plan.Reformulated = result
+	plan.Data = reformulated
+	for i, nutrient := range reformulableNutrients {
+		if levels[i] == 0 {
+			continue
+		}
+		before := nutrient.component(original.Breakdown).Points
+		after := nutrient.component(result.Breakdown).Points
+		gain := before - after
+		if !nutrient.negative {
+			gain = after - before
+		}
+		plan.Changes = append(plan.Changes, models.NutrientChange{
+			Nutrient:   nutrient.key,
+			Unit:       nutrient.unit,
+			From:       nutrient.get(data),
+			To:         nutrient.get(reformulated),
+			PointsGain: gain,
+		})
+	}
+
+	return plan, nil
+}
+
+// gradeRank returns the position of a grade in the algorithm's boundaries (0 is best)
+// Returns -1 for grades the algorithm does not use
+func gradeRank(algorithm *Algorithm, grade string) int {
+	for i, boundary := range algorithm.Grades {
+		if boundary.Grade == grade {
+			return i
+		}
+	}
+	return -1
+}
+
+// reformulationAdvisor holds the state of one reformulation search
+type reformulationAdvisor struct {
+	calculator *ScoreCalculator
+	algorithm  *Algorithm
+	foodType   models.ScoreType
+	targetRank int
+	data       models.NutritionalData
+	candidates [][]float64 // Candidate values per nutrient, nearest first
+}
+
+// candidateValues lists the values worth trying for every nutrient
+// Negative nutrients are lowered to band edges, positive nutrients raised just above them;
+// positive nutrients stay within the maxima of the validation rules
+func (ra *reformulationAdvisor) candidateValues(options models.ReformulationOptions, limits models.NutritionalDataValidation) [][]float64 {
+	locked := make(map[string]bool, len(options.Locked))
+	for _, key := range options.Locked {
+		locked[key] = true
+	}
+	maxima := map[string]float64{"fruits": limits.FruitsMax, "fibre": limits.FibreMax, "protein": limits.ProteinMax}
+
+	candidates := make([][]float64, len(reformulableNutrients))
+	for i, nutrient := range reformulableNutrients {
+		if locked[nutrient.key] {
+			continue
+		}
+		// Beverages only count their fruit points unless the algorithm counts every positive component
+		if ra.foodType == models.BeverageType && !ra.algorithm.BeveragePositives && (nutrient.key == "fibre" || nutrient.key == "protein") {
+			continue
+		}
+		current := nutrient.get(ra.data)
+		bound := options.Bounds[nutrient.key]
+
+		var values []float64
+		for _, edge := range ra.bandEdges(nutrient.key) {
+			value := edge
+			if !nutrient.negative {
+				// Positive nutrients must exceed the threshold, use the next 0.1 step
+				value = math.Round(math.Floor(edge*10+1e-9)+1) / 10
+			}
+			if nutrient.negative && value >= current || !nutrient.negative && value <= current {
+				continue
+			}
+			if bound.Min != nil && value < *bound.Min || bound.Max != nil && value > *bound.Max {
+				continue
+			}
+			if maximum, ok := maxima[nutrient.key]; ok && value > maximum {
+				continue
+			}
+			values = append(values, value)
+		}
+
+		// Nearest values first
+		sort.Slice(values, func(a, b int) bool {
+			return math.Abs(values[a]-current) < math.Abs(values[b]-current)
+		})
+		candidates[i] = dedupe(values)
+	}
+
+	return candidates
+}
+
+// bandEdges returns the thresholds of a nutrient expressed in the units of NutritionalData
+// Fats and oils scored on ratios or energy from saturates are converted back to grams
+func (ra *reformulationAdvisor) bandEdges(key string) []float64 {
+	switch key {
+	case "energy":
+		if ra.algorithm.EnergyFromSaturates {
+			// Energy follows saturated fat and cannot be changed on its own
+			return nil
+		}
+		return scaleEdges(ra.algorithm.Energy, 1)
+	case "sugars":
+		return scaleEdges(ra.algorithm.Sugars, 1)
+	case "saturated_fat":
+		var edges []float64
+		if ra.algorithm.SaturatedFatRatio {
+			edges = append(edges, scaleEdges(ra.algorithm.SaturatedFat, float64(ra.data.TotalFat)/100)...)
+		} else {
+			edges = append(edges, scaleEdges(ra.algorithm.SaturatedFat, 1)...)
+		}
+		if ra.algorithm.EnergyFromSaturates {
+			edges = append(edges, scaleEdges(ra.algorithm.Energy, 1/kilojoulesPerGramFat)...)
+		}
+		return edges
+	case "sodium":
+		return scaleEdges(ra.algorithm.Sodium, 1)
+	case "fruits":
+		return ra.algorithm.Fruits.Thresholds
+	case "fibre":
+		return ra.algorithm.Fibre.Thresholds
+	case "protein":
+		return ra.algorithm.Protein.Thresholds
+	}
+	return nil
+}
+
+// scaleEdges converts the thresholds of a negative scale with the given factor
+// Inclusive thresholds are scored once reached, so the edge moves to the previous 0.1 step
+func scaleEdges(scale PointScale, factor float64) []float64 {
+	edges := make([]float64, len(scale.Thresholds))
+	for i, threshold := range scale.Thresholds {
+		edges[i] = threshold * factor
+		if scale.Inclusive {
+			edges[i] = math.Round(math.Ceil(edges[i]*10-1e-9)-1) / 10
+		}
+	}
+	return edges
+}
+
+// apply returns the nutritional data with the candidate values selected by levels
+// Level 0 keeps the current value, level n uses candidate n-1
+func (ra *reformulationAdvisor) apply(levels []int) models.NutritionalData {
+	data := ra.data
+	for i, level := range levels {
+		if level > 0 {
+			reformulableNutrients[i].set(&data, ra.candidates[i][level-1])
+		}
+	}
+	return data
+}
+
+// reachesTarget scores the candidate formulation with the algorithm's point scales
+func (ra *reformulationAdvisor) reachesTarget(levels []int) bool {
+	breakdown := ra.calculator.CalculateBreakdown(ra.apply(levels), ra.foodType)
+	score := ra.calculator.GetFinalScoreFromBreakdown(breakdown, ra.foodType)
+	return gradeRank(ra.algorithm, ra.algorithm.Grade(score)) <= ra.targetRank
+}
+
+// search runs a uniform-cost search over candidate formulations
+// Each nutrient is changed at most once and nutrients are changed in list order, so every
+// combination is visited exactly once. The best value of every nutrient is returned, marked
+// as truncated, if the search limit is reached first
+func (ra *reformulationAdvisor) search() (levels []int, found bool, truncated bool) {
+	// Give up early if even the best value of every nutrient cannot reach the target
+	best := make([]int, len(reformulableNutrients))
+	for i, values := range ra.candidates {
+		best[i] = len(values)
+	}
+	if !ra.reachesTarget(best) {
+		return nil, false, false
+	}
+
+	queue := &reformulationQueue{{levels: make([]int, len(reformulableNutrients)), next: 0}}
+	for evaluated := 0; queue.Len() > 0 && evaluated < maxReformulationStates; evaluated++ {
+		state := heap.Pop(queue).(reformulationState)
+		if state.changed > 0 && ra.reachesTarget(state.levels) {
+			return state.levels, true, false
+		}
+
+		for i := state.next; i < len(reformulableNutrients); i++ {
+			current := reformulableNutrients[i].get(ra.data)
+			for level, value := range ra.candidates[i] {
+				levels := append([]int(nil), state.levels...)
+				levels[i] = level + 1
+				heap.Push(queue, reformulationState{
+					levels:  levels,
+					next:    i + 1,
+					changed: state.changed + 1,
+					cost:    state.cost + relativeChange(current, value),
+				})
+			}
+		}
+	}
+
+	// The search ended without a minimal plan, fall back to the best value of every nutrient
+	return best, true, true
+}
+
+// relativeChange measures a change relative to the larger of the two values
+func relativeChange(from, to float64) float64 {
+	largest := math.Max(math.Abs(from), math.Abs(to))
+	if largest == 0 {
+		return 0
+	}
+	return math.Abs(to-from) / largest
+}
+
+// dedupe removes repeated values from a slice, keeping the first occurrence
+func dedupe(values []float64) []float64 {
+	var unique []float64
+	seen := make(map[float64]bool, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+// reformulationState is one candidate formulation in the search
+type reformulationState struct {
+	levels  []int   // Selected candidate per nutrient (0 keeps the current value)
+	next    int     // First nutrient that may still be changed
+	changed int     // Number of nutrients changed
+	cost    float64 // Sum of relative changes
+}
+
+// reformulationQueue orders states by number of changed nutrients, then by total change
+type reformulationQueue []reformulationState
+
+func (q reformulationQueue) Len() int { return len(q) }
+
+func (q reformulationQueue) Less(i, j int) bool {
+	if q[i].changed != q[j].changed {
+		return q[i].changed < q[j].changed
+	}
+	return q[i].cost < q[j].cost
+}
+
+func (q reformulationQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *reformulationQueue) Push(x interface{}) { *q = append(*q, x.(reformulationState)) }
+
+func (q *reformulationQueue) Pop() interface{} {
+	old := *q
+	state := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return state
+}
@@ -0,0 +1,154 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// TestNutritionalScorer_Reformulate tests the search for minimal changes reaching a grade
+func TestNutritionalScorer_Reformulate(t *testing.T) {
+	scorer := NewNutritionalScorer()
+
+	// Breakfast cereal: 1600 kJ, 25g sugars, 2g saturated fat, 300mg sodium, 3g fibre, 8g protein
+	// Scores 4 + 5 + 1 + 3 = 13 negative points, 3 fibre points (protein excluded): 10 (grade C)
+	cereal := models.NutritionalData{
+		Energy:              models.EnergyKJ(1600),
+		Sugars:              models.SugarGram(25),
+		SaturatedFattyAcids: models.SaturatedFattyAcids(2),
+		Sodium:              models.SodiumMilligram(300),
+		Fibre:               models.FibreGram(3),
+		Protein:             models.ProteinGram(8),
+	}
+
+	original, _ := scorer.CalculateScore(cereal, models.FoodType)
+	if original.Grade != "C" {
+		t.Fatalf("Test product grade = %s (score %d), want C", original.Grade, original.Value)
+	}
+
+	tests := []struct {
+		name            string
+		options         models.ReformulationOptions
+		wantAchievable  bool
+		wantChanges     int
+		forbiddenChange string
+	}{
+		{"Already At Target", models.ReformulationOptions{TargetGrade: "D"}, true, 0, ""},
+		{"Target B", models.ReformulationOptions{TargetGrade: "B"}, true, 1, ""},
+		{"Target B With Sugars Locked", models.ReformulationOptions{TargetGrade: "B", Locked: []string{"sugars"}}, true, 1, "sugars"},
+		{"Target A", models.ReformulationOptions{TargetGrade: "A"}, true, 2, ""},
+		{"Everything Locked", models.ReformulationOptions{TargetGrade: "A",
+			Locked: []string{"energy", "sugars", "saturated_fat", "sodium", "fruits", "fibre", "protein"}}, false, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := scorer.Reformulate(cereal, models.FoodType, tt.options)
+			if err != nil {
+				t.Fatalf("Reformulate() unexpected error: %v", err)
+			}
+			if plan.Achievable != tt.wantAchievable {
+				t.Fatalf("Reformulate() achievable = %v, want %v", plan.Achievable, tt.wantAchievable)
+			}
+			if len(plan.Changes) != tt.wantChanges {
+				t.Errorf("Reformulate() changes = %+v, want %d changes", plan.Changes, tt.wantChanges)
+			}
+			if plan.Truncated {
+				t.Error("Reformulate() should finish the search for a single product")
+			}
+			for _, change := range plan.Changes {
+				if change.Nutrient == tt.forbiddenChange {
+					t.Errorf("Reformulate() changed locked nutrient %s", change.Nutrient)
+				}
+				if change.PointsGain <= 0 {
+					t.Errorf("Change %+v should gain points", change)
+				}
+			}
+			if plan.Achievable && gradeRank(newAlgorithm2017(), plan.Reformulated.Grade) > gradeRank(newAlgorithm2017(), tt.options.TargetGrade) {
+				t.Errorf("Reformulated grade = %s, want %s or better", plan.Reformulated.Grade, tt.options.TargetGrade)
+			}
+		})
+	}
+
+	t.Run("Smallest Single Change", func(t *testing.T) {
+		// Cutting sugars to 9g removes 4 points and lifts the protein exclusion (score 2, grade B)
+		plan, _ := scorer.Reformulate(cereal, models.FoodType, models.ReformulationOptions{TargetGrade: "B"})
+		if len(plan.Changes) != 1 || plan.Changes[0].Nutrient != "sugars" || plan.Changes[0].To != 9 {
+			t.Fatalf("Reformulate() changes = %+v, want sugars reduced to 9g", plan.Changes)
+		}
+
+		// The returned data reproduces the reformulated score
+		result, err := scorer.CalculateScore(plan.Data, models.FoodType)
+		if err != nil {
+			t.Fatalf("CalculateScore() unexpected error: %v", err)
+		}
+		if result.Value != 2 || result.Value != plan.Reformulated.Value {
+			t.Errorf("Reformulated score = %d, want 2", result.Value)
+		}
+	})
+
+	t.Run("Bounds Are Respected", func(t *testing.T) {
+		minSugars := 15.0
+		options := models.ReformulationOptions{
+			TargetGrade: "B",
+			Bounds:      map[string]models.NutrientBound{"sugars": {Min: &minSugars}},
+		}
+		plan, err := scorer.Reformulate(cereal, models.FoodType, options)
+		if err != nil {
+			t.Fatalf("Reformulate() unexpected error: %v", err)
+		}
+		if float64(plan.Data.Sugars) < minSugars {
+			t.Errorf("Reformulated sugars = %v, want at least %v", plan.Data.Sugars, minSugars)
+		}
+	})
+
+	t.Run("Search Limit", func(t *testing.T) {
+		// Lifting a grade E product without positive nutrients to A exceeds the search limit
+		product := models.NutritionalData{
+			Energy:              models.EnergyKJ(3000),
+			Sugars:              models.SugarGram(45),
+			SaturatedFattyAcids: models.SaturatedFattyAcids(10),
+			Sodium:              models.SodiumMilligram(900),
+		}
+		plan, err := scorer.Reformulate(product, models.FoodType, models.ReformulationOptions{TargetGrade: "A"})
+		if err != nil {
+			t.Fatalf("Reformulate() unexpected error: %v", err)
+		}
+		if !plan.Achievable || !plan.Truncated {
+			t.Errorf("Reformulate() achievable = %v, truncated = %v, want both true", plan.Achievable, plan.Truncated)
+		}
+	})
+
+	t.Run("Validation Profile Bounds", func(t *testing.T) {
+		// Only fibre can move: 3.6g adds the point lifting the grade D variant (score 11) to C
+		salty := cereal
+		salty.Sodium = models.SodiumMilligram(400)
+		options := models.ReformulationOptions{
+			TargetGrade: "C",
+			Locked:      []string{"energy", "sugars", "saturated_fat", "sodium", "fruits", "protein"},
+		}
+		if plan, _ := scorer.Reformulate(salty, models.FoodType, options); !plan.Achievable {
+			t.Fatal("Reformulate() should reach grade C by adding fibre")
+		}
+
+		// A profile capping fibre at 3.5g leaves no candidate
+		rules := models.DefaultValidationRules()
+		rules.FibreMax = 3.5
+		profiled := NewNutritionalScorer()
+		profiled.validator = NewInputValidatorWithProfiles(models.ValidationProfiles{Default: rules})
+
+		plan, err := profiled.Reformulate(salty, models.FoodType, options)
+		if err != nil {
+			t.Fatalf("Reformulate() unexpected error: %v", err)
+		}
+		if plan.Achievable {
+			t.Errorf("Reformulate() changes = %+v, want fibre kept within %vg", plan.Changes, rules.FibreMax)
+		}
+	})
+
+	t.Run("Invalid Target Grade", func(t *testing.T) {
+		if _, err := scorer.Reformulate(cereal, models.FoodType, models.ReformulationOptions{TargetGrade: "F"}); err == nil {
+			t.Error("Reformulate() should reject an unknown grade")
+		}
+	})
+}
@@ -0,0 +1,306 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// Ruleset is a data-driven description of the scoring rules
+// It bundles every algorithm version with its point scales and grade boundaries so that
+// a regulatory update can ship as a JSON file instead of a code change
+type Ruleset struct {
+	Name           string       `json:"name"`                      // Human-readable ruleset name
+	Version        string       `json:"version"`                   // Version of the ruleset file itself
+	Description    string       `json:"description,omitempty"`     // Optional description of the ruleset
+	DefaultVersion string       `json:"default_algorithm_version"` // Algorithm version or family used by default
+	Algorithms     []*Algorithm `json:"algorithms"`                // Algorithm versions defined by the ruleset
+}
+
+// scoreTypeName returns the name of a score type in ruleset and validation profiles files
+// A switch rather than a map, because the built-in ruleset checksum is computed during
+// package initialisation
+func scoreTypeName(scoreType models.ScoreType) (string, bool) {
+	switch scoreType {
+	case models.FoodType:
+		return "food", true
+	case models.BeverageType:
+		return "beverage", true
+	case models.WaterType:
+		return "water", true
+	case models.CheeseType:
+		return "cheese", true
+	case models.FatsOilsType:
+		return "fats_oils", true
+	default:
+		return "", false
+	}
+}
+
+// algorithmFields has the fields of Algorithm without its JSON methods
+type algorithmFields Algorithm
+
+// algorithmFile is the file representation of an Algorithm, with score types stored by name
+type algorithmFile struct {
+	*algorithmFields
+	ScoreTypes []string `json:"score_types"`
+}
+
+// MarshalJSON encodes the algorithm with its score types stored by name
+func (a *Algorithm) MarshalJSON() ([]byte, error) {
+	file := algorithmFile{algorithmFields: (*algorithmFields)(a)}
+	for _, scoreType := range a.ScoreTypes {
+		name, found := scoreTypeName(scoreType)
+		if !found {
+			return nil, fmt.Errorf("algorithm %s: unknown score type %d", a.Version, scoreType)
+		}
+		file.ScoreTypes = append(file.ScoreTypes, name)
+	}
+	return json.Marshal(file)
+}
+
+// UnmarshalJSON decodes an algorithm whose score types are stored by name
+func (a *Algorithm) UnmarshalJSON(data []byte) error {
+	file := algorithmFile{algorithmFields: (*algorithmFields)(a)}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return err
+	}
+
+	a.ScoreTypes = nil
+	for _, name := range file.ScoreTypes {
+		scoreType, found := scoreTypeByName(name)
+		if !found {
+			return fmt.Errorf("algorithm %s: unknown score type %q", a.Version, name)
+		}
+		a.ScoreTypes = append(a.ScoreTypes, scoreType)
+	}
+	return nil
+}
+
+// scoreTypeByName returns the score type a ruleset or validation profiles file refers to by name
+func scoreTypeByName(name string) (models.ScoreType, bool) {
+	for scoreType := models.FoodType; scoreType <= models.FatsOilsType; scoreType++ {
+		if candidate, _ := scoreTypeName(scoreType); candidate == name {
+			return scoreType, true
+		}
+	}
+	return 0, false
+}
+
+// DefaultRuleset returns the built-in official Nutri-Score rules
+func DefaultRuleset() *Ruleset {
+	return &Ruleset{
+		Name:           "Nutri-Score",
+		Version:        "1.0",
+		Description:    "Official Nutri-Score algorithms (2017 and 2023 revisions)",
+		DefaultVersion: DefaultAlgorithmVersion,
+		Algorithms:     builtinAlgorithms(),
+	}
+}
+
+// LoadRuleset reads and validates a ruleset JSON file
+func LoadRuleset(path string) (*Ruleset, error) {
+	fileData, err := os.ReadFile(path)
+	if err != nil {
+		return nil, models.NewConfigError("Failed to read ruleset file", err.Error())
+	}
+	return ParseRuleset(fileData)
+}
+
+// ParseRuleset decodes and validates a ruleset from JSON data
+func ParseRuleset(data []byte) (*Ruleset, error) {
+	var ruleset Ruleset
+	if err := json.Unmarshal(data, &ruleset); err != nil {
+		return nil, models.NewConfigError("Failed to parse ruleset JSON", err.Error())
+	}
+
+	if err := ruleset.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &ruleset, nil
+}
+
+// Checksum returns the SHA-256 checksum identifying the ruleset contents
+// The canonical JSON encoding is hashed, so formatting differences in the source file do not matter
+func (rs *Ruleset) Checksum() string {
+	canonical, err := json.Marshal(rs)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])
+}
+
+// Save writes the ruleset to a JSON file
+func (rs *Ruleset) Save(path string) error {
+	jsonData, err := json.MarshalIndent(rs, "", "  ")
+	if err != nil {
+		return models.NewConfigError("Failed to encode ruleset", err.Error())
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return models.NewConfigError("Failed to create ruleset directory", err.Error())
+	}
+
+	if err := os.WriteFile(path, jsonData, 0644); err != nil {
+		return models.NewConfigError("Failed to write ruleset file", err.Error())
+	}
+
+	return nil
+}
+
+// Registry builds an algorithm registry containing the ruleset's algorithms
+func (rs *Ruleset) Registry() (*AlgorithmRegistry, error) {
+	registry := NewAlgorithmRegistry()
+	for _, algorithm := range rs.Algorithms {
+		if err := registry.Register(algorithm); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+// Validate checks that the ruleset is complete and consistent
+// Every scale must have bands with strictly increasing thresholds and non-decreasing
+// points, and every algorithm must define increasing grade boundaries
+func (rs *Ruleset) Validate() error {
+	collection := models.ErrorCollection{Operation: "ruleset validation"}
+
+	if rs.Name == "" {
+		collection.AddError(models.NewConfigError("Ruleset name is required", ""))
+	}
+	if len(rs.Algorithms) == 0 {
+		collection.AddError(models.NewConfigError("Ruleset defines no algorithms", ""))
+	}
+
+	versions := make(map[string]bool)
+	for i, algorithm := range rs.Algorithms {
+		if algorithm == nil {
+			collection.AddError(models.NewConfigError(fmt.Sprintf("Algorithm %d is empty", i), ""))
+			continue
+		}
+		if algorithm.Version == "" {
+			collection.AddError(models.NewConfigError(fmt.Sprintf("Algorithm %d has no version", i), ""))
+		} else if versions[algorithm.Version] {
+			collection.AddError(models.NewConfigError(
+				fmt.Sprintf("Algorithm version %s is defined more than once", algorithm.Version), ""))
+		}
+		versions[algorithm.Version] = true
+
+		for _, err := range validateAlgorithm(algorithm) {
+			collection.AddError(err)
+		}
+	}
+
+	if rs.DefaultVersion != "" && len(collection.Errors) == 0 {
+		registry, err := rs.Registry()
+		if err == nil {
+			if _, err = registry.Resolve(rs.DefaultVersion, models.FoodType); err != nil {
+				collection.AddError(models.NewConfigError(
+					fmt.Sprintf("Default algorithm version %s is not defined", rs.DefaultVersion), err.Error()))
+			}
+		}
+	}
+
+	if collection.HasErrors() {
+		collection.Summary = collection.Errors[0].Message
+		return collection
+	}
+	return nil
+}
+
+// validateAlgorithm checks the scales and grade boundaries of a single algorithm
+func validateAlgorithm(algorithm *Algorithm) []models.NutritionalError {
+	var errors []models.NutritionalError
+
+	if len(algorithm.ScoreTypes) == 0 {
+		errors = append(errors, models.NewConfigError(
+			fmt.Sprintf("Algorithm %s applies to no score type", algorithm.Version), ""))
+	}
+
+	scales := []struct {
+		name  string
+		scale PointScale
+	}{
+		{"energy", algorithm.Energy},
+		{"sugars", algorithm.Sugars},
+		{"saturated_fat", algorithm.SaturatedFat},
+		{"sodium", algorithm.Sodium},
+		{"fruits", algorithm.Fruits},
+		{"fibre", algorithm.Fibre},
+		{"protein", algorithm.Protein},
+	}
+	for _, s := range scales {
+		if err := validateScale(s.scale); err != "" {
+			errors = append(errors, models.NutritionalError{
+				Type:    models.ConfigErrorType,
+				Message: fmt.Sprintf("Algorithm %s: %s scale %s", algorithm.Version, s.name, err),
+				Field:   s.name,
+				Code:    "CONFIG_ERROR",
+			})
+		}
+	}
+
+	if algorithm.SweetenerPenalty < 0 {
+		errors = append(errors, models.NewConfigError(
+			fmt.Sprintf("Algorithm %s: sweetener penalty cannot be negative", algorithm.Version), ""))
+	}
+
+	if len(algorithm.Grades) == 0 {
+		errors = append(errors, models.NewConfigError(
+			fmt.Sprintf("Algorithm %s defines no grade boundaries", algorithm.Version), ""))
+	}
+	grades := make(map[string]bool)
+	for i, boundary := range algorithm.Grades {
+		if boundary.Grade == "" || grades[boundary.Grade] {
+			errors = append(errors, models.NewConfigError(
+				fmt.Sprintf("Algorithm %s: grade %d is empty or duplicated", algorithm.Version, i), ""))
+		}
+		grades[boundary.Grade] = true
+
+		// The last grade has no upper boundary
+		if i > 0 && i < len(algorithm.Grades)-1 && boundary.MaxScore <= algorithm.Grades[i-1].MaxScore {
+			errors = append(errors, models.NewConfigError(
+				fmt.Sprintf("Algorithm %s: grade boundaries must increase (grade %s)", algorithm.Version, boundary.Grade), ""))
+		}
+	}
+
+	return errors
+}
+
+// validateScale checks that a point scale is complete and monotonic
+// Returns a description of the problem, or an empty string if the scale is valid
+func validateScale(scale PointScale) string {
+	if len(scale.Thresholds) == 0 {
+		return "has no bands"
+	}
+	if len(scale.Thresholds) != len(scale.Points) {
+		return fmt.Sprintf("has %d thresholds but %d point values", len(scale.Thresholds), len(scale.Points))
+	}
+	for i := range scale.Thresholds {
+		if scale.Points[i] < 0 {
+			return "awards negative points"
+		}
+		if i == 0 {
+			continue
+		}
+		if scale.Thresholds[i] <= scale.Thresholds[i-1] {
+			return fmt.Sprintf("thresholds must increase (%v after %v)", scale.Thresholds[i], scale.Thresholds[i-1])
+		}
+		if scale.Points[i] < scale.Points[i-1] {
+			return fmt.Sprintf("points must not decrease (%d after %d)", scale.Points[i], scale.Points[i-1])
+		}
+	}
+	return ""
+}
+
+// GetDefaultRulesetPath returns the default path for a custom ruleset file
+func GetDefaultRulesetPath() string {
+	return filepath.Join("data", "rulesets", "nutriscore.json")
+}
@@ -0,0 +1,177 @@
+package core
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// TestLoadRuleset tests that the shipped ruleset file matches the built-in rules
+func TestLoadRuleset(t *testing.T) {
+	ruleset, err := LoadRuleset(filepath.Join("..", "..", GetDefaultRulesetPath()))
+	if err != nil {
+		t.Fatalf("LoadRuleset() unexpected error: %v", err)
+	}
+
+	if ruleset.Checksum() != DefaultRuleset().Checksum() {
+		t.Error("Shipped ruleset checksum should match the built-in ruleset")
+	}
+	if len(ruleset.Checksum()) != 64 {
+		t.Errorf("Checksum() length = %d, want 64", len(ruleset.Checksum()))
+	}
+
+	if _, err := LoadRuleset("missing.json"); err == nil {
+		t.Error("LoadRuleset() should fail for a missing file")
+	}
+}
+
+// TestRuleset_SaveAndLoad tests that a saved ruleset loads with the same checksum
+func TestRuleset_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	ruleset := DefaultRuleset()
+
+	if err := ruleset.Save(path); err != nil {
+		t.Fatalf("Save() unexpected error: %v", err)
+	}
+
+	loaded, err := LoadRuleset(path)
+	if err != nil {
+		t.Fatalf("LoadRuleset() unexpected error: %v", err)
+	}
+	if loaded.Checksum() != ruleset.Checksum() {
+		t.Errorf("Checksum() = %s, want %s", loaded.Checksum(), ruleset.Checksum())
+	}
+}
+
+// TestParseRuleset_ScoreTypes tests that score types are stored by name
+func TestParseRuleset_ScoreTypes(t *testing.T) {
+	encoded, err := json.Marshal(DefaultRuleset())
+	if err != nil {
+		t.Fatalf("json.Marshal() unexpected error: %v", err)
+	}
+	if !strings.Contains(string(encoded), `"score_types":["food","cheese"]`) {
+		t.Error("Score types should be encoded by name")
+	}
+
+	ruleset, err := ParseRuleset(encoded)
+	if err != nil {
+		t.Fatalf("ParseRuleset() unexpected error: %v", err)
+	}
+	if scoreTypes := ruleset.Algorithms[0].ScoreTypes; len(scoreTypes) != 2 || scoreTypes[1] != models.CheeseType {
+		t.Errorf("ScoreTypes = %v, want [Food Cheese]", scoreTypes)
+	}
+
+	unknown := strings.Replace(string(encoded), `"cheese"`, `"snacks"`, 1)
+	if _, err := ParseRuleset([]byte(unknown)); err == nil {
+		t.Error("ParseRuleset() should reject an unknown score type")
+	}
+}
+
+// TestRuleset_Validate tests rejection of inconsistent rulesets
+func TestRuleset_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(rs *Ruleset)
+		wantErr bool
+	}{
+		{"Default Ruleset", func(rs *Ruleset) {}, false},
+		{"Non-Monotonic Thresholds", func(rs *Ruleset) {
+			rs.Algorithms[0].Sugars = PointScale{Thresholds: []float64{4.5, 9, 8}, Points: []int{1, 2, 3}}
+		}, true},
+		{"Decreasing Points", func(rs *Ruleset) {
+			rs.Algorithms[0].Fruits = PointScale{Thresholds: []float64{40, 60, 80}, Points: []int{2, 1, 5}}
+		}, true},
+		{"Missing Bands", func(rs *Ruleset) {
+			rs.Algorithms[0].Sodium = PointScale{}
+		}, true},
+		{"Mismatched Lengths", func(rs *Ruleset) {
+			rs.Algorithms[0].Energy = PointScale{Thresholds: []float64{335, 670}, Points: []int{1}}
+		}, true},
+		{"Grade Boundaries Not Increasing", func(rs *Ruleset) {
+			rs.Algorithms[0].Grades[2].MaxScore = rs.Algorithms[0].Grades[1].MaxScore
+		}, true},
+		{"No Grades", func(rs *Ruleset) {
+			rs.Algorithms[0].Grades = nil
+		}, true},
+		{"Duplicate Version", func(rs *Ruleset) {
+			rs.Algorithms[1].Version = rs.Algorithms[0].Version
+		}, true},
+		{"Unknown Default Version", func(rs *Ruleset) {
+			rs.DefaultVersion = "1999"
+		}, true},
+		{"No Algorithms", func(rs *Ruleset) {
+			rs.Algorithms = nil
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ruleset := DefaultRuleset()
+			tt.modify(ruleset)
+
+			err := ruleset.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if _, ok := err.(models.ErrorCollection); !ok {
+					t.Errorf("Validate() error type = %T, want models.ErrorCollection", err)
+				}
+			}
+		})
+	}
+}
+
+// TestNewNutritionalScorerWithRuleset tests scoring with a custom ruleset
+func TestNewNutritionalScorerWithRuleset(t *testing.T) {
+	data := models.NutritionalData{Energy: 1000, Sugars: 10, Sodium: 200}
+
+	defaultResult, err := NewNutritionalScorer().CalculateScore(data, models.FoodType)
+	if err != nil {
+		t.Fatalf("CalculateScore() unexpected error: %v", err)
+	}
+	if defaultResult.RulesetChecksum != DefaultRuleset().Checksum() {
+		t.Error("Default scorer should record the built-in ruleset checksum")
+	}
+
+	// Tighten the 2017 sugar scale so every gram above zero earns 10 points
+	ruleset := DefaultRuleset()
+	ruleset.Algorithms[0].Sugars = PointScale{Thresholds: []float64{0}, Points: []int{10}}
+	scorer, err := NewNutritionalScorerWithRuleset(ruleset)
+	if err != nil {
+		t.Fatalf("NewNutritionalScorerWithRuleset() unexpected error: %v", err)
+	}
+
+	result, err := scorer.CalculateScore(data, models.FoodType)
+	if err != nil {
+		t.Fatalf("CalculateScore() unexpected error: %v", err)
+	}
+	if result.Breakdown.Sugars.Points != 10 {
+		t.Errorf("Sugar points = %d, want 10", result.Breakdown.Sugars.Points)
+	}
+	if result.RulesetChecksum == "" || result.RulesetChecksum == defaultResult.RulesetChecksum {
+		t.Error("Custom ruleset checksum should be recorded and differ from the default")
+	}
+
+	// Water results also record the checksum
+	water, _ := scorer.CalculateScore(models.NutritionalData{}, models.WaterType)
+	if water.RulesetChecksum != scorer.GetRulesetChecksum() {
+		t.Errorf("Water RulesetChecksum = %s, want %s", water.RulesetChecksum, scorer.GetRulesetChecksum())
+	}
+
+	// The checksum survives JSON serialisation of the result
+	encoded, _ := json.Marshal(result)
+	var decoded models.NutritionalScore
+	if err := json.Unmarshal(encoded, &decoded); err != nil || decoded.RulesetChecksum != result.RulesetChecksum {
+		t.Error("RulesetChecksum should round-trip through JSON")
+	}
+
+	invalid := DefaultRuleset()
+	invalid.Algorithms[0].Energy = PointScale{}
+	if _, err := NewNutritionalScorerWithRuleset(invalid); err == nil {
+		t.Error("NewNutritionalScorerWithRuleset() should reject an invalid ruleset")
+	}
+}
@@ -1,67 +1,182 @@
 package core
 
 import (
-	"context"
 	"fmt"
-	"nutritional-score/pkg/models"
+
+	"github.com/nutritional-score/pkg/models"
 )
 
 // NutritionalScorer implements the official Nutri-Score algorithm
 // This struct provides accurate nutritional scoring based on the French ANSES guidelines
 type NutritionalScorer struct {
-	calculator models.ScoreCalculator
-	validator  models.InputValidator
+	registry  *AlgorithmRegistry
+	version   string
+	checksum  string
+	validator models.InputValidator
 }
 
 // NewNutritionalScorer creates a new instance of the nutritional scorer
 // Initializes with the official Nutri-Score calculator and validator
 func NewNutritionalScorer() *NutritionalScorer {
 	return &NutritionalScorer{
-		calculator: NewScoreCalculator(),
-		validator:  NewInputValidator(),
+		registry:  DefaultAlgorithmRegistry(),
+		version:   DefaultAlgorithmVersion,
+		checksum:  defaultRulesetChecksum,
+		validator: NewInputValidator(),
+	}
+}
+
+// NewNutritionalScorerWithRuleset creates a scorer that uses the rules of a custom ruleset
+// The ruleset is validated first and its checksum is recorded with every score
+func NewNutritionalScorerWithRuleset(ruleset *Ruleset) (*NutritionalScorer, error) {
+	if err := ruleset.Validate(); err != nil {
+		return nil, err
+	}
+
+	registry, err := ruleset.Registry()
+	if err != nil {
+		return nil, err
+	}
+
+	version := ruleset.DefaultVersion
+	if version == "" {
+		version = ruleset.Algorithms[0].Version
+	}
+
+	return &NutritionalScorer{
+		registry:  registry,
+		version:   version,
+		checksum:  ruleset.Checksum(),
+		validator: NewInputValidator(),
+	}, nil
+}
+
+// NewNutritionalScorerWithVersion creates a scorer that uses a specific algorithm version
+// The version can be an exact version (e.g. "2023-food") or a family (e.g. "2023")
+func NewNutritionalScorerWithVersion(version string) (*NutritionalScorer, error) {
+	scorer := NewNutritionalScorer()
+	if err := scorer.SetAlgorithmVersion(version); err != nil {
+		return nil, err
 	}
+	return scorer, nil
+}
+
+// NewScorer creates a scorer for the named scheme ("nutriscore" or "hsr")
+func NewScorer(scheme string) (models.NutritionalScorer, error) {
+	switch scheme {
+	case models.SchemeNutriScore, "":
+		return NewNutritionalScorer(), nil
+	case models.SchemeHealthStarRating:
+		return NewHealthStarRatingScorer(), nil
+	default:
+		return nil, models.NewConfigError(fmt.Sprintf("Unknown scoring scheme: %s", scheme),
+			fmt.Sprintf("available schemes: %v", AvailableSchemes()))
+	}
+}
+
+// AvailableSchemes returns the names of the scoring schemes NewScorer accepts
+func AvailableSchemes() []string {
+	return []string{models.SchemeNutriScore, models.SchemeHealthStarRating}
+}
+
+// SetAlgorithmVersion changes the algorithm version used for future calculations
+func (ns *NutritionalScorer) SetAlgorithmVersion(version string) error {
+	if _, err := ns.registry.Get(version); err != nil {
+		// Not an exact version, accept it only if it names a known family
+		if _, familyErr := ns.registry.Resolve(version, models.FoodType); familyErr != nil {
+			return err
+		}
+	}
+	ns.version = version
+	return nil
+}
+
+// GetAlgorithmVersion returns the configured algorithm version or family
+func (ns *NutritionalScorer) GetAlgorithmVersion() string {
+	return ns.version
+}
+
+// GetRulesetChecksum returns the checksum of the ruleset used by the scorer
+func (ns *NutritionalScorer) GetRulesetChecksum() string {
+	return ns.checksum
 }
 
 // CalculateScore computes the nutritional score using the official Nutri-Score algorithm
 // This method implements the complete scoring process including validation and grade assignment
 func (ns *NutritionalScorer) CalculateScore(data models.NutritionalData, foodType models.ScoreType) (models.NutritionalScore, error) {
 	// First validate the input data to ensure it's within acceptable ranges
-	validationErrors := ns.ValidateNutritionalData(data)
-	if len(validationErrors) > 0 {
-		// Return the first validation error for simplicity
-		return models.NutritionalScore{}, validationErrors[0]
+	// Warnings about implausible values do not stop the scoring and are attached to the score
+	validation := ns.validator.CheckNutritionalDataFor(data, foodType)
+	if validation.HasErrors() {
+		return models.NutritionalScore{}, validation
+	}
+
+	// Unknown values are not 0 and must be estimated first
+	if err := requireKnownValues(data); err != nil {
+		return models.NutritionalScore{}, err
+	}
+
+	// Fats and oils are scored on the saturated fat / total fat ratio, which needs total fat
+	if foodType == models.FatsOilsType && data.TotalFat <= 0 && data.SaturatedFattyAcids > 0 {
+		return models.NutritionalScore{}, models.ValidationError{
+			Field:   "total_fat",
+			Value:   float64(data.TotalFat),
+			Message: "Total fat is required to score fats, oils, nuts and seeds",
+		}
+	}
+
+	// Pick the algorithm version that applies to this score type
+	algorithm, err := ns.registry.Resolve(ns.version, foodType)
+	if err != nil {
+		return models.NutritionalScore{}, err
 	}
 
 	// Water has a special case - no nutritional scoring
 	if foodType == models.WaterType {
 		return models.NutritionalScore{
-			Value:     0,
-			Grade:     "A", // Water always gets the best grade
-			Positive:  0,
-			Negative:  0,
-			ScoreType: foodType,
+			Value:            0,
+			Grade:            "A", // Water always gets the best grade
+			Positive:         0,
+			Negative:         0,
+			ScoreType:        foodType,
+			AlgorithmVersion: algorithm.Version,
+			RulesetChecksum:  ns.checksum,
+			Scheme:           models.SchemeNutriScore,
+			Warnings:         validation.GetWarnings(),
 		}, nil
 	}
 
-	// Calculate negative points (nutrients to limit)
-	negativePoints := ns.calculator.CalculateNegativePoints(data)
-	
-	// Calculate positive points (beneficial nutrients)
-	positivePoints := ns.calculator.CalculatePositivePoints(data, foodType)
-	
+	score := ns.scoreWithAlgorithm(algorithm, data, foodType)
+	score.Warnings = validation.GetWarnings()
+	return score, nil
+}
+
+// scoreWithAlgorithm scores data that has already been validated with one algorithm version
+func (ns *NutritionalScorer) scoreWithAlgorithm(algorithm *Algorithm, data models.NutritionalData, foodType models.ScoreType) models.NutritionalScore {
+	calculator := NewScoreCalculatorWithAlgorithm(algorithm)
+
+	// Score every nutrient individually so the final rules can use the real components
+	breakdown := calculator.CalculateBreakdown(data, foodType)
+	negativePoints := breakdown.NegativePoints()
+	positivePoints := breakdown.PositivePoints()
+
 	// Get the final score using official Nutri-Score rules
-	finalScore := ns.calculator.GetFinalScore(negativePoints, positivePoints, foodType)
-	
-	// Convert numerical score to letter grade
-	grade := ns.GetScoreGrade(finalScore)
+	finalScore := calculator.GetFinalScoreFromBreakdown(breakdown, foodType)
+
+	// Convert numerical score to letter grade using the version's boundaries
+	grade := algorithm.Grade(finalScore)
 
 	return models.NutritionalScore{
-		Value:     finalScore,
-		Grade:     grade,
-		Positive:  positivePoints,
-		Negative:  negativePoints,
-		ScoreType: foodType,
-	}, nil
+		Value:            finalScore,
+		Grade:            grade,
+		Positive:         positivePoints,
+		Negative:         negativePoints,
+		ScoreType:        foodType,
+		AlgorithmVersion: algorithm.Version,
+		RulesetChecksum:  ns.checksum,
+		Scheme:           models.SchemeNutriScore,
+		Breakdown:        breakdown,
+	}
 }
 
 // ValidateNutritionalData checks if nutritional data is within acceptable ranges
@@ -71,261 +186,204 @@ func (ns *NutritionalScorer) ValidateNutritionalData(data models.NutritionalData
 }
 
 // GetScoreGrade converts a numerical score to a letter grade (A-E)
-// Uses official Nutri-Score thresholds: A (best) to E (worst)
+// Uses the food grade boundaries of the configured algorithm version: A (best) to E (worst)
 func (ns *NutritionalScorer) GetScoreGrade(score int) string {
-	// Official Nutri-Score grade thresholds
-	// Lower scores are better (healthier foods)
-	switch {
-	case score <= -1:
-		return "A" // Best nutritional quality
-	case score <= 2:
-		return "B" // Good nutritional quality
-	case score <= 10:
-		return "C" // Average nutritional quality
-	case score <= 18:
-		return "D" // Poor nutritional quality
-	default:
-		return "E" // Worst nutritional quality
+	return ns.GetScoreGradeForType(score, models.FoodType)
+}
+
+// GetScoreGradeForType converts a numerical score to a letter grade for a specific score type
+// Beverages use their own boundaries, under which only water can be graded A
+func (ns *NutritionalScorer) GetScoreGradeForType(score int, scoreType models.ScoreType) string {
+	if scoreType == models.WaterType {
+		return "A"
 	}
+	algorithm, err := ns.registry.Resolve(ns.version, scoreType)
+	if err != nil {
+		return "E"
+	}
+	return algorithm.Grade(score)
 }
 
-// GetScoreThresholds returns the score thresholds for each letter grade
+// GetScoreThresholds returns the score thresholds for each letter grade per score type
 // Useful for displaying grade boundaries to users
-func (ns *NutritionalScorer) GetScoreThresholds() map[string]int {
-	return map[string]int{
-		"A": -1,  // Score <= -1
-		"B": 2,   // Score <= 2
-		"C": 10,  // Score <= 10
-		"D": 18,  // Score <= 18
-		"E": 19,  // Score >= 19
+func (ns *NutritionalScorer) GetScoreThresholds() map[models.ScoreType]map[string]int {
+	thresholds := make(map[models.ScoreType]map[string]int)
+	for _, scoreType := range []models.ScoreType{models.FoodType, models.BeverageType, models.CheeseType, models.FatsOilsType} {
+		algorithm, err := ns.registry.Resolve(ns.version, scoreType)
+		if err != nil {
+			continue
+		}
+		thresholds[scoreType] = algorithm.Thresholds()
 	}
+
+	// Water is always graded A with a score of 0
+	thresholds[models.WaterType] = map[string]int{"A": 0}
+
+	return thresholds
 }
 
+// kilojoulesPerGramFat is the energy conversion factor for fat (EU Regulation 1169/2011)
+const kilojoulesPerGramFat = 37
+
 // ScoreCalculator implements the mathematical aspects of the Nutri-Score algorithm
-// This struct contains the official calculation logic for negative and positive points
-type ScoreCalculator struct{}
+// This struct applies the point scales of one algorithm version
+type ScoreCalculator struct {
+	algorithm *Algorithm
+}
 
 // NewScoreCalculator creates a new instance of the score calculator
+// The calculator uses the default (2017) algorithm version
 func NewScoreCalculator() *ScoreCalculator {
-	return &ScoreCalculator{}
+	algorithm, err := GetAlgorithm(DefaultAlgorithmVersion)
+	if err != nil {
+		panic(err)
+	}
+	return NewScoreCalculatorWithAlgorithm(algorithm)
 }
 
-// CalculateNegativePoints computes points from nutrients that should be limited
-// Uses official Nutri-Score thresholds for energy, sugars, saturated fat, and sodium
-func (sc *ScoreCalculator) CalculateNegativePoints(data models.NutritionalData) int {
-	var points int
-
-	// Energy points (per 100g)
-	// Official thresholds in kJ per 100g
-	energy := float64(data.Energy)
-	switch {
-	case energy <= 335:
-		points += 0
-	case energy <= 670:
-		points += 1
-	case energy <= 1005:
-		points += 2
-	case energy <= 1340:
-		points += 3
-	case energy <= 1675:
-		points += 4
-	case energy <= 2010:
-		points += 5
-	case energy <= 2345:
-		points += 6
-	case energy <= 2680:
-		points += 7
-	case energy <= 3015:
-		points += 8
-	case energy <= 3350:
-		points += 9
-	default:
-		points += 10 // Maximum points for very high energy
+// NewScoreCalculatorWithAlgorithm creates a score calculator for a specific algorithm version
+func NewScoreCalculatorWithAlgorithm(algorithm *Algorithm) *ScoreCalculator {
+	return &ScoreCalculator{algorithm: algorithm}
+}
+
+// GetAlgorithm returns the algorithm used by the calculator
+func (sc *ScoreCalculator) GetAlgorithm() *Algorithm {
+	return sc.algorithm
+}
+
+// CalculateBreakdown scores every nutrient individually
+// The result records the points and threshold band of each component
+func (sc *ScoreCalculator) CalculateBreakdown(data models.NutritionalData, foodType models.ScoreType) models.ScoreBreakdown {
+	// Labels may declare salt instead of sodium
+	data = data.ResolveSodium()
+
+	breakdown := models.ScoreBreakdown{
+		// Negative components (per 100g)
+		Energy:       sc.algorithm.Energy.Component(sc.energyValue(data)),
+		Sugars:       sc.algorithm.Sugars.Component(float64(data.Sugars)),
+		SaturatedFat: sc.algorithm.SaturatedFat.Component(sc.saturatedFatValue(data)),
+		Sodium:       sc.algorithm.Sodium.Component(float64(data.Sodium)),
+
+		// Positive components (per 100g, fruits as percentage of total weight)
+		Fruits:  sc.algorithm.Fruits.Component(float64(data.Fruits)),
+		Fibre:   sc.algorithm.Fibre.Component(float64(data.Fibre)),
+		Protein: sc.algorithm.Protein.Component(float64(data.Protein)),
 	}
 
-	// Sugar points (per 100g)
-	// Official thresholds in grams per 100g
-	sugars := float64(data.Sugars)
-	switch {
-	case sugars <= 4.5:
-		points += 0
-	case sugars <= 9:
-		points += 1
-	case sugars <= 13.5:
-		points += 2
-	case sugars <= 18:
-		points += 3
-	case sugars <= 22.5:
-		points += 4
-	case sugars <= 27:
-		points += 5
-	case sugars <= 31:
-		points += 6
-	case sugars <= 36:
-		points += 7
-	case sugars <= 40:
-		points += 8
-	case sugars <= 45:
-		points += 9
-	default:
-		points += 10 // Maximum points for very high sugar
+	// Red meat products have their protein points capped from the 2023 algorithm on
+	if data.RedMeat && sc.algorithm.RedMeatProteinCap > 0 {
+		breakdown.Protein.Points = min(breakdown.Protein.Points, sc.algorithm.RedMeatProteinCap)
+		breakdown.Protein.MaxPoints = min(breakdown.Protein.MaxPoints, sc.algorithm.RedMeatProteinCap)
 	}
 
-	// Saturated fatty acids points (per 100g)
-	// Official thresholds in grams per 100g
-	satFat := float64(data.SaturatedFattyAcids)
-	switch {
-	case satFat <= 1:
-		points += 0
-	case satFat <= 2:
-		points += 1
-	case satFat <= 3:
-		points += 2
-	case satFat <= 4:
-		points += 3
-	case satFat <= 5:
-		points += 4
-	case satFat <= 6:
-		points += 5
-	case satFat <= 7:
-		points += 6
-	case satFat <= 8:
-		points += 7
-	case satFat <= 9:
-		points += 8
-	case satFat <= 10:
-		points += 9
-	default:
-		points += 10 // Maximum points for very high saturated fat
+	// Beverages with non-nutritive sweeteners are penalised from the 2023 algorithm on
+	if foodType == models.BeverageType && data.NonNutritiveSweeteners {
+		breakdown.SweetenerPoints = sc.algorithm.SweetenerPenalty
 	}
 
-	// Sodium points (per 100g)
-	// Official thresholds in milligrams per 100g
-	sodium := float64(data.Sodium)
-	switch {
-	case sodium <= 90:
-		points += 0
-	case sodium <= 180:
-		points += 1
-	case sodium <= 270:
-		points += 2
-	case sodium <= 360:
-		points += 3
-	case sodium <= 450:
-		points += 4
-	case sodium <= 540:
-		points += 5
-	case sodium <= 630:
-		points += 6
-	case sodium <= 720:
-		points += 7
-	case sodium <= 810:
-		points += 8
-	case sodium <= 900:
-		points += 9
-	default:
-		points += 10 // Maximum points for very high sodium
+	breakdown.ProteinExcluded = !sc.IsProteinCounted(breakdown, foodType)
+	breakdown.FruitOnly = foodType == models.BeverageType && !sc.algorithm.BeveragePositives
+
+	return breakdown
+}
+
+// energyValue returns the energy figure scored by the algorithm
+// Fats and oils are scored on the energy from saturated fat (37 kJ per gram) in the 2023 revision
+func (sc *ScoreCalculator) energyValue(data models.NutritionalData) float64 {
+	if sc.algorithm.EnergyFromSaturates {
+		return float64(data.SaturatedFattyAcids) * kilojoulesPerGramFat
 	}
+	return float64(data.Energy)
+}
 
-	return points
+// saturatedFatValue returns the saturated fat figure scored by the algorithm
+// Fats and oils are scored on the saturated fat / total fat ratio in percent
+func (sc *ScoreCalculator) saturatedFatValue(data models.NutritionalData) float64 {
+	if sc.algorithm.SaturatedFatRatio {
+		return SaturatedFatRatio(data)
+	}
+	return float64(data.SaturatedFattyAcids)
 }
 
-// CalculatePositivePoints computes points from beneficial nutrients
-// Uses official Nutri-Score thresholds for fruits/vegetables/nuts, fiber, and protein
-func (sc *ScoreCalculator) CalculatePositivePoints(data models.NutritionalData, foodType models.ScoreType) int {
-	var points int
-
-	// Fruits, vegetables, and nuts points
-	// Official thresholds as percentage of total weight
-	fruits := float64(data.Fruits)
-	switch {
-	case fruits <= 40:
-		points += 0
-	case fruits <= 60:
-		points += 1
-	case fruits <= 80:
-		points += 2
-	default:
-		points += 5 // Maximum points for high fruit/vegetable content
+// SaturatedFatRatio returns saturated fat as a percentage of total fat
+// Returns 0 when no total fat is declared
+func SaturatedFatRatio(data models.NutritionalData) float64 {
+	if data.TotalFat <= 0 {
+		return 0
 	}
+	return float64(data.SaturatedFattyAcids) / float64(data.TotalFat) * 100
+}
 
-	// Fiber points (per 100g)
-	// Official thresholds in grams per 100g
-	fiber := float64(data.Fibre)
-	switch {
-	case fiber <= 0.9:
-		points += 0
-	case fiber <= 1.9:
-		points += 1
-	case fiber <= 2.8:
-		points += 2
-	case fiber <= 3.7:
-		points += 3
-	case fiber <= 4.7:
-		points += 4
-	default:
-		points += 5 // Maximum points for high fiber content
+// IsProteinCounted applies the official protein exclusion rule
+// Protein points are not counted when a food has 11 or more negative points and
+// fewer than 5 fruit points; cheese always counts its protein
+func (sc *ScoreCalculator) IsProteinCounted(breakdown models.ScoreBreakdown, foodType models.ScoreType) bool {
+	if foodType == models.CheeseType || sc.algorithm.ProteinExclusionThreshold == 0 {
+		return true
 	}
 
-	// Protein points (per 100g)
-	// Official thresholds in grams per 100g
-	protein := float64(data.Protein)
-	switch {
-	case protein <= 1.6:
-		points += 0
-	case protein <= 3.2:
-		points += 1
-	case protein <= 4.8:
-		points += 2
-	case protein <= 6.4:
-		points += 3
-	case protein <= 8.0:
-		points += 4
-	default:
-		points += 5 // Maximum points for high protein content
+	if breakdown.NegativePoints() < sc.algorithm.ProteinExclusionThreshold {
+		return true
 	}
 
-	return points
+	exemption := sc.algorithm.ProteinExclusionFruitExemption
+	return exemption > 0 && breakdown.Fruits.Points >= exemption
 }
 
-// GetFinalScore combines negative and positive points according to Nutri-Score rules
-// Different food types may have different calculation rules
-func (sc *ScoreCalculator) GetFinalScore(negative, positive int, foodType models.ScoreType) int {
+// CalculateNegativePoints computes points from nutrients that should be limited
+// Uses the algorithm's thresholds for energy, sugars, saturated fat, and sodium
+func (sc *ScoreCalculator) CalculateNegativePoints(data models.NutritionalData) int {
+	return sc.CalculateBreakdown(data, models.FoodType).NegativePoints()
+}
+
+// CalculatePositivePoints computes points from beneficial nutrients
+// Uses the algorithm's thresholds for fruits/vegetables/nuts, fiber, and protein
+func (sc *ScoreCalculator) CalculatePositivePoints(data models.NutritionalData, foodType models.ScoreType) int {
+	return sc.CalculateBreakdown(data, foodType).PositivePoints()
+}
+
+// GetFinalScoreFromBreakdown combines the individual components according to Nutri-Score rules
+// Unlike GetFinalScore, the rules can see which nutrient each point came from
+func (sc *ScoreCalculator) GetFinalScoreFromBreakdown(breakdown models.ScoreBreakdown, foodType models.ScoreType) int {
+	negative := breakdown.NegativePoints()
+
 	switch foodType {
 	case models.WaterType:
 		// Water always gets a score of 0 (best possible)
 		return 0
-		
-	case models.CheeseType:
-		// Cheese has special rules - protein points are always counted
-		// regardless of negative points
-		return negative - positive
-		
+
 	case models.BeverageType:
-		// Beverages have modified rules - no fiber or protein points
-		// Only fruits/vegetables points are counted
-		// This is a simplified implementation - actual rules are more complex
-		fruitsPoints := 0
-		if data := positive; data > 0 {
-			// Extract only fruits points (first component of positive points)
-			// This is a simplification - in practice we'd need to track components separately
-			fruitsPoints = min(positive, 5) // Max 5 points from fruits
+		// Beverages only count their fruits/vegetables points unless the algorithm
+		// counts every positive component for them
+		if sc.algorithm.BeveragePositives {
+			return negative - (breakdown.Fruits.Points + breakdown.Fibre.Points + breakdown.Protein.Points)
 		}
-		return negative - fruitsPoints
-		
+		return negative - breakdown.Fruits.Points
+
+	case models.CheeseType:
+		// Cheese always counts all of its positive points, including protein
+		return negative - (breakdown.Fruits.Points + breakdown.Fibre.Points + breakdown.Protein.Points)
+
 	default: // Regular food
-		// Standard Nutri-Score calculation
-		// If negative points >= 11, protein points only count if fruits points >= 5
-		if negative >= 11 {
-			// This is a simplified check - in practice we'd need to track
-			// fruits and protein points separately
-			// For now, we'll use the standard calculation
-			return negative - positive
+		positive := breakdown.Fruits.Points + breakdown.Fibre.Points
+		if sc.IsProteinCounted(breakdown, foodType) {
+			positive += breakdown.Protein.Points
 		}
 		return negative - positive
 	}
 }
 
+// GetFinalScore combines negative and positive points according to Nutri-Score rules
+// The positive points must only include the components that count for the food type,
+// as returned by ScoreBreakdown.PositivePoints
+func (sc *ScoreCalculator) GetFinalScore(negative, positive int, foodType models.ScoreType) int {
+	if foodType == models.WaterType {
+		// Water always gets a score of 0 (best possible)
+		return 0
+	}
+	return negative - positive
+}
+
 // min returns the minimum of two integers
 func min(a, b int) int {
 	if a < b {
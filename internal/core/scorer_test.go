@@ -1,7 +1,7 @@
 package core
 
 import (
-	"nutritional-score/pkg/models"
+	"github.com/nutritional-score/pkg/models"
 	"testing"
 )
 
@@ -19,8 +19,8 @@ func TestNutritionalScorer_CalculateScore(t *testing.T) {
 		{
 			name: "Apple - Healthy Food (Grade A)",
 			data: models.NutritionalData{
-				Energy:              models.EnergyKJ(218),    // Low energy
-				Sugars:              models.SugarGram(10.4),  // Natural sugars
+				Energy:              models.EnergyKJ(218),            // Low energy
+				Sugars:              models.SugarGram(10.4),          // Natural sugars
 				SaturatedFattyAcids: models.SaturatedFattyAcids(0.1), // Very low
 				Sodium:              models.SodiumMilligram(1),       // Very low
 				Fruits:              models.FruitsPercent(100),       // 100% fruit
@@ -37,8 +37,8 @@ func TestNutritionalScorer_CalculateScore(t *testing.T) {
 		{
 			name: "Chocolate Bar - Unhealthy Food (Grade E)",
 			data: models.NutritionalData{
-				Energy:              models.EnergyKJ(2200),   // High energy
-				Sugars:              models.SugarGram(47),    // Very high sugar
+				Energy:              models.EnergyKJ(2200),          // High energy
+				Sugars:              models.SugarGram(47),           // Very high sugar
 				SaturatedFattyAcids: models.SaturatedFattyAcids(18), // High saturated fat
 				Sodium:              models.SodiumMilligram(24),     // Low sodium
 				Fruits:              models.FruitsPercent(0),        // No fruits
@@ -76,8 +76,8 @@ func TestNutritionalScorer_CalculateScore(t *testing.T) {
 		{
 			name: "Cheese - Special Scoring Rules",
 			data: models.NutritionalData{
-				Energy:              models.EnergyKJ(1500),   // Moderate energy
-				Sugars:              models.SugarGram(1),     // Low sugar
+				Energy:              models.EnergyKJ(1500),          // Moderate energy
+				Sugars:              models.SugarGram(1),            // Low sugar
 				SaturatedFattyAcids: models.SaturatedFattyAcids(15), // High saturated fat
 				Sodium:              models.SodiumMilligram(600),    // High sodium
 				Fruits:              models.FruitsPercent(0),        // No fruits
@@ -85,8 +85,10 @@ func TestNutritionalScorer_CalculateScore(t *testing.T) {
 				Protein:             models.ProteinGram(25),         // High protein
 			},
 			foodType: models.CheeseType,
+			// 4 energy + 0 sugars + 10 saturated fat + 6 sodium - 5 protein = 15,
+			// which is D under the 2017 boundaries (11 to 18)
 			expected: models.NutritionalScore{
-				Grade:     "E",
+				Grade:     "D",
 				ScoreType: models.CheeseType,
 			},
 			wantErr: false,
@@ -94,8 +96,8 @@ func TestNutritionalScorer_CalculateScore(t *testing.T) {
 		{
 			name: "Beverage - Modified Rules",
 			data: models.NutritionalData{
-				Energy:              models.EnergyKJ(180),    // Low energy
-				Sugars:              models.SugarGram(4),     // Low sugar
+				Energy:              models.EnergyKJ(180),          // Low energy
+				Sugars:              models.SugarGram(4),           // Low sugar
 				SaturatedFattyAcids: models.SaturatedFattyAcids(0), // No fat
 				Sodium:              models.SodiumMilligram(10),    // Low sodium
 				Fruits:              models.FruitsPercent(50),      // Some fruit
@@ -104,16 +106,16 @@ func TestNutritionalScorer_CalculateScore(t *testing.T) {
 			},
 			foodType: models.BeverageType,
 			expected: models.NutritionalScore{
-				Grade:     "A",
+				Grade:     "D", // 6 energy + 3 sugar points - 2 fruit points; only water can be graded A
 				ScoreType: models.BeverageType,
 			},
 			wantErr: false,
 		},
 		{
-			name: "Whole Grain Bread - Grade B Food",
+			name: "Whole Grain Bread - Grade A Food",
 			data: models.NutritionalData{
-				Energy:              models.EnergyKJ(1100),   // Moderate energy
-				Sugars:              models.SugarGram(3.2),   // Low sugar
+				Energy:              models.EnergyKJ(1100),           // Moderate energy
+				Sugars:              models.SugarGram(3.2),           // Low sugar
 				SaturatedFattyAcids: models.SaturatedFattyAcids(1.1), // Low saturated fat
 				Sodium:              models.SodiumMilligram(380),     // Moderate sodium
 				Fruits:              models.FruitsPercent(0),         // No fruits
@@ -121,8 +123,10 @@ func TestNutritionalScorer_CalculateScore(t *testing.T) {
 				Protein:             models.ProteinGram(9.4),         // Good protein
 			},
 			foodType: models.FoodType,
+			// 3 energy + 0 sugars + 1 saturated fat + 4 sodium - 5 fibre - 5 protein = -2;
+			// protein counts as the negative points stay below 11, and -2 is A in 2017
 			expected: models.NutritionalScore{
-				Grade:     "B",
+				Grade:     "A",
 				ScoreType: models.FoodType,
 			},
 			wantErr: false,
@@ -130,8 +134,8 @@ func TestNutritionalScorer_CalculateScore(t *testing.T) {
 		{
 			name: "Orange Juice - Beverage with High Sugar",
 			data: models.NutritionalData{
-				Energy:              models.EnergyKJ(190),    // Low energy
-				Sugars:              models.SugarGram(9.6),   // Natural fruit sugars
+				Energy:              models.EnergyKJ(190),          // Low energy
+				Sugars:              models.SugarGram(9.6),         // Natural fruit sugars
 				SaturatedFattyAcids: models.SaturatedFattyAcids(0), // No fat
 				Sodium:              models.SodiumMilligram(1),     // Very low sodium
 				Fruits:              models.FruitsPercent(100),     // 100% fruit
@@ -140,7 +144,7 @@ func TestNutritionalScorer_CalculateScore(t *testing.T) {
 			},
 			foodType: models.BeverageType,
 			expected: models.NutritionalScore{
-				Grade:     "B",
+				Grade:     "C", // 7 energy + 7 sugar points - 10 fruit points
 				ScoreType: models.BeverageType,
 			},
 			wantErr: false,
@@ -148,8 +152,8 @@ func TestNutritionalScorer_CalculateScore(t *testing.T) {
 		{
 			name: "Cheddar Cheese - High Fat Cheese",
 			data: models.NutritionalData{
-				Energy:              models.EnergyKJ(1700),   // High energy
-				Sugars:              models.SugarGram(0.1),   // Very low sugar
+				Energy:              models.EnergyKJ(1700),          // High energy
+				Sugars:              models.SugarGram(0.1),          // Very low sugar
 				SaturatedFattyAcids: models.SaturatedFattyAcids(21), // Very high saturated fat
 				Sodium:              models.SodiumMilligram(621),    // High sodium
 				Fruits:              models.FruitsPercent(0),        // No fruits
@@ -157,8 +161,10 @@ func TestNutritionalScorer_CalculateScore(t *testing.T) {
 				Protein:             models.ProteinGram(25),         // High protein
 			},
 			foodType: models.CheeseType,
+			// 5 energy + 0 sugars + 10 saturated fat + 6 sodium - 5 protein = 16,
+			// which is D under the 2017 boundaries (11 to 18)
 			expected: models.NutritionalScore{
-				Grade:     "E",
+				Grade:     "D",
 				ScoreType: models.CheeseType,
 			},
 			wantErr: false,
@@ -168,12 +174,12 @@ func TestNutritionalScorer_CalculateScore(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			result, err := scorer.CalculateScore(tt.data, tt.foodType)
-			
+
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CalculateScore() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			
+
 			if !tt.wantErr {
 				if result.Grade != tt.expected.Grade {
 					t.Errorf("CalculateScore() grade = %v, want %v (score: %d)", result.Grade, tt.expected.Grade, result.Value)
@@ -254,8 +260,8 @@ func TestScoreCalculator_CalculateNegativePoints(t *testing.T) {
 		{
 			name: "Low Values - Minimal Points",
 			data: models.NutritionalData{
-				Energy:              models.EnergyKJ(300),  // 0 points
-				Sugars:              models.SugarGram(4),   // 0 points
+				Energy:              models.EnergyKJ(300),            // 0 points
+				Sugars:              models.SugarGram(4),             // 0 points
 				SaturatedFattyAcids: models.SaturatedFattyAcids(0.5), // 0 points
 				Sodium:              models.SodiumMilligram(50),      // 0 points
 			},
@@ -264,18 +270,18 @@ func TestScoreCalculator_CalculateNegativePoints(t *testing.T) {
 		{
 			name: "Moderate Values",
 			data: models.NutritionalData{
-				Energy:              models.EnergyKJ(1000), // 2 points
-				Sugars:              models.SugarGram(15),  // 3 points
-				SaturatedFattyAcids: models.SaturatedFattyAcids(3),  // 2 points
-				Sodium:              models.SodiumMilligram(300),    // 2 points
+				Energy:              models.EnergyKJ(1000),         // 2 points
+				Sugars:              models.SugarGram(15),          // 3 points
+				SaturatedFattyAcids: models.SaturatedFattyAcids(3), // 2 points
+				Sodium:              models.SodiumMilligram(300),   // 3 points (above 270 mg)
 			},
-			expected: 9, // 2+3+2+2
+			expected: 10, // 2+3+2+3
 		},
 		{
 			name: "High Values - Maximum Points",
 			data: models.NutritionalData{
-				Energy:              models.EnergyKJ(4000), // 10 points
-				Sugars:              models.SugarGram(50),  // 10 points
+				Energy:              models.EnergyKJ(4000),          // 10 points
+				Sugars:              models.SugarGram(50),           // 10 points
 				SaturatedFattyAcids: models.SaturatedFattyAcids(15), // 10 points
 				Sodium:              models.SodiumMilligram(1000),   // 10 points
 			},
@@ -347,7 +353,7 @@ func TestScoreCalculator_CalculatePositivePoints(t *testing.T) {
 			name: "High Fiber Content",
 			data: models.NutritionalData{
 				Fruits:  models.FruitsPercent(0),
-				Fibre:   models.FibreGram(6),  // 5 points
+				Fibre:   models.FibreGram(6), // 5 points
 				Protein: models.ProteinGram(0),
 			},
 			foodType: models.FoodType,
@@ -536,7 +542,7 @@ func TestNutritionalScorer_ValidationIntegration(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			_, err := scorer.CalculateScore(tt.data, models.FoodType)
-			
+
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CalculateScore() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -587,11 +593,11 @@ func TestEdgeCases(t *testing.T) {
 			sugar    models.SugarGram
 			expected int // Expected sugar points
 		}{
-			{4.5, 0},  // Boundary for 0 points
-			{4.6, 1},  // Just over boundary
-			{9.0, 1},  // Boundary for 1 point
-			{9.1, 2},  // Just over boundary
-			{45.0, 9}, // Boundary for 9 points
+			{4.5, 0},   // Boundary for 0 points
+			{4.6, 1},   // Just over boundary
+			{9.0, 1},   // Boundary for 1 point
+			{9.1, 2},   // Just over boundary
+			{45.0, 9},  // Boundary for 9 points
 			{45.1, 10}, // Maximum points
 		}
 
@@ -616,11 +622,11 @@ func TestEdgeCases(t *testing.T) {
 			satFat   models.SaturatedFattyAcids
 			expected int // Expected saturated fat points
 		}{
-			{1.0, 0},  // Boundary for 0 points
-			{1.1, 1},  // Just over boundary
-			{2.0, 1},  // Boundary for 1 point
-			{2.1, 2},  // Just over boundary
-			{10.0, 9}, // Boundary for 9 points
+			{1.0, 0},   // Boundary for 0 points
+			{1.1, 1},   // Just over boundary
+			{2.0, 1},   // Boundary for 1 point
+			{2.1, 2},   // Just over boundary
+			{10.0, 9},  // Boundary for 9 points
 			{10.1, 10}, // Maximum points
 		}
 
@@ -755,8 +761,8 @@ func TestEdgeCases(t *testing.T) {
 	t.Run("Extreme Values", func(t *testing.T) {
 		// Test with maximum allowed values
 		extremeData := models.NutritionalData{
-			Energy:              models.EnergyKJ(4000),  // Maximum
-			Sugars:              models.SugarGram(100),  // Maximum
+			Energy:              models.EnergyKJ(4000),           // Maximum
+			Sugars:              models.SugarGram(100),           // Maximum
 			SaturatedFattyAcids: models.SaturatedFattyAcids(100), // Maximum
 			Sodium:              models.SodiumMilligram(10000),   // Maximum
 			Fruits:              models.FruitsPercent(100),       // Maximum
@@ -802,9 +808,9 @@ func TestEdgeCases(t *testing.T) {
 			t.Errorf("CalculateScore() with minimum values failed: %v", err)
 		}
 
-		// Should get Grade A due to no negative points
-		if result.Grade != "A" {
-			t.Errorf("Expected Grade A for minimum values, got %s (score: %d)", result.Grade, result.Value)
+		// A score of 0 is B: the 2017 food boundaries only grade -1 and below as A
+		if result.Grade != "B" {
+			t.Errorf("Expected Grade B for minimum values, got %s (score: %d)", result.Grade, result.Value)
 		}
 
 		// Verify zero points
@@ -852,27 +858,372 @@ func TestGetScoreThresholds(t *testing.T) {
 	scorer := NewNutritionalScorer()
 	thresholds := scorer.GetScoreThresholds()
 
-	expectedThresholds := map[string]int{
-		"A": -1,
-		"B": 2,
-		"C": 10,
-		"D": 18,
-		"E": 19,
+	expectedThresholds := map[models.ScoreType]map[string]int{
+		models.FoodType: {
+			"A": -1,
+			"B": 2,
+			"C": 10,
+			"D": 18,
+			"E": 19,
+		},
+		models.CheeseType: {
+			"A": -1,
+			"B": 2,
+			"C": 10,
+			"D": 18,
+			"E": 19,
+		},
+		models.BeverageType: {
+			"B": 1,
+			"C": 5,
+			"D": 9,
+			"E": 10,
+		},
+		models.WaterType: {
+			"A": 0,
+		},
 	}
 
-	for grade, expectedThreshold := range expectedThresholds {
-		if threshold, exists := thresholds[grade]; !exists {
-			t.Errorf("Missing threshold for grade %s", grade)
-		} else if threshold != expectedThreshold {
-			t.Errorf("Threshold for grade %s = %d, want %d", grade, threshold, expectedThreshold)
+	for scoreType, expected := range expectedThresholds {
+		actual, exists := thresholds[scoreType]
+		if !exists {
+			t.Errorf("Missing thresholds for score type %s", scoreType)
+			continue
+		}
+		if len(actual) != len(expected) {
+			t.Errorf("%s has %d thresholds, want %d", scoreType, len(actual), len(expected))
 		}
+		for grade, expectedThreshold := range expected {
+			if threshold, exists := actual[grade]; !exists {
+				t.Errorf("Missing %s threshold for grade %s", scoreType, grade)
+			} else if threshold != expectedThreshold {
+				t.Errorf("%s threshold for grade %s = %d, want %d", scoreType, grade, threshold, expectedThreshold)
+			}
+		}
+	}
+}
+
+// TestNutritionalScorer_GetScoreGradeForType tests the beverage grade mapping
+func TestNutritionalScorer_GetScoreGradeForType(t *testing.T) {
+	scorer := NewNutritionalScorer()
+
+	tests := []struct {
+		name      string
+		score     int
+		scoreType models.ScoreType
+		expected  string
+	}{
+		{"Water - Always A", 10, models.WaterType, "A"},
+		{"Beverage - Best Score Is B", -10, models.BeverageType, "B"},
+		{"Beverage - B Boundary", 1, models.BeverageType, "B"},
+		{"Beverage - C Start", 2, models.BeverageType, "C"},
+		{"Beverage - C Boundary", 5, models.BeverageType, "C"},
+		{"Beverage - D Start", 6, models.BeverageType, "D"},
+		{"Beverage - D Boundary", 9, models.BeverageType, "D"},
+		{"Beverage - E Start", 10, models.BeverageType, "E"},
+		{"Food - Uses Food Boundaries", 1, models.FoodType, "B"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if grade := scorer.GetScoreGradeForType(tt.score, tt.scoreType); grade != tt.expected {
+				t.Errorf("GetScoreGradeForType(%d, %s) = %s, want %s", tt.score, tt.scoreType, grade, tt.expected)
+			}
+		})
+	}
+}
+
+// TestScoreCalculator_BeverageTables tests the dedicated 2017 beverage point scales
+func TestScoreCalculator_BeverageTables(t *testing.T) {
+	algorithm, err := GetAlgorithm(AlgorithmVersion2017Beverage)
+	if err != nil {
+		t.Fatalf("GetAlgorithm() unexpected error: %v", err)
+	}
+	calculator := NewScoreCalculatorWithAlgorithm(algorithm)
+
+	tests := []struct {
+		name     string
+		data     models.NutritionalData
+		expected models.ScoreBreakdown
+	}{
+		{
+			name: "Energy Steps Of 30 kJ",
+			data: models.NutritionalData{Energy: models.EnergyKJ(31)},
+			expected: models.ScoreBreakdown{
+				Energy: models.ComponentScore{Points: 2},
+			},
+		},
+		{
+			name: "Any Energy Earns A Point",
+			data: models.NutritionalData{Energy: models.EnergyKJ(1)},
+			expected: models.ScoreBreakdown{
+				Energy: models.ComponentScore{Points: 1},
+			},
+		},
+		{
+			name: "Sugar Steps Of 1.5 g",
+			data: models.NutritionalData{Sugars: models.SugarGram(4.6)},
+			expected: models.ScoreBreakdown{
+				Sugars: models.ComponentScore{Points: 4},
+			},
+		},
+		{
+			name: "Fruit Scale Up To 10 Points",
+			data: models.NutritionalData{Fruits: models.FruitsPercent(85)},
+			expected: models.ScoreBreakdown{
+				Fruits: models.ComponentScore{Points: 10},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breakdown := calculator.CalculateBreakdown(tt.data, models.BeverageType)
+			if breakdown.Energy.Points != tt.expected.Energy.Points {
+				t.Errorf("Energy points = %d, want %d", breakdown.Energy.Points, tt.expected.Energy.Points)
+			}
+			if breakdown.Sugars.Points != tt.expected.Sugars.Points {
+				t.Errorf("Sugar points = %d, want %d", breakdown.Sugars.Points, tt.expected.Sugars.Points)
+			}
+			if breakdown.Fruits.Points != tt.expected.Fruits.Points {
+				t.Errorf("Fruit points = %d, want %d", breakdown.Fruits.Points, tt.expected.Fruits.Points)
+			}
+		})
+	}
+}
+
+// TestNutritionalScorer_CalculateScore_Breakdown tests the per-component point breakdown
+func TestNutritionalScorer_CalculateScore_Breakdown(t *testing.T) {
+	scorer := NewNutritionalScorer()
+
+	data := models.NutritionalData{
+		Energy:              models.EnergyKJ(1000),         // 2 points (band 670-1005)
+		Sugars:              models.SugarGram(22),          // 4 points (band 18-22.5)
+		SaturatedFattyAcids: models.SaturatedFattyAcids(3), // 2 points (band 2-3)
+		Sodium:              models.SodiumMilligram(50),    // 0 points (band 0-90)
+		Fruits:              models.FruitsPercent(70),      // 2 points (band 60-80)
+		Fibre:               models.FibreGram(3),           // 3 points (band 2.8-3.7)
+		Protein:             models.ProteinGram(20),        // 5 points (top band)
+	}
+
+	result, err := scorer.CalculateScore(data, models.FoodType)
+	if err != nil {
+		t.Fatalf("CalculateScore() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		component models.ComponentScore
+		points    int
+		lower     float64
+		upper     *float64
+	}{
+		{"Energy", result.Breakdown.Energy, 2, 670, floatPtr(1005)},
+		{"Sugars", result.Breakdown.Sugars, 4, 18, floatPtr(22.5)},
+		{"Saturated Fat", result.Breakdown.SaturatedFat, 2, 2, floatPtr(3)},
+		{"Sodium", result.Breakdown.Sodium, 0, 0, floatPtr(90)},
+		{"Fruits", result.Breakdown.Fruits, 2, 60, floatPtr(80)},
+		{"Fibre", result.Breakdown.Fibre, 3, 2.8, floatPtr(3.7)},
+		{"Protein", result.Breakdown.Protein, 5, 8.0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.component.Points != tt.points {
+				t.Errorf("%s points = %d, want %d", tt.name, tt.component.Points, tt.points)
+			}
+			if tt.component.BandLower != tt.lower {
+				t.Errorf("%s band lower = %v, want %v", tt.name, tt.component.BandLower, tt.lower)
+			}
+			if (tt.component.BandUpper == nil) != (tt.upper == nil) ||
+				(tt.upper != nil && *tt.component.BandUpper != *tt.upper) {
+				t.Errorf("%s band upper = %v, want %v", tt.name, tt.component.BandUpper, tt.upper)
+			}
+		})
+	}
+
+	// The aggregates must match the sum of the components
+	if result.Negative != result.Breakdown.NegativePoints() || result.Negative != 8 {
+		t.Errorf("Negative = %d, breakdown sum = %d, want 8", result.Negative, result.Breakdown.NegativePoints())
+	}
+	if result.Positive != result.Breakdown.PositivePoints() || result.Positive != 10 {
+		t.Errorf("Positive = %d, breakdown sum = %d, want 10", result.Positive, result.Breakdown.PositivePoints())
 	}
 }
 
+// TestScoreCalculator_GetFinalScoreFromBreakdown tests that beverages use their real fruit points
+func TestScoreCalculator_GetFinalScoreFromBreakdown(t *testing.T) {
+	calculator := NewScoreCalculator()
+
+	// A beverage with high fibre and protein but no fruit must not benefit from them
+	data := models.NutritionalData{
+		Energy:  models.EnergyKJ(700), // 2 points
+		Sugars:  models.SugarGram(10), // 2 points
+		Fruits:  models.FruitsPercent(0),
+		Fibre:   models.FibreGram(5),
+		Protein: models.ProteinGram(9),
+	}
+
+	breakdown := calculator.CalculateBreakdown(data, models.BeverageType)
+	if result := calculator.GetFinalScoreFromBreakdown(breakdown, models.BeverageType); result != 4 {
+		t.Errorf("GetFinalScoreFromBreakdown() = %d, want 4", result)
+	}
+}
+
+// TestNutritionalScorer_CalculateScore_Aggregates tests that the value is always negative minus positive
+func TestNutritionalScorer_CalculateScore_Aggregates(t *testing.T) {
+	scorer2023, _ := NewNutritionalScorerWithVersion(AlgorithmFamily2023)
+	juice := models.NutritionalData{
+		Energy:  models.EnergyKJ(190),
+		Sugars:  models.SugarGram(9.6),
+		Sodium:  models.SodiumMilligram(1),
+		Fruits:  models.FruitsPercent(100),
+		Fibre:   models.FibreGram(4),
+		Protein: models.ProteinGram(9),
+	}
+
+	tests := []struct {
+		name     string
+		scorer   *NutritionalScorer
+		foodType models.ScoreType
+		positive int
+	}{
+		{"2017 Beverage Counts Fruit Only", NewNutritionalScorer(), models.BeverageType, 10},
+		{"2023 Beverage Counts All Positives", scorer2023, models.BeverageType, 6 + 1 + 7},
+		{"2017 Food", NewNutritionalScorer(), models.FoodType, 5 + 4 + 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.scorer.CalculateScore(juice, tt.foodType)
+			if err != nil {
+				t.Fatalf("CalculateScore() unexpected error: %v", err)
+			}
+			if result.Positive != tt.positive {
+				t.Errorf("Positive = %d, want %d", result.Positive, tt.positive)
+			}
+			if result.Value != result.Negative-result.Positive {
+				t.Errorf("Value = %d, want Negative - Positive = %d - %d", result.Value, result.Negative, result.Positive)
+			}
+		})
+	}
+}
+
+// TestScoreCalculator_ProteinExclusion tests the "negative >= 11" protein exclusion rule
+func TestScoreCalculator_ProteinExclusion(t *testing.T) {
+	calculator2017 := NewScoreCalculator()
+	algorithm2023, _ := GetAlgorithm(AlgorithmVersion2023Food)
+	calculator2023 := NewScoreCalculatorWithAlgorithm(algorithm2023)
+
+	// Salami-like product: 11+ negative points, no fruit, high protein
+	salami := models.NutritionalData{
+		Energy:              models.EnergyKJ(1600),          // 4 points
+		SaturatedFattyAcids: models.SaturatedFattyAcids(11), // 10 points
+		Sodium:              models.SodiumMilligram(1500),   // 10 points
+		Protein:             models.ProteinGram(25),         // 5 points
+	}
+	// Same product with enough vegetables to earn 5 fruit points
+	vegetableBased := salami
+	vegetableBased.Fruits = models.FruitsPercent(85)
+
+	tests := []struct {
+		name       string
+		calculator *ScoreCalculator
+		data       models.NutritionalData
+		foodType   models.ScoreType
+		excluded   bool
+		expected   int
+	}{
+		{"Food - Protein Excluded", calculator2017, salami, models.FoodType, true, 24},
+		{"Food - Fruit Exemption", calculator2017, vegetableBased, models.FoodType, false, 14},
+		{"Cheese - Always Counts Protein", calculator2017, salami, models.CheeseType, false, 19},
+		{"Food - Below Threshold", calculator2017, models.NutritionalData{Energy: 1600, Protein: 25}, models.FoodType, false, -1},
+		{"2023 Food - No Fruit Exemption", calculator2023, vegetableBased, models.FoodType, true, 27},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breakdown := tt.calculator.CalculateBreakdown(tt.data, tt.foodType)
+			if breakdown.ProteinExcluded != tt.excluded {
+				t.Errorf("ProteinExcluded = %v, want %v", breakdown.ProteinExcluded, tt.excluded)
+			}
+			if result := tt.calculator.GetFinalScoreFromBreakdown(breakdown, tt.foodType); result != tt.expected {
+				t.Errorf("GetFinalScoreFromBreakdown() = %d, want %d", result, tt.expected)
+			}
+		})
+	}
+}
+
+// floatPtr returns a pointer to the given value
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
 // Helper function to calculate absolute difference
 func abs(x int) int {
 	if x < 0 {
 		return -x
 	}
 	return x
-}
\ No newline at end of file
+}
+
+// TestNutritionalScorer_SaltInput tests that declared salt is scored as sodium
+func TestNutritionalScorer_SaltInput(t *testing.T) {
+	scorer := NewNutritionalScorer()
+
+	fromSodium, err := scorer.CalculateScore(models.NutritionalData{Energy: 1000, Sodium: 500}, models.FoodType)
+	if err != nil {
+		t.Fatalf("CalculateScore() unexpected error: %v", err)
+	}
+	fromSalt, err := scorer.CalculateScore(models.NutritionalData{Energy: 1000, Salt: 1.25}, models.FoodType)
+	if err != nil {
+		t.Fatalf("CalculateScore() unexpected error: %v", err)
+	}
+
+	if fromSalt.Breakdown.Sodium.Value != 500 {
+		t.Errorf("Sodium value from 1.25g salt = %v, want 500", fromSalt.Breakdown.Sodium.Value)
+	}
+	if fromSalt.Value != fromSodium.Value {
+		t.Errorf("Score from salt = %d, want %d (same as sodium)", fromSalt.Value, fromSodium.Value)
+	}
+
+	if _, err := scorer.CalculateScore(models.NutritionalData{Salt: 1.25, Sodium: 100}, models.FoodType); err == nil {
+		t.Error("CalculateScore() should reject salt and sodium that disagree")
+	}
+
+	// Provenance records the declared value
+	resolved := models.NutritionalData{Salt: 0.8}.ResolveSodium()
+	if resolved.SodiumDeclaredAs != models.DeclaredAsSalt || resolved.Sodium != 320 {
+		t.Errorf("ResolveSodium() = %v (%s), want 320 mg declared as salt", resolved.Sodium, resolved.SodiumDeclaredAs)
+	}
+	if value, unit := resolved.DeclaredSodiumValue(); value != 0.8 || unit != "g salt" {
+		t.Errorf("DeclaredSodiumValue() = %v %s, want 0.8 g salt", value, unit)
+	}
+}
+
+// TestNutritionalScorer_Warnings tests that warnings are attached to the score and errors stop it
+func TestNutritionalScorer_Warnings(t *testing.T) {
+	scorer := NewNutritionalScorer()
+
+	// Stock cubes are very salty but real
+	score, err := scorer.CalculateScore(models.NutritionalData{Energy: 1000, Sodium: 6000}, models.FoodType)
+	if err != nil {
+		t.Fatalf("CalculateScore() unexpected error: %v", err)
+	}
+	if len(score.Warnings) != 1 || score.Warnings[0].Field != "sodium" || score.Warnings[0].Severity != models.SeverityWarning {
+		t.Errorf("CalculateScore() warnings = %v, want one sodium warning", score.Warnings)
+	}
+
+	if score, err = scorer.CalculateScore(models.NutritionalData{Energy: 1000, Sodium: 400}, models.FoodType); err != nil || score.Warnings != nil {
+		t.Errorf("CalculateScore() = %v, %v, want no warnings", score.Warnings, err)
+	}
+
+	_, err = scorer.CalculateScore(models.NutritionalData{Energy: 5000, Sodium: 6000}, models.FoodType)
+	collection, ok := err.(models.ErrorCollection)
+	if !ok {
+		t.Fatalf("CalculateScore() error type = %T, want models.ErrorCollection", err)
+	}
+	if collection.ErrorCount != 1 || collection.WarningCount != 1 {
+		t.Errorf("CalculateScore() error = %d errors, %d warnings, want 1 and 1", collection.ErrorCount, collection.WarningCount)
+	}
+}
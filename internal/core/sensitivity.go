@@ -0,0 +1,242 @@
+package core
+
+import (
+	"math"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// LabelTolerance returns the labelling tolerance for a declared nutrient value per 100g
+// Follows the European Commission guidance on tolerances for nutrition labelling (2012).
+// Energy and fruit content have no tolerance of their own and return 0
+func LabelTolerance(nutrient string, value float64) float64 {
+	switch nutrient {
+	case "sugars", "fibre", "protein":
+		// ±2g below 10g, ±20% from 10 to 40g, ±8g above 40g
+		return bandedTolerance(value, 2, 8)
+	case "total_fat":
+		// ±1.5g below 10g, ±20% from 10 to 40g, ±8g above 40g
+		return bandedTolerance(value, 1.5, 8)
+	case "saturated_fat":
+		// ±0.8g below 4g, ±20% from 4g
+		if value < 4 {
+			return 0.8
+		}
+		return value * 0.2
+	case "sodium":
+		// ±150mg below 500mg, ±20% from 500mg
+		if value < 500 {
+			return 150
+		}
+		return value * 0.2
+	case "salt":
+		// ±0.375g below 1.25g, ±20% from 1.25g
+		if value < 1.25 {
+			return 0.375
+		}
+		return value * 0.2
+	}
+	return 0
+}
+
+// bandedTolerance applies the common tolerance pattern for macronutrients
+// An absolute tolerance below 10g, 20% from 10 to 40g and an absolute tolerance above 40g
+func bandedTolerance(value, below10, above40 float64) float64 {
+	switch {
+	case value < 10:
+		return below10
+	case value <= 40:
+		return value * 0.2
+	default:
+		return above40
+	}
+}
+
+// Sensitivity reports how far every nutrient is from the edges of its point band and
+// whether a change within labelling tolerance could change the grade
+func (ns *NutritionalScorer) Sensitivity(data models.NutritionalData, foodType models.ScoreType) (models.SensitivityReport, error) {
+	result, err := ns.CalculateScore(data, foodType)
+	if err != nil {
+		return models.SensitivityReport{}, err
+	}
+
+	report := models.SensitivityReport{
+		Score:          result,
+		PossibleGrades: []string{result.Grade},
+	}
+
+	// Water always gets grade A, whatever its composition
+	if foodType == models.WaterType {
+		return report, nil
+	}
+
+	algorithm, err := ns.registry.Get(result.AlgorithmVersion)
+	if err != nil {
+		return models.SensitivityReport{}, err
+	}
+
+	calculator := NewScoreCalculatorWithAlgorithm(algorithm)
+	data = data.ResolveSodium()
+
+	for _, nutrient := range reformulableNutrients {
+		component := nutrient.component(result.Breakdown)
+		sensitivity := models.NutrientSensitivity{
+			Nutrient:  nutrient.key,
+			Unit:      scoredUnit(algorithm, nutrient.key, nutrient.unit),
+			Value:     component.Value,
+			Points:    component.Points,
+			MinPoints: component.Points,
+			MaxPoints: component.Points,
+		}
+		if component.Points > 0 {
+			distance := roundDistance(component.Value - component.BandLower)
+			sensitivity.DistanceToLowerBand = &distance
+		}
+		if component.BandUpper != nil {
+			distance := roundDistance(*component.BandUpper - component.Value)
+			sensitivity.DistanceToHigherBand = &distance
+		}
+
+		declared := nutrient.get(data)
+		sensitivity.Tolerance = LabelTolerance(nutrient.key, declared)
+		if sensitivity.Tolerance > 0 {
+			low, high := data, data
+			nutrient.set(&low, math.Max(declared-sensitivity.Tolerance, 0))
+			nutrient.set(&high, declared+sensitivity.Tolerance)
+
+			grades := make(map[string]bool)
+			for _, variant := range []models.NutritionalData{low, data, high} {
+				breakdown := calculator.CalculateBreakdown(variant, foodType)
+				points := nutrient.component(breakdown).Points
+				sensitivity.MinPoints = min(sensitivity.MinPoints, points)
+				if points > sensitivity.MaxPoints {
+					sensitivity.MaxPoints = points
+				}
+				grades[algorithm.Grade(calculator.GetFinalScoreFromBreakdown(breakdown, foodType))] = true
+			}
+			if len(grades) > 1 {
+				sensitivity.GradeFlip = true
+				sensitivity.FlipGrades = orderedGrades(algorithm, grades)
+				report.GradeCanFlip = true
+			}
+		}
+
+		report.Nutrients = append(report.Nutrients, sensitivity)
+	}
+
+	// Every grade between the best and the worst case is possible
+	best, worst := toleranceExtremes(data)
+	report.PossibleGrades = gradesBetween(algorithm,
+		algorithm.Grade(calculator.GetFinalScoreFromBreakdown(calculator.CalculateBreakdown(best, foodType), foodType)),
+		algorithm.Grade(calculator.GetFinalScoreFromBreakdown(calculator.CalculateBreakdown(worst, foodType), foodType)))
+	if len(report.PossibleGrades) > 1 {
+		report.GradeCanFlip = true
+	}
+
+	return report, nil
+}
+
+// CalculateScoreWithTolerance scores the declared values and the best and worst case within
+// the EU labelling tolerances. Nutrients without a tolerance, such as energy, keep their value
+func (ns *NutritionalScorer) CalculateScoreWithTolerance(data models.NutritionalData, foodType models.ScoreType) (models.ToleranceScore, error) {
+	score, err := ns.CalculateScore(data, foodType)
+	if err != nil {
+		return models.ToleranceScore{}, err
+	}
+
+	result := models.ToleranceScore{
+		Score:         score,
+		BestCase:      score,
+		WorstCase:     score,
+		Grades:        []string{score.Grade},
+		GradeInterval: score.Grade,
+		Robust:        true,
+	}
+
+	// Water always gets grade A, whatever its composition
+	if foodType == models.WaterType {
+		return result, nil
+	}
+
+	algorithm, err := ns.registry.Get(score.AlgorithmVersion)
+	if err != nil {
+		return models.ToleranceScore{}, err
+	}
+
+	// The extremes may break consistency checks such as saturated fat within total fat,
+	// so they are scored without validation
+	best, worst := toleranceExtremes(data.ResolveSodium())
+	result.BestCase = ns.scoreWithAlgorithm(algorithm, best, foodType)
+	result.WorstCase = ns.scoreWithAlgorithm(algorithm, worst, foodType)
+	result.Grades = gradesBetween(algorithm, result.BestCase.Grade, result.WorstCase.Grade)
+	if len(result.Grades) > 1 {
+		result.Robust = false
+		result.GradeInterval = result.Grades[0] + "–" + result.Grades[len(result.Grades)-1]
+	}
+
+	return result, nil
+}
+
+// toleranceExtremes moves every nutrient to its most and least favourable value within tolerance
+func toleranceExtremes(data models.NutritionalData) (best, worst models.NutritionalData) {
+	best, worst = data, data
+	for _, nutrient := range reformulableNutrients {
+		declared := nutrient.get(data)
+		tolerance := LabelTolerance(nutrient.key, declared)
+		if tolerance == 0 {
+			continue
+		}
+
+		low, high := math.Max(declared-tolerance, 0), declared+tolerance
+		if nutrient.negative {
+			nutrient.set(&best, low)
+			nutrient.set(&worst, high)
+		} else {
+			nutrient.set(&best, high)
+			nutrient.set(&worst, low)
+		}
+	}
+
+	// Total fat only counts through the saturated fat ratio of fats and oils: more fat lowers
+	// the ratio. The worst case keeps total fat at least at the worst case saturated fat
+	if declared := float64(data.TotalFat); declared > 0 {
+		tolerance := LabelTolerance("total_fat", declared)
+		best.TotalFat = models.TotalFatGram(declared + tolerance)
+		worst.TotalFat = models.TotalFatGram(math.Max(declared-tolerance, float64(worst.SaturatedFattyAcids)))
+	}
+	return best, worst
+}
+
+// gradesBetween lists the grades from the best to the worst grade
+func gradesBetween(algorithm *Algorithm, best, worst string) []string {
+	var grades []string
+	for rank := gradeRank(algorithm, best); rank <= gradeRank(algorithm, worst); rank++ {
+		grades = append(grades, algorithm.Grades[rank].Grade)
+	}
+	return grades
+}
+
+// scoredUnit returns the unit a nutrient is scored in by the algorithm
+// Fats and oils score energy from saturated fat and saturated fat as a share of total fat
+func scoredUnit(algorithm *Algorithm, nutrient, unit string) string {
+	if nutrient == "saturated_fat" && algorithm.SaturatedFatRatio {
+		return "% of total fat"
+	}
+	return unit
+}
+
+// orderedGrades lists the grades in the set from best to worst
+func orderedGrades(algorithm *Algorithm, grades map[string]bool) []string {
+	var ordered []string
+	for _, boundary := range algorithm.Grades {
+		if grades[boundary.Grade] {
+			ordered = append(ordered, boundary.Grade)
+		}
+	}
+	return ordered
+}
+
+// roundDistance removes floating point noise from band distances
+func roundDistance(distance float64) float64 {
+	return math.Round(distance*10000) / 10000
+}
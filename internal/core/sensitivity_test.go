@@ -0,0 +1,191 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// TestLabelTolerance tests the labelling tolerance bands
+func TestLabelTolerance(t *testing.T) {
+	tests := []struct {
+		nutrient string
+		value    float64
+		expected float64
+	}{
+		{"sugars", 4.5, 2},
+		{"sugars", 20, 4},
+		{"sugars", 50, 8},
+		{"saturated_fat", 3, 0.8},
+		{"saturated_fat", 10, 2},
+		{"sodium", 90, 150},
+		{"sodium", 1000, 200},
+		{"salt", 1, 0.375},
+		{"energy", 1000, 0},
+		{"fruits", 50, 0},
+	}
+
+	for _, tt := range tests {
+		if result := LabelTolerance(tt.nutrient, tt.value); result != tt.expected {
+			t.Errorf("LabelTolerance(%s, %v) = %v, want %v", tt.nutrient, tt.value, result, tt.expected)
+		}
+	}
+}
+
+// TestNutritionalScorer_Sensitivity tests band distances and grade flips within tolerance
+func TestNutritionalScorer_Sensitivity(t *testing.T) {
+	scorer := NewNutritionalScorer()
+
+	t.Run("Band Distances", func(t *testing.T) {
+		data := models.NutritionalData{Energy: 500, Sugars: 4.6, Sodium: 90}
+		report, err := scorer.Sensitivity(data, models.FoodType)
+		if err != nil {
+			t.Fatalf("Sensitivity() unexpected error: %v", err)
+		}
+		if len(report.Nutrients) != 7 {
+			t.Fatalf("Sensitivity() nutrients = %d, want 7", len(report.Nutrients))
+		}
+
+		sugars := report.Nutrients[1]
+		if sugars.DistanceToLowerBand == nil || *sugars.DistanceToLowerBand != 0.1 {
+			t.Errorf("Sugars distance to lower band = %v, want 0.1", sugars.DistanceToLowerBand)
+		}
+		if sugars.DistanceToHigherBand == nil || *sugars.DistanceToHigherBand != 4.4 {
+			t.Errorf("Sugars distance to higher band = %v, want 4.4", sugars.DistanceToHigherBand)
+		}
+		if sugars.MinPoints != 0 || sugars.MaxPoints != 1 {
+			t.Errorf("Sugars points within tolerance = %d-%d, want 0-1", sugars.MinPoints, sugars.MaxPoints)
+		}
+
+		// Sodium sits exactly on the 90mg edge: no lower band, the next band starts above 90mg
+		sodium := report.Nutrients[3]
+		if sodium.DistanceToLowerBand != nil {
+			t.Errorf("Sodium in the first band should have no lower band, got %v", *sodium.DistanceToLowerBand)
+		}
+		if sodium.DistanceToHigherBand == nil || *sodium.DistanceToHigherBand != 0 {
+			t.Errorf("Sodium distance to higher band = %v, want 0", sodium.DistanceToHigherBand)
+		}
+	})
+
+	t.Run("Grade Flip Within Tolerance", func(t *testing.T) {
+		// Energy 700kJ (2 points) + sugars 4.6g (1 point) = 3 (grade C); 2g less sugars gives 2 (grade B)
+		data := models.NutritionalData{Energy: 700, Sugars: 4.6}
+		report, err := scorer.Sensitivity(data, models.FoodType)
+		if err != nil {
+			t.Fatalf("Sensitivity() unexpected error: %v", err)
+		}
+		if report.Score.Grade != "C" {
+			t.Fatalf("Test product grade = %s (score %d), want C", report.Score.Grade, report.Score.Value)
+		}
+		sugars := report.Nutrients[1]
+		if !sugars.GradeFlip || !reflect.DeepEqual(sugars.FlipGrades, []string{"B", "C"}) {
+			t.Errorf("Sugars flip grades = %v (flip %v), want [B C]", sugars.FlipGrades, sugars.GradeFlip)
+		}
+		if !report.GradeCanFlip {
+			t.Error("Report should flag that the grade can flip")
+		}
+		if report.Nutrients[0].GradeFlip {
+			t.Error("Energy has no labelling tolerance and cannot flip the grade")
+		}
+	})
+
+	t.Run("Robust Grade", func(t *testing.T) {
+		data := models.NutritionalData{Energy: 200, Fruits: 100, Fibre: 10, Protein: 10}
+		report, err := scorer.Sensitivity(data, models.FoodType)
+		if err != nil {
+			t.Fatalf("Sensitivity() unexpected error: %v", err)
+		}
+		if report.GradeCanFlip || !reflect.DeepEqual(report.PossibleGrades, []string{"A"}) {
+			t.Errorf("Possible grades = %v (flip %v), want [A]", report.PossibleGrades, report.GradeCanFlip)
+		}
+	})
+
+	t.Run("Water", func(t *testing.T) {
+		report, err := scorer.Sensitivity(models.NutritionalData{}, models.WaterType)
+		if err != nil {
+			t.Fatalf("Sensitivity() unexpected error: %v", err)
+		}
+		if report.GradeCanFlip || len(report.Nutrients) != 0 {
+			t.Errorf("Water report = %+v, want no nutrients and no flip", report)
+		}
+	})
+}
+
+// TestNutritionalScorer_CalculateScoreWithTolerance tests grade intervals within labelling tolerance
+func TestNutritionalScorer_CalculateScoreWithTolerance(t *testing.T) {
+	scorer := NewNutritionalScorer()
+
+	tests := []struct {
+		name         string
+		data         models.NutritionalData
+		foodType     models.ScoreType
+		wantInterval string
+		wantGrades   []string
+		wantRobust   bool
+	}{
+		{
+			// Energy 700kJ (2 points) + sugars 4.6g (1 point) = 3 (grade C); in the best case
+			// sugars drop a band and 2g of fibre and protein earn points as well
+			name:         "grade not robust",
+			data:         models.NutritionalData{Energy: 700, Sugars: 4.6},
+			foodType:     models.FoodType,
+			wantInterval: "A–C",
+			wantGrades:   []string{"A", "B", "C"},
+		},
+		{
+			name:         "robust grade",
+			data:         models.NutritionalData{Energy: 200, Fruits: 100, Fibre: 10, Protein: 10},
+			foodType:     models.FoodType,
+			wantInterval: "A",
+			wantGrades:   []string{"A"},
+			wantRobust:   true,
+		},
+		{
+			// The worst case puts saturated fat above total fat, which must not fail the score
+			name:         "extremes beyond consistency checks",
+			data:         models.NutritionalData{Energy: 3400, SaturatedFattyAcids: 0.9, TotalFat: 1, Sugars: 45, Sodium: 900},
+			foodType:     models.FoodType,
+			wantInterval: "E",
+			wantGrades:   []string{"E"},
+			wantRobust:   true,
+		},
+		{
+			// Saturated fat 37g of 92g total fat reaches 44.4g in the worst case: 48.3% of the
+			// declared total fat (grade D), but 52.9% of the 84g lower total fat (grade E)
+			name:         "fats and oils ratio with total fat tolerance",
+			data:         models.NutritionalData{Energy: 3400, SaturatedFattyAcids: 37, TotalFat: 92},
+			foodType:     models.FatsOilsType,
+			wantInterval: "D–E",
+			wantGrades:   []string{"D", "E"},
+		},
+		{
+			name:         "water",
+			data:         models.NutritionalData{},
+			foodType:     models.WaterType,
+			wantInterval: "A",
+			wantGrades:   []string{"A"},
+			wantRobust:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := scorer.CalculateScoreWithTolerance(tt.data, tt.foodType)
+			if err != nil {
+				t.Fatalf("CalculateScoreWithTolerance() unexpected error: %v", err)
+			}
+			if result.GradeInterval != tt.wantInterval || result.Robust != tt.wantRobust {
+				t.Errorf("CalculateScoreWithTolerance() = %s (robust %v), want %s (robust %v)",
+					result.GradeInterval, result.Robust, tt.wantInterval, tt.wantRobust)
+			}
+			if !reflect.DeepEqual(result.Grades, tt.wantGrades) {
+				t.Errorf("Grades = %v, want %v", result.Grades, tt.wantGrades)
+			}
+			if result.BestCase.Value > result.Score.Value || result.WorstCase.Value < result.Score.Value {
+				t.Errorf("score %d should lie between best case %d and worst case %d",
+					result.Score.Value, result.BestCase.Value, result.WorstCase.Value)
+			}
+		})
+	}
+}
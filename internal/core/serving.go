@@ -0,0 +1,202 @@
+package core
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// NormalizeDeclaration converts a label declaration to the per 100g basis of NutritionalData
+// Liquids are normalised per 100ml. The density converts the serving size when it is
+// declared in the other unit (ml for a solid, g for a liquid)
+func NormalizeDeclaration(declaration models.NutritionDeclaration, liquid bool) (models.NutritionalData, error) {
+	if declaration.Basis == models.Per100 {
+		return declaration.Values, nil
+	}
+	size, err := basisSize(declaration, liquid)
+	if err != nil {
+		return models.NutritionalData{}, err
+	}
+
+	// The fruit percentage does not depend on the amount
+	data := roundNutrients(scaleNutrients(declaration.Values, 100/size))
+	data.Fruits = declaration.Values.Fruits
+	return data, nil
+}
+
+// basisSize returns the serving or pack size in the unit of the per 100 basis (g, or ml for liquids)
+func basisSize(declaration models.NutritionDeclaration, liquid bool) (float64, error) {
+	if declaration.Basis != models.PerServing && declaration.Basis != models.PerPack {
+		return 0, models.NewValidationError("basis", fmt.Sprintf("Unknown declaration basis: %q", declaration.Basis),
+			"Values can be declared per 100, per serving or per pack")
+	}
+	if declaration.Size <= 0 {
+		return 0, models.NewValidationError("size", "Serving size must be greater than 0",
+			"Enter the serving or pack size printed on the label")
+	}
+	if declaration.Density < 0 {
+		return 0, models.NewValidationError("density", "Density cannot be negative", "Enter the density in g per ml")
+	}
+
+	unit := servingUnit(liquid)
+	switch declaration.Unit {
+	case unit:
+		return declaration.Size, nil
+	case models.Grams, models.Millilitres:
+		if declaration.Density == 0 {
+			return 0, models.NewValidationError("density",
+				fmt.Sprintf("A density is needed to convert %s to %s", declaration.Unit, unit), "Enter the density in g per ml")
+		}
+		if declaration.Unit == models.Millilitres {
+			return declaration.Size * declaration.Density, nil
+		}
+		return declaration.Size / declaration.Density, nil
+	default:
+		return 0, models.NewValidationError("unit", fmt.Sprintf("Unknown serving unit: %q", declaration.Unit),
+			"Serving sizes are declared in g or ml")
+	}
+}
+
+// servingUnit returns the unit of the per 100 basis, ml for liquids and g otherwise
+func servingUnit(liquid bool) models.ServingUnit {
+	if liquid {
+		return models.Millilitres
+	}
+	return models.Grams
+}
+
+// ApplyDeclaration stores the normalised values on the food and keeps the original declaration
+func ApplyDeclaration(food models.Food, declaration models.NutritionDeclaration) (models.Food, error) {
+	data, err := NormalizeDeclaration(declaration, food.IsLiquid())
+	if err != nil {
+		return models.Food{}, err
+	}
+
+	food.NutritionalData = data
+	food.Declaration = nil
+	if declaration.Basis != models.Per100 {
+		food.Declaration = &declaration
+	}
+	return food, nil
+}
+
+// ServingSize returns the declared serving size of a food in g, or ml for liquids
+// Foods declared per pack use the whole pack as the serving; 0 means no serving is declared
+func ServingSize(food models.Food) float64 {
+	if food.Declaration == nil || food.Declaration.Basis == models.Per100 {
+		return 0
+	}
+	size, err := basisSize(*food.Declaration, food.IsLiquid())
+	if err != nil {
+		return 0
+	}
+	return roundDistance(size)
+}
+
+// PerServing scales per 100g (or 100ml) data to the amounts in a serving
+func PerServing(data models.NutritionalData, servingSize float64) models.NutritionalData {
+	fruits := data.Fruits
+	data = roundNutrients(scaleNutrients(data.ResolveSodium(), servingSize/100))
+	data.Fruits = fruits
+	return data
+}
+
+// servingNutrients lists the nutrients of a serving table in label order
+var servingNutrients = []struct {
+	name  string
+	unit  string
+	value func(models.NutritionalData) float64
+}{
+	{"energy", "kJ", func(d models.NutritionalData) float64 { return float64(d.Energy) }},
+	{"fat", "g", func(d models.NutritionalData) float64 { return float64(d.TotalFat) }},
+	{"saturates", "g", func(d models.NutritionalData) float64 { return float64(d.SaturatedFattyAcids) }},
+	{"trans fat", "g", func(d models.NutritionalData) float64 { return float64(d.TransFat) }},
+	{"carbohydrate", "g", func(d models.NutritionalData) float64 { return float64(d.Carbohydrate) }},
+	{"sugars", "g", func(d models.NutritionalData) float64 { return float64(d.Sugars) }},
+	{"fibre", "g", func(d models.NutritionalData) float64 { return float64(d.Fibre) }},
+	{"protein", "g", func(d models.NutritionalData) float64 { return float64(d.Protein) }},
+	{"salt", "g", func(d models.NutritionalData) float64 { return float64(d.Salt) }},
+}
+
+// NewServingTable lists the nutrients of a food per 100g (or 100ml) and per serving
+// A serving size of 0 uses the serving declared on the label, whose values are then
+// shown as they were declared
+func NewServingTable(food models.Food, servingSize float64) (models.ServingTable, error) {
+	if servingSize < 0 {
+		return models.ServingTable{}, models.NewValidationError("serving_size", "Serving size cannot be negative")
+	}
+	if servingSize == 0 {
+		servingSize = ServingSize(food)
+	}
+	if servingSize == 0 {
+		return models.ServingTable{}, models.NewValidationError("serving_size", "No serving size declared",
+			"Enter a serving size in g or ml")
+	}
+
+	per100 := food.NutritionalData.ResolveSodium()
+	perServing := PerServing(per100, servingSize)
+	if food.Declaration != nil && servingSize == ServingSize(food) {
+		perServing = roundNutrients(food.Declaration.Values.ResolveSodium())
+	}
+	table := models.ServingTable{
+		FoodID:      food.ID,
+		Liquid:      food.IsLiquid(),
+		ServingSize: servingSize,
+	}
+	if food.Declaration != nil {
+		table.Basis = food.Declaration.Basis
+	}
+	for _, nutrient := range servingNutrients {
+		table.Nutrients = append(table.Nutrients, models.ServingNutrient{
+			Nutrient:   nutrient.name,
+			Unit:       nutrient.unit,
+			Per100:     roundDistance(nutrient.value(per100)),
+			PerServing: nutrient.value(perServing),
+		})
+	}
+
+	return table, nil
+}
+
+// servingCSVHeader lists the columns of a serving table CSV export
+var servingCSVHeader = []string{"food_id", "nutrient", "unit", "per_100", "per_serving", "serving_size"}
+
+// ExportServingTable exports a serving table as JSON or CSV
+// The CSV export has one row per nutrient
+func ExportServingTable(table models.ServingTable, format models.ExportFormat) ([]byte, error) {
+	switch format {
+	case models.JSON:
+		data, err := json.MarshalIndent(table, "", "  ")
+		if err != nil {
+			return nil, models.NewExportError("Failed to export nutrition table", err.Error())
+		}
+		return data, nil
+	case models.CSV:
+		var buf bytes.Buffer
+		writer := csv.NewWriter(&buf)
+		if err := writer.Write(servingCSVHeader); err != nil {
+			return nil, models.NewExportError("Failed to export nutrition table", err.Error())
+		}
+		size := strconv.FormatFloat(table.ServingSize, 'f', -1, 64)
+		for _, nutrient := range table.Nutrients {
+			row := []string{table.FoodID, nutrient.Nutrient, nutrient.Unit,
+				strconv.FormatFloat(nutrient.Per100, 'f', -1, 64),
+				strconv.FormatFloat(nutrient.PerServing, 'f', -1, 64), size}
+			if err := writer.Write(row); err != nil {
+				return nil, models.NewExportError("Failed to export nutrition table", err.Error())
+			}
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return nil, models.NewExportError("Failed to export nutrition table", err.Error())
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, models.NewExportError(fmt.Sprintf("Unsupported export format: %s", format),
+			"nutrition tables can be exported as JSON or CSV")
+	}
+}
@@ -0,0 +1,177 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// TestNormalizeDeclaration tests the conversion of serving and pack values to per 100g or 100ml
+func TestNormalizeDeclaration(t *testing.T) {
+	// One serving of cereal: 30g with 480 kJ, 6g sugars and 45% fruit
+	cereal := models.NutritionalData{Energy: 480, Sugars: 6, Sodium: 60, Fruits: 45, Fibre: 1.5}
+
+	tests := []struct {
+		name        string
+		declaration models.NutritionDeclaration
+		liquid      bool
+		wantEnergy  models.EnergyKJ
+		wantSugars  models.SugarGram
+		wantFruits  models.FruitsPercent
+		wantErr     bool
+	}{
+		{"per 100 unchanged", models.NutritionDeclaration{Basis: models.Per100, Values: cereal}, false, 480, 6, 45, false},
+		{"per serving in g", models.NutritionDeclaration{Basis: models.PerServing, Size: 30, Unit: models.Grams, Values: cereal}, false, 1600, 20, 45, false},
+		{"per pack in g", models.NutritionDeclaration{Basis: models.PerPack, Size: 400, Unit: models.Grams, Values: cereal}, false, 120, 1.5, 45, false},
+		{"liquid per serving in ml", models.NutritionDeclaration{Basis: models.PerServing, Size: 250, Unit: models.Millilitres, Values: cereal}, true, 192, 2.4, 45, false},
+		{"solid per serving in ml", models.NutritionDeclaration{Basis: models.PerServing, Size: 50, Unit: models.Millilitres, Density: 0.6, Values: cereal}, false, 1600, 20, 45, false},
+		{"liquid per serving in g", models.NutritionDeclaration{Basis: models.PerServing, Size: 206, Unit: models.Grams, Density: 1.03, Values: cereal}, true, 240, 3, 45, false},
+		{"missing density", models.NutritionDeclaration{Basis: models.PerServing, Size: 50, Unit: models.Millilitres, Values: cereal}, false, 0, 0, 0, true},
+		{"missing size", models.NutritionDeclaration{Basis: models.PerServing, Unit: models.Grams, Values: cereal}, false, 0, 0, 0, true},
+		{"unknown unit", models.NutritionDeclaration{Basis: models.PerServing, Size: 2, Unit: "oz", Values: cereal}, false, 0, 0, 0, true},
+		{"unknown basis", models.NutritionDeclaration{Basis: "portion", Size: 30, Unit: models.Grams, Values: cereal}, false, 0, 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := NormalizeDeclaration(tt.declaration, tt.liquid)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("NormalizeDeclaration() expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeDeclaration() unexpected error: %v", err)
+			}
+			if data.Energy != tt.wantEnergy || data.Sugars != tt.wantSugars || data.Fruits != tt.wantFruits {
+				t.Errorf("NormalizeDeclaration() = %v kJ, %v g sugars, %v%% fruit, want %v kJ, %v g, %v%%",
+					data.Energy, data.Sugars, data.Fruits, tt.wantEnergy, tt.wantSugars, tt.wantFruits)
+			}
+		})
+	}
+}
+
+// TestApplyDeclaration tests that the food keeps its original declaration
+func TestApplyDeclaration(t *testing.T) {
+	declaration := models.NutritionDeclaration{Basis: models.PerServing, Size: 30, Unit: models.Grams,
+		Values: models.NutritionalData{Energy: 480, Sugars: 6, Sodium: 60}}
+
+	food, err := ApplyDeclaration(models.Food{ID: "cereal"}, declaration)
+	if err != nil {
+		t.Fatalf("ApplyDeclaration() unexpected error: %v", err)
+	}
+	if food.NutritionalData.Energy != 1600 {
+		t.Errorf("Energy = %v, want 1600 per 100g", food.NutritionalData.Energy)
+	}
+	if food.Declaration == nil || food.Declaration.Values.Energy != 480 {
+		t.Fatal("ApplyDeclaration() should keep the declared values")
+	}
+	if ServingSize(food) != 30 {
+		t.Errorf("ServingSize() = %v, want 30", ServingSize(food))
+	}
+
+	// Per 100 declarations need no record of the original values
+	per100, err := ApplyDeclaration(food, models.NutritionDeclaration{Basis: models.Per100, Values: food.NutritionalData})
+	if err != nil {
+		t.Fatalf("ApplyDeclaration() unexpected error: %v", err)
+	}
+	if per100.Declaration != nil || ServingSize(per100) != 0 {
+		t.Error("per 100 declaration should not declare a serving")
+	}
+}
+
+// TestNewServingTable tests nutrients per 100g and per serving
+func TestNewServingTable(t *testing.T) {
+	food, err := ApplyDeclaration(models.Food{ID: "cola", Liquid: true}, models.NutritionDeclaration{
+		Basis: models.PerServing, Size: 330, Unit: models.Millilitres,
+		Values: models.NutritionalData{Energy: 594, Sugars: 34.98, Salt: 0.033}})
+	if err != nil {
+		t.Fatalf("ApplyDeclaration() unexpected error: %v", err)
+	}
+
+	table, err := NewServingTable(food, 0)
+	if err != nil {
+		t.Fatalf("NewServingTable() unexpected error: %v", err)
+	}
+	if table.ServingSize != 330 || !table.Liquid || table.Basis != models.PerServing {
+		t.Errorf("table = %v ml (liquid %v, %s), want 330 ml liquid per serving", table.ServingSize, table.Liquid, table.Basis)
+	}
+
+	want := map[string][2]float64{"energy": {180, 594}, "sugars": {10.6, 34.98}, "salt": {0.01, 0.033}}
+	for _, nutrient := range table.Nutrients {
+		amounts, found := want[nutrient.Nutrient]
+		if !found {
+			continue
+		}
+		if nutrient.Per100 != amounts[0] || nutrient.PerServing != amounts[1] {
+			t.Errorf("%s = %v / %v, want %v / %v", nutrient.Nutrient, nutrient.Per100, nutrient.PerServing, amounts[0], amounts[1])
+		}
+	}
+
+	// An explicit serving size overrides the declaration
+	table, err = NewServingTable(food, 500)
+	if err != nil {
+		t.Fatalf("NewServingTable() unexpected error: %v", err)
+	}
+	if table.Nutrients[0].PerServing != 900 {
+		t.Errorf("energy per 500ml = %v, want 900", table.Nutrients[0].PerServing)
+	}
+
+	food.Declaration = nil
+	if _, err := NewServingTable(food, 0); err == nil {
+		t.Error("NewServingTable() expected error without a serving size, got nil")
+	}
+}
+
+// TestExportServingTable tests the JSON and CSV exports of a serving table
+func TestExportServingTable(t *testing.T) {
+	food := models.Food{ID: "cereal", NutritionalData: models.NutritionalData{Energy: 1600, Sugars: 20}}
+	table, err := NewServingTable(food, 30)
+	if err != nil {
+		t.Fatalf("NewServingTable() unexpected error: %v", err)
+	}
+
+	data, err := ExportServingTable(table, models.CSV)
+	if err != nil {
+		t.Fatalf("ExportServingTable() unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if lines[0] != "food_id,nutrient,unit,per_100,per_serving,serving_size" {
+		t.Errorf("CSV header = %q", lines[0])
+	}
+	if lines[1] != "cereal,energy,kJ,1600,480,30" {
+		t.Errorf("CSV energy row = %q", lines[1])
+	}
+
+	if data, err := ExportServingTable(table, models.JSON); err != nil || !strings.Contains(string(data), `"per_serving": 480`) {
+		t.Errorf("ExportServingTable(JSON) = %s, %v", data, err)
+	}
+	if _, err := ExportServingTable(table, models.XML); err == nil {
+		t.Error("ExportServingTable(XML) expected error, got nil")
+	}
+}
+
+// TestProfiler_ServingDeclaration tests that the profile uses the declared serving size
+func TestProfiler_ServingDeclaration(t *testing.T) {
+	food, err := ApplyDeclaration(models.Food{ID: "crisps"}, models.NutritionDeclaration{
+		Basis: models.PerPack, Size: 150, Unit: models.Grams,
+		Values: models.NutritionalData{Energy: 4000, TotalFat: 45, SaturatedFattyAcids: 4.5, Sugars: 1.5, Salt: 2.25}})
+	if err != nil {
+		t.Fatalf("ApplyDeclaration() unexpected error: %v", err)
+	}
+
+	report := NewProfiler().Profile(food, models.FoodType)
+	if report.Nutrition == nil || report.Nutrition.ServingSize != 150 {
+		t.Fatalf("Nutrition = %+v, want a 150 g serving", report.Nutrition)
+	}
+	if report.TrafficLights == nil || report.TrafficLights.ServingSize != 150 {
+		t.Errorf("traffic lights should be evaluated per 150 g portion")
+	}
+
+	rows := ProfileReportRows(report)
+	if got := strings.Join(rows[0], "|"); got != "nutrition|energy|2666.6667 kJ per 100g|4000|kJ per 150 g serving" {
+		t.Errorf("first row = %q", got)
+	}
+}
@@ -0,0 +1,173 @@
+package core
+
+import (
+	"github.com/nutritional-score/pkg/models"
+)
+
+// TrafficLightCriterion holds the UK front-of-pack thresholds of one nutrient
+// Values up to GreenMax are green, up to AmberMax amber and above AmberMax red
+type TrafficLightCriterion struct {
+	Nutrient        string  // Nutrient key ("fat", "saturates", "sugars", "salt")
+	Label           string  // Display name as printed on the label
+	GreenMax        float64 // Highest green amount per 100g (or 100ml)
+	AmberMax        float64 // Highest amber amount per 100g (or 100ml)
+	ReferenceIntake float64 // Adult reference intake in grams per day
+}
+
+// TrafficLightCriteria holds the criteria for foods or drinks
+// Portions larger than MinPortion are red when they exceed PortionRedPercent of the reference intake
+type TrafficLightCriteria struct {
+	Nutrients         []TrafficLightCriterion
+	MinPortion        float64 // Portion size in g or ml from which the portion criteria apply
+	PortionRedPercent float64 // Share of the reference intake per portion that is always red
+}
+
+// Adult reference intakes in grams per day (Regulation 1169/2011, Annex XIII)
+const (
+	fatReferenceIntake       = 70
+	saturatesReferenceIntake = 20
+	sugarsReferenceIntake    = 90
+	saltReferenceIntake      = 6
+)
+
+// FoodTrafficLightCriteria returns the UK criteria for foods per 100g
+// Portions above 100g are red from 30% of the reference intake
+func FoodTrafficLightCriteria() TrafficLightCriteria {
+	return TrafficLightCriteria{
+		Nutrients: []TrafficLightCriterion{
+			{Nutrient: "fat", Label: "Fat", GreenMax: 3.0, AmberMax: 17.5, ReferenceIntake: fatReferenceIntake},
+			{Nutrient: "saturates", Label: "Saturates", GreenMax: 1.5, AmberMax: 5.0, ReferenceIntake: saturatesReferenceIntake},
+			{Nutrient: "sugars", Label: "Sugars", GreenMax: 5.0, AmberMax: 22.5, ReferenceIntake: sugarsReferenceIntake},
+			{Nutrient: "salt", Label: "Salt", GreenMax: 0.3, AmberMax: 1.5, ReferenceIntake: saltReferenceIntake},
+		},
+		MinPortion:        100,
+		PortionRedPercent: 30,
+	}
+}
+
+// DrinkTrafficLightCriteria returns the UK criteria for drinks per 100ml
+// Drinks use half the food thresholds (salt keeps its green threshold); portions above
+// 150ml are red from 25% of the reference intake
+func DrinkTrafficLightCriteria() TrafficLightCriteria {
+	return TrafficLightCriteria{
+		Nutrients: []TrafficLightCriterion{
+			{Nutrient: "fat", Label: "Fat", GreenMax: 1.5, AmberMax: 8.75, ReferenceIntake: fatReferenceIntake},
+			{Nutrient: "saturates", Label: "Saturates", GreenMax: 0.75, AmberMax: 2.5, ReferenceIntake: saturatesReferenceIntake},
+			{Nutrient: "sugars", Label: "Sugars", GreenMax: 2.5, AmberMax: 11.25, ReferenceIntake: sugarsReferenceIntake},
+			{Nutrient: "salt", Label: "Salt", GreenMax: 0.3, AmberMax: 0.75, ReferenceIntake: saltReferenceIntake},
+		},
+		MinPortion:        150,
+		PortionRedPercent: 25,
+	}
+}
+
+// TrafficLightEvaluator rates products with the UK front-of-pack traffic light scheme
+// Fat, saturates, sugars and salt are rated green, amber or red per 100g, and large
+// portions can turn a light red
+type TrafficLightEvaluator struct {
+	food      TrafficLightCriteria
+	drink     TrafficLightCriteria
+	validator models.InputValidator
+}
+
+// NewTrafficLightEvaluator creates an evaluator with the UK Department of Health criteria (2016)
+func NewTrafficLightEvaluator() *TrafficLightEvaluator {
+	return &TrafficLightEvaluator{
+		food:      FoodTrafficLightCriteria(),
+		drink:     DrinkTrafficLightCriteria(),
+		validator: NewInputValidator(),
+	}
+}
+
+// Evaluate rates the nutrients of a product per 100g and, when a serving size in g or ml
+// is given, applies the per-portion red overrides
+// Beverages and water are rated with the drink criteria
+func (tle *TrafficLightEvaluator) Evaluate(data models.NutritionalData, scoreType models.ScoreType, servingSize float64) (models.TrafficLightLabel, error) {
+	validation := tle.validator.CheckNutritionalDataFor(data, scoreType)
+	if validation.HasErrors() {
+		return models.TrafficLightLabel{}, validation
+	}
+	if err := requireKnownValues(data); err != nil {
+		return models.TrafficLightLabel{}, err
+	}
+	if servingSize < 0 {
+		return models.TrafficLightLabel{}, models.ValidationError{
+			Field:   "serving_size",
+			Value:   servingSize,
+			Message: "Serving size cannot be negative",
+		}
+	}
+	// The fat light needs total fat, which can never be lower than saturated fat
+	if data.TotalFat <= 0 && data.SaturatedFattyAcids > 0 {
+		return models.TrafficLightLabel{}, models.ValidationError{
+			Field:   "total_fat",
+			Value:   float64(data.TotalFat),
+			Message: "Total fat is required for traffic light labels",
+		}
+	}
+
+	criteria := tle.food
+	if scoreType == models.BeverageType || scoreType == models.WaterType {
+		criteria = tle.drink
+	}
+
+	data = data.ResolveSodium()
+	amounts := map[string]float64{
+		"fat":       float64(data.TotalFat),
+		"saturates": float64(data.SaturatedFattyAcids),
+		"sugars":    float64(data.Sugars),
+		"salt":      float64(data.Salt),
+	}
+
+	label := models.TrafficLightLabel{
+		ScoreType:   scoreType,
+		ServingSize: servingSize,
+	}
+	for _, criterion := range criteria.Nutrients {
+		label.Lights = append(label.Lights, criteria.rate(criterion, amounts[criterion.Nutrient], servingSize))
+	}
+
+	return label, nil
+}
+
+// EvaluateAnalysis rates the food of an analysis using its score type and serving size
+func (tle *TrafficLightEvaluator) EvaluateAnalysis(analysis models.NutritionalAnalysis) (models.TrafficLightLabel, error) {
+	return tle.Evaluate(analysis.Food.NutritionalData, analysis.Score.ScoreType, analysis.ServingSize)
+}
+
+// rate computes the traffic light of one nutrient
+func (tc TrafficLightCriteria) rate(criterion TrafficLightCriterion, per100g, servingSize float64) models.NutrientTrafficLight {
+	light := models.NutrientTrafficLight{
+		Nutrient:      criterion.Nutrient,
+		Label:         criterion.Label,
+		Per100g:       per100g,
+		Per100gColour: criterion.colour(per100g),
+	}
+	light.Colour = light.Per100gColour
+
+	if servingSize > 0 {
+		perPortion := roundDistance(per100g * servingSize / 100)
+		percent := roundDistance(perPortion / criterion.ReferenceIntake * 100)
+		light.PerPortion = &perPortion
+		light.ReferenceIntakePercent = &percent
+
+		if servingSize > tc.MinPortion && percent > tc.PortionRedPercent && light.Colour != models.TrafficLightRed {
+			light.Colour = models.TrafficLightRed
+			light.PortionOverride = true
+		}
+	}
+
+	return light
+}
+
+// colour returns the per 100g colour of an amount
+func (tc TrafficLightCriterion) colour(amount float64) models.TrafficLightColour {
+	switch {
+	case amount <= tc.GreenMax:
+		return models.TrafficLightGreen
+	case amount <= tc.AmberMax:
+		return models.TrafficLightAmber
+	default:
+		return models.TrafficLightRed
+	}
+}
@@ -0,0 +1,181 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// TestTrafficLightEvaluator_Per100g tests the per 100g colours of foods and drinks
+func TestTrafficLightEvaluator_Per100g(t *testing.T) {
+	evaluator := NewTrafficLightEvaluator()
+
+	tests := []struct {
+		name      string
+		data      models.NutritionalData
+		scoreType models.ScoreType
+		expected  map[string]models.TrafficLightColour
+	}{
+		{
+			name:      "Crisps",
+			data:      models.NutritionalData{TotalFat: 32, SaturatedFattyAcids: 2.6, Sugars: 0.6, Salt: 1.3},
+			scoreType: models.FoodType,
+			expected: map[string]models.TrafficLightColour{
+				"fat": models.TrafficLightRed, "saturates": models.TrafficLightAmber,
+				"sugars": models.TrafficLightGreen, "salt": models.TrafficLightAmber,
+			},
+		},
+		{
+			name:      "Boundaries Are Inclusive",
+			data:      models.NutritionalData{TotalFat: 17.5, SaturatedFattyAcids: 1.5, Sugars: 22.5, Salt: 1.51},
+			scoreType: models.FoodType,
+			expected: map[string]models.TrafficLightColour{
+				"fat": models.TrafficLightAmber, "saturates": models.TrafficLightGreen,
+				"sugars": models.TrafficLightAmber, "salt": models.TrafficLightRed,
+			},
+		},
+		{
+			name:      "Cola Uses Drink Criteria",
+			data:      models.NutritionalData{Sugars: 10.6, Sodium: 4},
+			scoreType: models.BeverageType,
+			expected: map[string]models.TrafficLightColour{
+				"fat": models.TrafficLightGreen, "saturates": models.TrafficLightGreen,
+				"sugars": models.TrafficLightAmber, "salt": models.TrafficLightGreen,
+			},
+		},
+		{
+			name:      "Sodium Is Converted To Salt",
+			data:      models.NutritionalData{TotalFat: 1, Sodium: 800},
+			scoreType: models.FoodType,
+			expected: map[string]models.TrafficLightColour{
+				"fat": models.TrafficLightGreen, "saturates": models.TrafficLightGreen,
+				"sugars": models.TrafficLightGreen, "salt": models.TrafficLightRed, // 2g salt
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			label, err := evaluator.Evaluate(tt.data, tt.scoreType, 0)
+			if err != nil {
+				t.Fatalf("Evaluate() unexpected error: %v", err)
+			}
+			if len(label.Lights) != 4 {
+				t.Fatalf("Evaluate() returned %d lights, want 4", len(label.Lights))
+			}
+			for nutrient, expected := range tt.expected {
+				light, ok := label.Light(nutrient)
+				if !ok {
+					t.Fatalf("Light(%q) missing", nutrient)
+				}
+				if light.Colour != expected {
+					t.Errorf("%s colour = %s, want %s", nutrient, light.Colour, expected)
+				}
+				if light.PerPortion != nil {
+					t.Errorf("%s should have no portion amount without a serving size", nutrient)
+				}
+			}
+		})
+	}
+}
+
+// TestTrafficLightEvaluator_PortionOverride tests the per-portion red overrides
+func TestTrafficLightEvaluator_PortionOverride(t *testing.T) {
+	evaluator := NewTrafficLightEvaluator()
+
+	t.Run("Large Food Portion", func(t *testing.T) {
+		// 20g sugars per 100g is amber; 150g portion has 30g sugars (33% of 90g)
+		data := models.NutritionalData{TotalFat: 2, SaturatedFattyAcids: 1, Sugars: 20, Salt: 0.1}
+		label, err := evaluator.Evaluate(data, models.FoodType, 150)
+		if err != nil {
+			t.Fatalf("Evaluate() unexpected error: %v", err)
+		}
+		sugars, _ := label.Light("sugars")
+		if sugars.Per100gColour != models.TrafficLightAmber || sugars.Colour != models.TrafficLightRed || !sugars.PortionOverride {
+			t.Errorf("sugars = %s per 100g, %s shown (override %v), want amber turned red", sugars.Per100gColour, sugars.Colour, sugars.PortionOverride)
+		}
+		if *sugars.PerPortion != 30 || *sugars.ReferenceIntakePercent != 33.3333 {
+			t.Errorf("sugars per portion = %v g (%v%%), want 30 g (33.3333%%)", *sugars.PerPortion, *sugars.ReferenceIntakePercent)
+		}
+		if fat, _ := label.Light("fat"); fat.Colour != models.TrafficLightGreen {
+			t.Errorf("fat colour = %s, want green", fat.Colour)
+		}
+	})
+
+	t.Run("Small Portion Is Not Overridden", func(t *testing.T) {
+		// 100g portions do not use the portion criteria even above 30% of the reference intake
+		data := models.NutritionalData{TotalFat: 2, SaturatedFattyAcids: 1, Sugars: 22, Salt: 0.1}
+		label, err := evaluator.Evaluate(data, models.FoodType, 100)
+		if err != nil {
+			t.Fatalf("Evaluate() unexpected error: %v", err)
+		}
+		if sugars, _ := label.Light("sugars"); sugars.Colour != models.TrafficLightAmber {
+			t.Errorf("sugars colour = %s, want amber", sugars.Colour)
+		}
+	})
+
+	t.Run("Large Drink Portion", func(t *testing.T) {
+		// 7g sugars per 100ml is amber; a 330ml can has 23.1g sugars (25.7% of 90g)
+		data := models.NutritionalData{Sugars: 7}
+		label, err := evaluator.Evaluate(data, models.BeverageType, 330)
+		if err != nil {
+			t.Fatalf("Evaluate() unexpected error: %v", err)
+		}
+		if sugars, _ := label.Light("sugars"); sugars.Colour != models.TrafficLightRed || !sugars.PortionOverride {
+			t.Errorf("sugars colour = %s (override %v), want red by portion", sugars.Colour, sugars.PortionOverride)
+		}
+	})
+
+	t.Run("Analysis Serving Size", func(t *testing.T) {
+		analysis := models.NutritionalAnalysis{
+			Food:        models.Food{NutritionalData: models.NutritionalData{TotalFat: 15, SaturatedFattyAcids: 4, Sugars: 3, Salt: 0.5}},
+			Score:       models.NutritionalScore{ScoreType: models.FoodType},
+			ServingSize: 200,
+		}
+		label, err := evaluator.EvaluateAnalysis(analysis)
+		if err != nil {
+			t.Fatalf("EvaluateAnalysis() unexpected error: %v", err)
+		}
+		if label.ServingSize != 200 {
+			t.Errorf("ServingSize = %v, want 200", label.ServingSize)
+		}
+		// 30g fat and 8g saturates per portion exceed 30% of 70g and 20g
+		for _, nutrient := range []string{"fat", "saturates"} {
+			if light, _ := label.Light(nutrient); light.Colour != models.TrafficLightRed {
+				t.Errorf("%s colour = %s, want red", nutrient, light.Colour)
+			}
+		}
+	})
+}
+
+// TestTrafficLightEvaluator_Errors tests invalid input
+func TestTrafficLightEvaluator_Errors(t *testing.T) {
+	evaluator := NewTrafficLightEvaluator()
+
+	tests := []struct {
+		name        string
+		data        models.NutritionalData
+		servingSize float64
+	}{
+		{"Missing Total Fat", models.NutritionalData{SaturatedFattyAcids: 3}, 0},
+		{"Negative Serving Size", models.NutritionalData{TotalFat: 3}, -30},
+		{"Invalid Sugars", models.NutritionalData{Sugars: -1}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := evaluator.Evaluate(tt.data, models.FoodType, tt.servingSize); err == nil {
+				t.Error("Evaluate() should return an error")
+			}
+		})
+	}
+
+	t.Run("Beverage Profile", func(t *testing.T) {
+		// Valid for a food, but above the energy limit of the beverage profile
+		data := models.NutritionalData{Energy: 1500, Sugars: 85}
+		if _, err := evaluator.Evaluate(data, models.BeverageType, 0); err == nil || !strings.Contains(err.Error(), "Energy cannot exceed 1000.0 kJ") {
+			t.Errorf("Evaluate() error = %v, want the beverage energy limit", err)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package core
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// builtinValidationProfiles is the JSON source of the built-in validation profiles
+//
+//go:embed validationprofiles.json
+var builtinValidationProfiles []byte
+
+// DefaultValidationProfiles returns the built-in validation profiles of validationprofiles.json
+// Drinks are validated strictly per 100ml, while oils, bran-rich grains and supplements
+// may exceed the ranges of ordinary foods
+func DefaultValidationProfiles() models.ValidationProfiles {
+	profiles, err := ParseValidationProfiles(builtinValidationProfiles)
+	if err != nil {
+		panic(err)
+	}
+	return profiles
+}
+
+// validationProfilesFile is the JSON layout of a validation profiles file
+// Every profile only lists the rules it changes; score types are keyed by their ruleset name
+type validationProfilesFile struct {
+	Default    json.RawMessage            `json:"default"`
+	Categories map[string]json.RawMessage `json:"categories"`
+	ScoreTypes map[string]json.RawMessage `json:"score_types"`
+}
+
+// LoadValidationProfiles reads and validates a validation profiles JSON file
+func LoadValidationProfiles(path string) (models.ValidationProfiles, error) {
+	fileData, err := os.ReadFile(path)
+	if err != nil {
+		return models.ValidationProfiles{}, models.NewConfigError("Failed to read validation profiles file", err.Error())
+	}
+	return ParseValidationProfiles(fileData)
+}
+
+// ParseValidationProfiles decodes and validates validation profiles from JSON data
+// The default profile overrides the built-in default rules, and every category and score
+// type profile overrides the default profile. Categories are stored under their lower-case key,
+// score types are named as in ruleset files ("beverage", "fats_oils", ...)
+func ParseValidationProfiles(data []byte) (models.ValidationProfiles, error) {
+	var file validationProfilesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return models.ValidationProfiles{}, models.NewConfigError("Failed to parse validation profiles JSON", err.Error())
+	}
+
+	collection := models.ErrorCollection{Operation: "validation profiles"}
+	profiles := models.ValidationProfiles{
+		Categories: make(map[string]models.NutritionalDataValidation, len(file.Categories)),
+		ScoreTypes: make(map[models.ScoreType]models.NutritionalDataValidation, len(file.ScoreTypes)),
+	}
+
+	var err error
+	if profiles.Default, err = overrideRules(models.DefaultValidationRules(), file.Default); err != nil {
+		collection.AddError(models.NewConfigError("Default validation profile is invalid", err.Error()))
+	}
+	for category, raw := range file.Categories {
+		key := models.CategoryKey(category)
+		if _, duplicate := profiles.Categories[key]; duplicate {
+			collection.AddError(models.NewConfigError(fmt.Sprintf("Validation profile for category %s is defined more than once", category), ""))
+			continue
+		}
+		rules, err := overrideRules(profiles.Default, raw)
+		if err != nil {
+			collection.AddError(models.NewConfigError(fmt.Sprintf("Validation profile for category %s is invalid", category), err.Error()))
+			continue
+		}
+		profiles.Categories[key] = rules
+	}
+	for name, raw := range file.ScoreTypes {
+		scoreType, found := scoreTypeByName(name)
+		if !found {
+			collection.AddError(models.NewConfigError(fmt.Sprintf("Validation profile for unknown score type %q", name), ""))
+			continue
+		}
+		rules, err := overrideRules(profiles.Default, raw)
+		if err != nil {
+			collection.AddError(models.NewConfigError(fmt.Sprintf("Validation profile for %s is invalid", name), err.Error()))
+			continue
+		}
+		profiles.ScoreTypes[scoreType] = rules
+	}
+
+	if collection.HasErrors() {
+		collection.Summary = collection.Errors[0].Message
+		return models.ValidationProfiles{}, collection
+	}
+	return profiles, nil
+}
+
+// overrideRules applies the rules listed in a profile to the base rules
+// Returns an error if a minimum ends up above its maximum
+func overrideRules(base models.NutritionalDataValidation, raw json.RawMessage) (models.NutritionalDataValidation, error) {
+	rules := base
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &rules); err != nil {
+			return rules, err
+		}
+	}
+
+	ranges := []struct {
+		name     string
+		min, max float64
+	}{
+		{"energy", rules.EnergyMin, rules.EnergyMax},
+		{"sugars", rules.SugarsMin, rules.SugarsMax},
+		{"saturated fat", rules.SaturatedFatMin, rules.SaturatedFatMax},
+		{"total fat", rules.TotalFatMin, rules.TotalFatMax},
+		{"trans fat", rules.TransFatMin, rules.TransFatMax},
+		{"carbohydrate", rules.CarbohydrateMin, rules.CarbohydrateMax},
+		{"sodium", rules.SodiumMin, rules.SodiumMax},
+		{"salt", rules.SaltMin, rules.SaltMax},
+		{"fruits", rules.FruitsMin, rules.FruitsMax},
+		{"fibre", rules.FibreMin, rules.FibreMax},
+		{"protein", rules.ProteinMin, rules.ProteinMax},
+	}
+	for _, r := range ranges {
+		if r.min > r.max {
+			return rules, fmt.Errorf("%s minimum %g is above its maximum %g", r.name, r.min, r.max)
+		}
+	}
+	return rules, nil
+}
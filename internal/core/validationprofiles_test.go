@@ -0,0 +1,175 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// TestLoadValidationProfiles tests the built-in profiles and loading them from a file
+func TestLoadValidationProfiles(t *testing.T) {
+	profiles := DefaultValidationProfiles()
+	beverages := profiles.RulesFor("", models.BeverageType)
+	if beverages.EnergyMax != 1000 || beverages.SugarsMax != 60 || beverages.EnergyMin != profiles.Default.EnergyMin {
+		t.Errorf("Beverage profile = energy %g-%g, sugars max %g, want the default minimum, 1000 and 60",
+			beverages.EnergyMin, beverages.EnergyMax, beverages.SugarsMax)
+	}
+	if water := profiles.RulesFor("", models.WaterType); water != beverages {
+		t.Errorf("Water profile = %+v, want the beverage profile", water)
+	}
+	if fats := profiles.RulesFor("", models.FatsOilsType); fats.EnergyMax != 4500 {
+		t.Errorf("Fats and oils energy max = %g, want 4500", fats.EnergyMax)
+	}
+	if grains := profiles.RulesFor("Grains", models.FoodType); grains.FibreMax != 60 {
+		t.Errorf("Grains fibre max = %g, want 60", grains.FibreMax)
+	}
+
+	loaded, err := LoadValidationProfiles("validationprofiles.json")
+	if err != nil {
+		t.Fatalf("LoadValidationProfiles() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, profiles) {
+		t.Errorf("Loaded profiles = %+v, want the built-in profiles", loaded)
+	}
+	if _, err := LoadValidationProfiles("missing.json"); err == nil {
+		t.Error("LoadValidationProfiles() should fail for a missing file")
+	}
+}
+
+// TestParseValidationProfiles tests that profiles override the default rules and are checked
+func TestParseValidationProfiles(t *testing.T) {
+	profiles, err := ParseValidationProfiles([]byte(`{
+		"default": {"sodium_warning": 3000},
+		"categories": {"Spices": {"sodium_max": 40000}}
+	}`))
+	if err != nil {
+		t.Fatalf("ParseValidationProfiles() unexpected error: %v", err)
+	}
+	spices := profiles.RulesFor("spices", models.FoodType)
+	if spices.SodiumMax != 40000 || spices.SodiumWarning != 3000 || spices.EnergyMax != 4000 {
+		t.Errorf("Spices profile = sodium max %g, sodium warning %g, energy max %g, want 40000, 3000 and 4000",
+			spices.SodiumMax, spices.SodiumWarning, spices.EnergyMax)
+	}
+	if rules := profiles.RulesFor("Dairy", models.BeverageType); rules != profiles.Default {
+		t.Errorf("RulesFor() without a profile = %+v, want the default rules", rules)
+	}
+
+	invalid := []struct {
+		name string
+		json string
+	}{
+		{"Malformed JSON", `{"default": `},
+		{"Minimum Above Maximum", `{"categories": {"Oils": {"energy_min": 5000}}}`},
+		{"Unknown Score Type", `{"score_types": {"snacks": {"energy_max": 1000}}}`},
+		{"Score Type By Number", `{"score_types": {"1": {"energy_max": 1000}}}`},
+		{"Duplicate Category", `{"categories": {"Oils": {}, "oils": {}}}`},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseValidationProfiles([]byte(tt.json)); err == nil {
+				t.Error("ParseValidationProfiles() expected an error")
+			}
+		})
+	}
+}
+
+// TestNewInputValidator_Profiles tests that validators use the built-in profiles unless given a profiles file
+func TestNewInputValidator_Profiles(t *testing.T) {
+	if profiles := NewInputValidator().GetValidationProfiles(); !reflect.DeepEqual(*profiles, DefaultValidationProfiles()) {
+		t.Errorf("Profiles = %+v, want the built-in profiles", *profiles)
+	}
+
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	if err := os.WriteFile(path, []byte(`{"categories": {"Spices": {"sodium_max": 40000}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	profiles, err := LoadValidationProfiles(path)
+	if err != nil {
+		t.Fatalf("LoadValidationProfiles() unexpected error: %v", err)
+	}
+	if rules := NewInputValidatorWithProfiles(profiles).RulesFor(" SPICES ", models.FoodType); rules.SodiumMax != 40000 {
+		t.Errorf("Spices sodium max = %g, want 40000 from the profiles file", rules.SodiumMax)
+	}
+}
+
+// TestInputValidator_ValidateFoodProfiles tests that foods are validated with their category profile
+func TestInputValidator_ValidateFoodProfiles(t *testing.T) {
+	validator := NewInputValidator()
+
+	tests := []struct {
+		name     string
+		category string
+		liquid   bool
+		data     models.NutritionalData
+		wantErr  bool
+	}{
+		{"Oil Above Food Energy Range", "Oils", false, models.NutritionalData{Energy: 4200, TotalFat: 100, SaturatedFattyAcids: 14}, false},
+		{"Same Energy In Dairy", "Dairy", false, models.NutritionalData{Energy: 4200, TotalFat: 100, SaturatedFattyAcids: 14}, true},
+		{"Bran Above Food Fibre Range", "Grains", false, models.NutritionalData{Energy: 1100, Fibre: 55}, false},
+		{"Same Fibre In Snacks", "Snacks", false, models.NutritionalData{Energy: 1100, Fibre: 55}, true},
+		{"Fibre Supplement", "Supplements", false, models.NutritionalData{Energy: 800, Fibre: 80}, false},
+		{"Syrup-Strength Drink", "Drinks", true, models.NutritionalData{Energy: 1500, Sugars: 60}, true},
+		{"Soft Drink", "Drinks", true, models.NutritionalData{Energy: 180, Sugars: 10.6}, false},
+		{"Beverages Category Is Liquid", "Beverages", false, models.NutritionalData{Energy: 180, Sodium: 1500}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			food := models.Food{Name: tt.name, Category: tt.category, Liquid: tt.liquid, NutritionalData: tt.data}
+			var errors []models.ValidationError
+			for _, result := range validator.ValidateFood(food) {
+				if !result.IsWarning() {
+					errors = append(errors, result)
+				}
+			}
+			if (len(errors) > 0) != tt.wantErr {
+				t.Errorf("ValidateFood() errors = %v, wantErr %v", errors, tt.wantErr)
+			}
+		})
+	}
+
+	// Category keys of custom profiles are normalised
+	profiles := DefaultValidationProfiles()
+	profiles.Categories = map[string]models.NutritionalDataValidation{"Oils": profiles.Categories["oils"]}
+	oils := NewInputValidatorWithProfiles(profiles).RulesFor("OILS", models.FoodType)
+	if oils.EnergyMax != 4500 {
+		t.Errorf("Oils energy max = %g, want 4500", oils.EnergyMax)
+	}
+
+	// Custom rules apply to every category
+	oil := models.Food{Name: "Oil", Category: "Oils", NutritionalData: models.NutritionalData{Energy: 4200, TotalFat: 100}}
+	if errors := NewInputValidatorWithRules(models.DefaultValidationRules()).ValidateFood(oil); len(errors) == 0 {
+		t.Error("ValidateFood() with custom rules should ignore the category profiles")
+	}
+	// Nutritional data on its own uses the default profile
+	if errors := validator.ValidateNutritionalData(oil.NutritionalData); len(errors) == 0 {
+		t.Error("ValidateNutritionalData() should use the default rules")
+	}
+}
+
+// TestCalculateScore_ValidationProfiles tests that scores are validated with the profile of their score type
+func TestCalculateScore_ValidationProfiles(t *testing.T) {
+	// Above the 4000 kJ food range, but within the 4500 kJ range of the fats and oils profile
+	ghee := models.NutritionalData{Energy: 4100, TotalFat: 99.8, SaturatedFattyAcids: 61.9}
+	scorer := NewNutritionalScorer()
+
+	if _, err := scorer.CalculateScore(ghee, models.FoodType); err == nil {
+		t.Error("CalculateScore() as food should reject energy above the food range")
+	}
+	if _, err := scorer.CalculateScore(ghee, models.FatsOilsType); err != nil {
+		t.Errorf("CalculateScore() as fats and oils unexpected error: %v", err)
+	}
+
+	// Foods are validated with the fats and oils profile when scored as such
+	validator := NewInputValidator()
+	food := models.Food{Name: "Ghee", Category: "Dairy", NutritionalData: ghee}
+	if errors := validator.ValidateFoodFor(food, models.FatsOilsType); len(errors) != 0 {
+		t.Errorf("ValidateFoodFor() as fats and oils = %v, want no errors", errors)
+	}
+	if errors := validator.ValidateFood(food); len(errors) == 0 {
+		t.Error("ValidateFood() should validate a solid food with the food range")
+	}
+}
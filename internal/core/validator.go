@@ -2,7 +2,8 @@ package core
 
 import (
 	"fmt"
-	"nutritional-score/pkg/models"
+	"github.com/nutritional-score/pkg/models"
+	"math"
 	"strings"
 )
 
@@ -10,16 +11,32 @@ import (
 // This struct ensures data integrity and provides helpful error messages
 type InputValidator struct {
 	validationRules models.NutritionalDataValidation
+	profiles        *models.ValidationProfiles
 }
 
 // NewInputValidator creates a new input validator with default validation rules
+// Complete foods are validated with the built-in profile of their category; use
+// LoadValidationProfiles and NewInputValidatorWithProfiles for custom profiles
 func NewInputValidator() *InputValidator {
+	profiles := DefaultValidationProfiles()
 	return &InputValidator{
-		validationRules: models.DefaultValidationRules(),
+		validationRules: profiles.Default,
+		profiles:        &profiles,
+	}
+}
+
+// NewInputValidatorWithProfiles creates a validator with per-category validation profiles
+// Nutritional data on its own is validated with the default profile
+func NewInputValidatorWithProfiles(profiles models.ValidationProfiles) *InputValidator {
+	profiles = profiles.WithCategoryKeys()
+	return &InputValidator{
+		validationRules: profiles.Default,
+		profiles:        &profiles,
 	}
 }
 
 // NewInputValidatorWithRules creates a validator with custom validation rules
+// The rules apply to every food regardless of its category
 func NewInputValidatorWithRules(rules models.NutritionalDataValidation) *InputValidator {
 	return &InputValidator{
 		validationRules: rules,
@@ -27,7 +44,8 @@ func NewInputValidatorWithRules(rules models.NutritionalDataValidation) *InputVa
 }
 
 // ValidateNutritionalData validates all nutritional data fields against defined rules
-// Returns a slice of validation errors for any invalid values
+// Returns a slice of validation results for any invalid or implausible values; each result
+// has a severity, and only errors make the data unusable
 func (iv *InputValidator) ValidateNutritionalData(data models.NutritionalData) []models.ValidationError {
 	var errors []models.ValidationError
 
@@ -35,20 +53,22 @@ func (iv *InputValidator) ValidateNutritionalData(data models.NutritionalData) [
 	energy := float64(data.Energy)
 	if energy < iv.validationRules.EnergyMin {
 		errors = append(errors, models.ValidationError{
-			Field:   "energy",
-			Value:   energy,
-			Message: fmt.Sprintf("Energy cannot be less than %.1f kJ per 100g", iv.validationRules.EnergyMin),
-			Min:     &iv.validationRules.EnergyMin,
-			Max:     &iv.validationRules.EnergyMax,
+			Field:    "energy",
+			Value:    energy,
+			Message:  fmt.Sprintf("Energy cannot be less than %.1f kJ per 100g", iv.validationRules.EnergyMin),
+			Min:      &iv.validationRules.EnergyMin,
+			Max:      &iv.validationRules.EnergyMax,
+			Severity: models.SeverityError,
 		})
 	}
 	if energy > iv.validationRules.EnergyMax {
 		errors = append(errors, models.ValidationError{
-			Field:   "energy",
-			Value:   energy,
-			Message: fmt.Sprintf("Energy cannot exceed %.1f kJ per 100g", iv.validationRules.EnergyMax),
-			Min:     &iv.validationRules.EnergyMin,
-			Max:     &iv.validationRules.EnergyMax,
+			Field:    "energy",
+			Value:    energy,
+			Message:  fmt.Sprintf("Energy cannot exceed %.1f kJ per 100g", iv.validationRules.EnergyMax),
+			Min:      &iv.validationRules.EnergyMin,
+			Max:      &iv.validationRules.EnergyMax,
+			Severity: models.SeverityError,
 		})
 	}
 
@@ -56,20 +76,22 @@ func (iv *InputValidator) ValidateNutritionalData(data models.NutritionalData) [
 	sugars := float64(data.Sugars)
 	if sugars < iv.validationRules.SugarsMin {
 		errors = append(errors, models.ValidationError{
-			Field:   "sugars",
-			Value:   sugars,
-			Message: fmt.Sprintf("Sugar content cannot be less than %.1f g per 100g", iv.validationRules.SugarsMin),
-			Min:     &iv.validationRules.SugarsMin,
-			Max:     &iv.validationRules.SugarsMax,
+			Field:    "sugars",
+			Value:    sugars,
+			Message:  fmt.Sprintf("Sugar content cannot be less than %.1f g per 100g", iv.validationRules.SugarsMin),
+			Min:      &iv.validationRules.SugarsMin,
+			Max:      &iv.validationRules.SugarsMax,
+			Severity: models.SeverityError,
 		})
 	}
 	if sugars > iv.validationRules.SugarsMax {
 		errors = append(errors, models.ValidationError{
-			Field:   "sugars",
-			Value:   sugars,
-			Message: fmt.Sprintf("Sugar content cannot exceed %.1f g per 100g", iv.validationRules.SugarsMax),
-			Min:     &iv.validationRules.SugarsMin,
-			Max:     &iv.validationRules.SugarsMax,
+			Field:    "sugars",
+			Value:    sugars,
+			Message:  fmt.Sprintf("Sugar content cannot exceed %.1f g per 100g", iv.validationRules.SugarsMax),
+			Min:      &iv.validationRules.SugarsMin,
+			Max:      &iv.validationRules.SugarsMax,
+			Severity: models.SeverityError,
 		})
 	}
 
@@ -77,20 +99,133 @@ func (iv *InputValidator) ValidateNutritionalData(data models.NutritionalData) [
 	satFat := float64(data.SaturatedFattyAcids)
 	if satFat < iv.validationRules.SaturatedFatMin {
 		errors = append(errors, models.ValidationError{
-			Field:   "saturated_fatty_acids",
-			Value:   satFat,
-			Message: fmt.Sprintf("Saturated fat content cannot be less than %.1f g per 100g", iv.validationRules.SaturatedFatMin),
-			Min:     &iv.validationRules.SaturatedFatMin,
-			Max:     &iv.validationRules.SaturatedFatMax,
+			Field:    "saturated_fatty_acids",
+			Value:    satFat,
+			Message:  fmt.Sprintf("Saturated fat content cannot be less than %.1f g per 100g", iv.validationRules.SaturatedFatMin),
+			Min:      &iv.validationRules.SaturatedFatMin,
+			Max:      &iv.validationRules.SaturatedFatMax,
+			Severity: models.SeverityError,
 		})
 	}
 	if satFat > iv.validationRules.SaturatedFatMax {
 		errors = append(errors, models.ValidationError{
-			Field:   "saturated_fatty_acids",
-			Value:   satFat,
-			Message: fmt.Sprintf("Saturated fat content cannot exceed %.1f g per 100g", iv.validationRules.SaturatedFatMax),
-			Min:     &iv.validationRules.SaturatedFatMin,
-			Max:     &iv.validationRules.SaturatedFatMax,
+			Field:    "saturated_fatty_acids",
+			Value:    satFat,
+			Message:  fmt.Sprintf("Saturated fat content cannot exceed %.1f g per 100g", iv.validationRules.SaturatedFatMax),
+			Min:      &iv.validationRules.SaturatedFatMin,
+			Max:      &iv.validationRules.SaturatedFatMax,
+			Severity: models.SeverityError,
+		})
+	}
+
+	// Validate Total Fat (g per 100g)
+	totalFat := float64(data.TotalFat)
+	if totalFat < iv.validationRules.TotalFatMin {
+		errors = append(errors, models.ValidationError{
+			Field:    "total_fat",
+			Value:    totalFat,
+			Message:  fmt.Sprintf("Total fat content cannot be less than %.1f g per 100g", iv.validationRules.TotalFatMin),
+			Min:      &iv.validationRules.TotalFatMin,
+			Max:      &iv.validationRules.TotalFatMax,
+			Severity: models.SeverityError,
+		})
+	}
+	if totalFat > iv.validationRules.TotalFatMax {
+		errors = append(errors, models.ValidationError{
+			Field:    "total_fat",
+			Value:    totalFat,
+			Message:  fmt.Sprintf("Total fat content cannot exceed %.1f g per 100g", iv.validationRules.TotalFatMax),
+			Min:      &iv.validationRules.TotalFatMin,
+			Max:      &iv.validationRules.TotalFatMax,
+			Severity: models.SeverityError,
+		})
+	}
+
+	// Saturated fat is part of total fat, so it can never exceed it (when total fat is declared)
+	if totalFat > 0 && satFat > totalFat {
+		errors = append(errors, models.ValidationError{
+			Field:    "saturated_fatty_acids",
+			Value:    satFat,
+			Message:  fmt.Sprintf("Saturated fat (%.1f g) cannot exceed total fat (%.1f g)", satFat, totalFat),
+			Max:      &totalFat,
+			Severity: models.SeverityError,
+			Suggestions: []string{
+				"Check whether saturated fat and total fat were swapped",
+				"Enter the \"of which saturates\" line of the label as saturated fat",
+			},
+		})
+	}
+
+	// Validate Trans Fat (g per 100g)
+	transFat := float64(data.TransFat)
+	if transFat < iv.validationRules.TransFatMin {
+		errors = append(errors, models.ValidationError{
+			Field:    "trans_fat",
+			Value:    transFat,
+			Message:  fmt.Sprintf("Trans fat content cannot be less than %.1f g per 100g", iv.validationRules.TransFatMin),
+			Min:      &iv.validationRules.TransFatMin,
+			Max:      &iv.validationRules.TransFatMax,
+			Severity: models.SeverityError,
+		})
+	}
+	if transFat > iv.validationRules.TransFatMax {
+		errors = append(errors, models.ValidationError{
+			Field:    "trans_fat",
+			Value:    transFat,
+			Message:  fmt.Sprintf("Trans fat content cannot exceed %.1f g per 100g", iv.validationRules.TransFatMax),
+			Min:      &iv.validationRules.TransFatMin,
+			Max:      &iv.validationRules.TransFatMax,
+			Severity: models.SeverityError,
+		})
+	}
+
+	// Trans fat is part of total fat as well
+	if totalFat > 0 && transFat > totalFat {
+		errors = append(errors, models.ValidationError{
+			Field:       "trans_fat",
+			Value:       transFat,
+			Message:     fmt.Sprintf("Trans fat (%.1f g) cannot exceed total fat (%.1f g)", transFat, totalFat),
+			Max:         &totalFat,
+			Severity:    models.SeverityError,
+			Suggestions: []string{"Check whether trans fat and total fat were swapped"},
+		})
+	}
+
+	// Validate Carbohydrate (g per 100g)
+	carbohydrate := float64(data.Carbohydrate)
+	if carbohydrate < iv.validationRules.CarbohydrateMin {
+		errors = append(errors, models.ValidationError{
+			Field:    "carbohydrate",
+			Value:    carbohydrate,
+			Message:  fmt.Sprintf("Carbohydrate content cannot be less than %.1f g per 100g", iv.validationRules.CarbohydrateMin),
+			Min:      &iv.validationRules.CarbohydrateMin,
+			Max:      &iv.validationRules.CarbohydrateMax,
+			Severity: models.SeverityError,
+		})
+	}
+	if carbohydrate > iv.validationRules.CarbohydrateMax {
+		errors = append(errors, models.ValidationError{
+			Field:    "carbohydrate",
+			Value:    carbohydrate,
+			Message:  fmt.Sprintf("Carbohydrate content cannot exceed %.1f g per 100g", iv.validationRules.CarbohydrateMax),
+			Min:      &iv.validationRules.CarbohydrateMin,
+			Max:      &iv.validationRules.CarbohydrateMax,
+			Severity: models.SeverityError,
+		})
+	}
+
+	// Sugars are part of the carbohydrates (when carbohydrate is declared)
+	if carbohydrate > 0 && sugars > carbohydrate {
+		errors = append(errors, models.ValidationError{
+			Field:    "sugars",
+			Value:    sugars,
+			Message:  fmt.Sprintf("Sugars (%.1f g) cannot exceed carbohydrate (%.1f g)", sugars, carbohydrate),
+			Max:      &carbohydrate,
+			Severity: models.SeverityError,
+			Suggestions: []string{
+				"Check whether sugars and carbohydrate were swapped",
+				"Enter the \"of which sugars\" line of the label as sugars",
+			},
 		})
 	}
 
@@ -98,41 +233,83 @@ func (iv *InputValidator) ValidateNutritionalData(data models.NutritionalData) [
 	sodium := float64(data.Sodium)
 	if sodium < iv.validationRules.SodiumMin {
 		errors = append(errors, models.ValidationError{
-			Field:   "sodium",
-			Value:   sodium,
-			Message: fmt.Sprintf("Sodium content cannot be less than %.1f mg per 100g", iv.validationRules.SodiumMin),
-			Min:     &iv.validationRules.SodiumMin,
-			Max:     &iv.validationRules.SodiumMax,
+			Field:    "sodium",
+			Value:    sodium,
+			Message:  fmt.Sprintf("Sodium content cannot be less than %.1f mg per 100g", iv.validationRules.SodiumMin),
+			Min:      &iv.validationRules.SodiumMin,
+			Max:      &iv.validationRules.SodiumMax,
+			Severity: models.SeverityError,
 		})
 	}
 	if sodium > iv.validationRules.SodiumMax {
 		errors = append(errors, models.ValidationError{
-			Field:   "sodium",
-			Value:   sodium,
-			Message: fmt.Sprintf("Sodium content cannot exceed %.1f mg per 100g", iv.validationRules.SodiumMax),
-			Min:     &iv.validationRules.SodiumMin,
-			Max:     &iv.validationRules.SodiumMax,
+			Field:    "sodium",
+			Value:    sodium,
+			Message:  fmt.Sprintf("Sodium content cannot exceed %.1f mg per 100g", iv.validationRules.SodiumMax),
+			Min:      &iv.validationRules.SodiumMin,
+			Max:      &iv.validationRules.SodiumMax,
+			Severity: models.SeverityError,
+		})
+	}
+	if warning := iv.plausibilityWarning("sodium", sodium, iv.validationRules.SodiumWarning, iv.validationRules.SodiumMax,
+		fmt.Sprintf("Sodium content of %.1f mg per 100g is unusually high", sodium)); warning != nil {
+		errors = append(errors, *warning)
+	}
+
+	// Validate Salt (g per 100g), the alternative declaration of sodium
+	salt := float64(data.Salt)
+	if salt < iv.validationRules.SaltMin {
+		errors = append(errors, models.ValidationError{
+			Field:    "salt",
+			Value:    salt,
+			Message:  fmt.Sprintf("Salt content cannot be less than %.1f g per 100g", iv.validationRules.SaltMin),
+			Min:      &iv.validationRules.SaltMin,
+			Max:      &iv.validationRules.SaltMax,
+			Severity: models.SeverityError,
 		})
 	}
+	if salt > iv.validationRules.SaltMax {
+		errors = append(errors, models.ValidationError{
+			Field:    "salt",
+			Value:    salt,
+			Message:  fmt.Sprintf("Salt content cannot exceed %.1f g per 100g", iv.validationRules.SaltMax),
+			Min:      &iv.validationRules.SaltMin,
+			Max:      &iv.validationRules.SaltMax,
+			Severity: models.SeverityError,
+		})
+	}
+	if warning := iv.plausibilityWarning("salt", salt, iv.validationRules.SaltWarning, iv.validationRules.SaltMax,
+		fmt.Sprintf("Salt content of %.1f g per 100g is unusually high", salt)); warning != nil {
+		errors = append(errors, *warning)
+	}
+
+	// When both salt and sodium are given they must describe the same amount
+	if salt > 0 && sodium > 0 {
+		if err := iv.validateSaltSodiumAgreement(data.Salt, data.Sodium); err != nil {
+			errors = append(errors, *err)
+		}
+	}
 
 	// Validate Fruits/Vegetables/Nuts percentage
 	fruits := float64(data.Fruits)
 	if fruits < iv.validationRules.FruitsMin {
 		errors = append(errors, models.ValidationError{
-			Field:   "fruits",
-			Value:   fruits,
-			Message: fmt.Sprintf("Fruits/vegetables/nuts percentage cannot be less than %.1f%%", iv.validationRules.FruitsMin),
-			Min:     &iv.validationRules.FruitsMin,
-			Max:     &iv.validationRules.FruitsMax,
+			Field:    "fruits",
+			Value:    fruits,
+			Message:  fmt.Sprintf("Fruits/vegetables/nuts percentage cannot be less than %.1f%%", iv.validationRules.FruitsMin),
+			Min:      &iv.validationRules.FruitsMin,
+			Max:      &iv.validationRules.FruitsMax,
+			Severity: models.SeverityError,
 		})
 	}
 	if fruits > iv.validationRules.FruitsMax {
 		errors = append(errors, models.ValidationError{
-			Field:   "fruits",
-			Value:   fruits,
-			Message: fmt.Sprintf("Fruits/vegetables/nuts percentage cannot exceed %.1f%%", iv.validationRules.FruitsMax),
-			Min:     &iv.validationRules.FruitsMin,
-			Max:     &iv.validationRules.FruitsMax,
+			Field:    "fruits",
+			Value:    fruits,
+			Message:  fmt.Sprintf("Fruits/vegetables/nuts percentage cannot exceed %.1f%%", iv.validationRules.FruitsMax),
+			Min:      &iv.validationRules.FruitsMin,
+			Max:      &iv.validationRules.FruitsMax,
+			Severity: models.SeverityError,
 		})
 	}
 
@@ -140,103 +317,274 @@ func (iv *InputValidator) ValidateNutritionalData(data models.NutritionalData) [
 	fiber := float64(data.Fibre)
 	if fiber < iv.validationRules.FibreMin {
 		errors = append(errors, models.ValidationError{
-			Field:   "fibre",
-			Value:   fiber,
-			Message: fmt.Sprintf("Fiber content cannot be less than %.1f g per 100g", iv.validationRules.FibreMin),
-			Min:     &iv.validationRules.FibreMin,
-			Max:     &iv.validationRules.FibreMax,
+			Field:    "fibre",
+			Value:    fiber,
+			Message:  fmt.Sprintf("Fiber content cannot be less than %.1f g per 100g", iv.validationRules.FibreMin),
+			Min:      &iv.validationRules.FibreMin,
+			Max:      &iv.validationRules.FibreMax,
+			Severity: models.SeverityError,
 		})
 	}
 	if fiber > iv.validationRules.FibreMax {
 		errors = append(errors, models.ValidationError{
-			Field:   "fibre",
-			Value:   fiber,
-			Message: fmt.Sprintf("Fiber content cannot exceed %.1f g per 100g", iv.validationRules.FibreMax),
-			Min:     &iv.validationRules.FibreMin,
-			Max:     &iv.validationRules.FibreMax,
+			Field:    "fibre",
+			Value:    fiber,
+			Message:  fmt.Sprintf("Fiber content cannot exceed %.1f g per 100g", iv.validationRules.FibreMax),
+			Min:      &iv.validationRules.FibreMin,
+			Max:      &iv.validationRules.FibreMax,
+			Severity: models.SeverityError,
 		})
 	}
+	if warning := iv.plausibilityWarning("fibre", fiber, iv.validationRules.FibreWarning, iv.validationRules.FibreMax,
+		fmt.Sprintf("Fiber content of %.1f g per 100g is unusually high", fiber)); warning != nil {
+		errors = append(errors, *warning)
+	}
 
 	// Validate Protein (g per 100g)
 	protein := float64(data.Protein)
 	if protein < iv.validationRules.ProteinMin {
 		errors = append(errors, models.ValidationError{
-			Field:   "protein",
-			Value:   protein,
-			Message: fmt.Sprintf("Protein content cannot be less than %.1f g per 100g", iv.validationRules.ProteinMin),
-			Min:     &iv.validationRules.ProteinMin,
-			Max:     &iv.validationRules.ProteinMax,
+			Field:    "protein",
+			Value:    protein,
+			Message:  fmt.Sprintf("Protein content cannot be less than %.1f g per 100g", iv.validationRules.ProteinMin),
+			Min:      &iv.validationRules.ProteinMin,
+			Max:      &iv.validationRules.ProteinMax,
+			Severity: models.SeverityError,
 		})
 	}
 	if protein > iv.validationRules.ProteinMax {
 		errors = append(errors, models.ValidationError{
-			Field:   "protein",
-			Value:   protein,
-			Message: fmt.Sprintf("Protein content cannot exceed %.1f g per 100g", iv.validationRules.ProteinMax),
-			Min:     &iv.validationRules.ProteinMin,
-			Max:     &iv.validationRules.ProteinMax,
+			Field:    "protein",
+			Value:    protein,
+			Message:  fmt.Sprintf("Protein content cannot exceed %.1f g per 100g", iv.validationRules.ProteinMax),
+			Min:      &iv.validationRules.ProteinMin,
+			Max:      &iv.validationRules.ProteinMax,
+			Severity: models.SeverityError,
 		})
 	}
 
+	// Declared energy must match the energy of the macronutrients, once any of them is declared
+	if energy > 0 && AtwaterEnergy(data) > 0 {
+		if warning := iv.validateEnergyAgreement(data); warning != nil {
+			errors = append(errors, *warning)
+		}
+	}
+
+	// Only nutrients that can be estimated may be marked unknown
+	for _, nutrient := range data.Unknown {
+		if _, found := imputableNutrient(nutrient); !found {
+			errors = append(errors, models.ValidationError{
+				Field:    "unknown",
+				Message:  fmt.Sprintf("%q cannot be marked unknown", nutrient),
+				Severity: models.SeverityError,
+			})
+		}
+	}
+
 	return errors
 }
 
+// plausibilityWarning warns about a value above the warning threshold that is still within the maximum
+// Such values occur in a few products but are more often typing or unit mistakes; a threshold of 0
+// disables the warning
+func (iv *InputValidator) plausibilityWarning(field string, value, threshold, max float64, message string) *models.ValidationError {
+	if threshold <= 0 || value <= threshold || value > max {
+		return nil
+	}
+	return &models.ValidationError{
+		Field:    field,
+		Value:    value,
+		Message:  fmt.Sprintf("%s (above %.1f); check the value and its unit", message, threshold),
+		Max:      &threshold,
+		Severity: models.SeverityWarning,
+	}
+}
+
+// CheckNutritionalData validates the nutritional data and sorts the results into errors and warnings
+// Data with only warnings can still be scored
+func (iv *InputValidator) CheckNutritionalData(data models.NutritionalData) models.ErrorCollection {
+	collection := models.ErrorCollection{Operation: "nutritional data validation"}
+	for _, result := range iv.ValidateNutritionalData(data) {
+		collection.AddValidationError(result)
+	}
+	return collection
+}
+
+// CheckNutritionalDataFor validates the nutritional data with the profile of a score type
+// and sorts the results into errors and warnings
+func (iv *InputValidator) CheckNutritionalDataFor(data models.NutritionalData, scoreType models.ScoreType) models.ErrorCollection {
+	profileValidator := &InputValidator{validationRules: iv.RulesFor("", scoreType)}
+	return profileValidator.CheckNutritionalData(data)
+}
+
+// CheckFoodData validates the nutritional data of a food with the profile of its category,
+// or of the solid or liquid score type, and sorts the results into errors and warnings
+func (iv *InputValidator) CheckFoodData(food models.Food) models.ErrorCollection {
+	profileValidator := &InputValidator{validationRules: iv.RulesFor(food.Category, foodScoreType(food))}
+	return profileValidator.CheckNutritionalData(food.NutritionalData)
+}
+
+// saltRoundingMilligrams is the sodium equivalent of the 0.01g precision salt is labelled with
+const saltRoundingMilligrams = 0.01 * 1000 / models.SaltToSodiumFactor
+
+// validateSaltSodiumAgreement checks that declared salt and sodium agree within the tolerance
+// Salt is converted with the 2.5 factor; label rounding of 0.01g salt is always allowed
+func (iv *InputValidator) validateSaltSodiumAgreement(salt models.SaltGram, sodium models.SodiumMilligram) *models.ValidationError {
+	expected := float64(salt.ToSodium())
+	allowed := math.Max(expected*iv.validationRules.SaltSodiumTolerance, saltRoundingMilligrams)
+	if math.Abs(float64(sodium)-expected) <= allowed {
+		return nil
+	}
+
+	return &models.ValidationError{
+		Field: "salt",
+		Value: float64(salt),
+		Message: fmt.Sprintf("Salt (%.2f g) and sodium (%.0f mg) disagree: %.2f g salt is %.0f mg sodium",
+			float64(salt), float64(sodium), float64(salt), expected),
+		Severity: models.SeverityError,
+	}
+}
+
+// Energy conversion factors of Annex XIV of EU Regulation 1169/2011 not defined elsewhere
+const (
+	kilojoulesPerGramCarbohydrate = 17
+	kilojoulesPerGramFibre        = 8
+)
+
+// atwaterRoundingKilojoules is the energy of the 0.5g of fat, carbohydrate and protein a label may round to 0
+const atwaterRoundingKilojoules = 0.5 * (kilojoulesPerGramFat + kilojoulesPerGramCarbohydrate + kilojoulesPerGramProtein)
+
+// AtwaterEnergy calculates the energy in kJ from the macronutrients with the EU conversion factors
+// Saturated and trans fat stand in for total fat when it is not declared
+func AtwaterEnergy(data models.NutritionalData) float64 {
+	fat := math.Max(float64(data.TotalFat), float64(data.SaturatedFattyAcids)+float64(data.TransFat))
+	return fat*kilojoulesPerGramFat +
+		float64(data.Carbohydrate)*kilojoulesPerGramCarbohydrate +
+		float64(data.Protein)*kilojoulesPerGramProtein +
+		float64(data.Fibre)*kilojoulesPerGramFibre
+}
+
+// validateEnergyAgreement checks that the declared energy agrees with the Atwater calculation
+// A mismatch is a warning, as alcohol, polyols and organic acids also provide energy; it usually
+// means a typo or energy entered in kcal
+func (iv *InputValidator) validateEnergyAgreement(data models.NutritionalData) *models.ValidationError {
+	if iv.validationRules.EnergyTolerance <= 0 {
+		return nil
+	}
+
+	declared := float64(data.Energy)
+	expected := AtwaterEnergy(data)
+	// Without carbohydrate the calculation only covers part of the energy, so only a
+	// declared energy below it is suspicious (e.g. an oil with its energy entered in kcal)
+	if data.Carbohydrate <= 0 && declared > expected {
+		return nil
+	}
+	allowed := math.Max(expected*iv.validationRules.EnergyTolerance, atwaterRoundingKilojoules)
+	if math.Abs(declared-expected) <= allowed {
+		return nil
+	}
+
+	suggestions := []string{"Check the fat, carbohydrate, protein and fibre values for typos"}
+	if math.Abs(declared*kilojoulesPerKilocalorie-expected) <= allowed {
+		suggestions = append([]string{fmt.Sprintf("The energy looks like kcal; enter it in kJ (%.0f kJ)",
+			declared*kilojoulesPerKilocalorie)}, suggestions...)
+	}
+	return &models.ValidationError{
+		Field: "energy",
+		Value: declared,
+		Message: fmt.Sprintf("Energy (%.0f kJ) disagrees with the %.0f kJ calculated from fat, carbohydrate, protein and fibre",
+			declared, expected),
+		Severity:    models.SeverityWarning,
+		Suggestions: suggestions,
+	}
+}
+
 // ValidateFood validates a complete food item including name, category, and nutritional data
+// Liquids are validated as beverages and everything else as food; use ValidateFoodFor when
+// the score type is known
 func (iv *InputValidator) ValidateFood(food models.Food) []models.ValidationError {
+	return iv.ValidateFoodFor(food, foodScoreType(food))
+}
+
+// foodScoreType returns the score type whose profile validates a food of unknown score type
+func foodScoreType(food models.Food) models.ScoreType {
+	if food.IsLiquid() {
+		return models.BeverageType
+	}
+	return models.FoodType
+}
+
+// ValidateFoodFor validates a complete food item scored as the given score type
+func (iv *InputValidator) ValidateFoodFor(food models.Food, scoreType models.ScoreType) []models.ValidationError {
 	var errors []models.ValidationError
 
 	// Validate food name
 	if strings.TrimSpace(food.Name) == "" {
 		errors = append(errors, models.ValidationError{
-			Field:   "name",
-			Value:   0, // Not applicable for string fields
-			Message: "Food name is required and cannot be empty",
+			Field:    "name",
+			Value:    0, // Not applicable for string fields
+			Message:  "Food name is required and cannot be empty",
+			Severity: models.SeverityError,
 		})
 	}
 
 	if len(food.Name) > 200 {
 		errors = append(errors, models.ValidationError{
-			Field:   "name",
-			Value:   float64(len(food.Name)),
-			Message: "Food name must be less than 200 characters",
-			Max:     func() *float64 { v := 200.0; return &v }(),
+			Field:    "name",
+			Value:    float64(len(food.Name)),
+			Message:  "Food name must be less than 200 characters",
+			Max:      func() *float64 { v := 200.0; return &v }(),
+			Severity: models.SeverityError,
 		})
 	}
 
 	// Validate food category
 	if strings.TrimSpace(food.Category) == "" {
 		errors = append(errors, models.ValidationError{
-			Field:   "category",
-			Value:   0,
-			Message: "Food category is required",
+			Field:    "category",
+			Value:    0,
+			Message:  "Food category is required",
+			Severity: models.SeverityError,
 		})
 	}
 
 	// Validate food ID format (if provided)
 	if food.ID != "" && !iv.isValidFoodID(food.ID) {
 		errors = append(errors, models.ValidationError{
-			Field:   "id",
-			Value:   0,
-			Message: "Food ID must contain only alphanumeric characters, hyphens, and underscores",
+			Field:    "id",
+			Value:    0,
+			Message:  "Food ID must contain only alphanumeric characters, hyphens, and underscores",
+			Severity: models.SeverityError,
 		})
 	}
 
-	// Validate nutritional data
-	nutritionalErrors := iv.ValidateNutritionalData(food.NutritionalData)
+	// Validate nutritional data with the profile of the food's category or score type
+	profileValidator := &InputValidator{validationRules: iv.RulesFor(food.Category, scoreType)}
+	nutritionalErrors := profileValidator.ValidateNutritionalData(food.NutritionalData)
 	errors = append(errors, nutritionalErrors...)
 
 	return errors
 }
 
+// RulesFor returns the validation rules of a food category scored as the given score type
+// The category profile is used when there is one, otherwise the profile of the score type;
+// validators created with custom rules use them for everything
+func (iv *InputValidator) RulesFor(category string, scoreType models.ScoreType) models.NutritionalDataValidation {
+	if iv.profiles == nil {
+		return iv.validationRules
+	}
+	return iv.profiles.RulesFor(category, scoreType)
+}
+
 // ValidateScoreType checks if the provided score type is valid
 func (iv *InputValidator) ValidateScoreType(scoreType models.ScoreType) error {
 	switch scoreType {
-	case models.FoodType, models.BeverageType, models.WaterType, models.CheeseType:
+	case models.FoodType, models.BeverageType, models.WaterType, models.CheeseType, models.FatsOilsType:
 		return nil
 	default:
 		return models.NewValidationError("score_type", 
-			fmt.Sprintf("Invalid score type: %d. Must be 0 (Food), 1 (Beverage), 2 (Water), or 3 (Cheese)", int(scoreType)),
-			"Use 0 for Food, 1 for Beverage, 2 for Water, or 3 for Cheese")
+			fmt.Sprintf("Invalid score type: %d. Must be 0 (Food), 1 (Beverage), 2 (Water), 3 (Cheese), or 4 (Fats/Oils)", int(scoreType)),
+			"Use 0 for Food, 1 for Beverage, 2 for Water, 3 for Cheese, or 4 for Fats/Oils")
 	}
 }
 
@@ -321,8 +669,24 @@ func (iv *InputValidator) GetValidationRules() models.NutritionalDataValidation
 }
 
 // SetValidationRules updates the validation rules
+// The rules also become the default profile for foods without a category profile
 func (iv *InputValidator) SetValidationRules(rules models.NutritionalDataValidation) {
 	iv.validationRules = rules
+	if iv.profiles != nil {
+		iv.profiles.Default = rules
+	}
+}
+
+// GetValidationProfiles returns the per-category validation profiles, if any
+func (iv *InputValidator) GetValidationProfiles() *models.ValidationProfiles {
+	return iv.profiles
+}
+
+// SetValidationProfiles replaces the per-category validation profiles and the default rules
+func (iv *InputValidator) SetValidationProfiles(profiles models.ValidationProfiles) {
+	profiles = profiles.WithCategoryKeys()
+	iv.validationRules = profiles.Default
+	iv.profiles = &profiles
 }
 
 // ValidateNutritionalRange checks if a single nutritional value is within range
@@ -330,21 +694,23 @@ func (iv *InputValidator) SetValidationRules(rules models.NutritionalDataValidat
 func (iv *InputValidator) ValidateNutritionalRange(value float64, min, max float64, fieldName string) *models.ValidationError {
 	if value < min {
 		return &models.ValidationError{
-			Field:   fieldName,
-			Value:   value,
-			Message: fmt.Sprintf("%s cannot be less than %.1f", fieldName, min),
-			Min:     &min,
-			Max:     &max,
+			Field:    fieldName,
+			Value:    value,
+			Message:  fmt.Sprintf("%s cannot be less than %.1f", fieldName, min),
+			Min:      &min,
+			Max:      &max,
+			Severity: models.SeverityError,
 		}
 	}
 	
 	if value > max {
 		return &models.ValidationError{
-			Field:   fieldName,
-			Value:   value,
-			Message: fmt.Sprintf("%s cannot exceed %.1f", fieldName, max),
-			Min:     &min,
-			Max:     &max,
+			Field:    fieldName,
+			Value:    value,
+			Message:  fmt.Sprintf("%s cannot exceed %.1f", fieldName, max),
+			Min:      &min,
+			Max:      &max,
+			Severity: models.SeverityError,
 		}
 	}
 	
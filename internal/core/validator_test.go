@@ -0,0 +1,154 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// TestInputValidator_SaltAndSodium tests salt range checks and salt/sodium agreement
+func TestInputValidator_SaltAndSodium(t *testing.T) {
+	validator := NewInputValidator()
+
+	tests := []struct {
+		name    string
+		salt    models.SaltGram
+		sodium  models.SodiumMilligram
+		wantErr bool
+	}{
+		{"Salt Only", 1.25, 0, false},
+		{"Sodium Only", 0, 500, false},
+		{"Both Agree", 1.25, 500, false},
+		{"Both Agree Within Rounding", 0.01, 5, false},
+		{"Both Agree Within Tolerance", 1.0, 410, false},
+		{"Both Disagree", 1.25, 200, true},
+		{"Salt Given As Sodium", 500, 500, true},
+		{"Salt Too High", 30, 0, true},
+		{"Salt Negative", -1, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := models.NutritionalData{Salt: tt.salt, Sodium: tt.sodium}
+			errors := validator.ValidateNutritionalData(data)
+			if (len(errors) > 0) != tt.wantErr {
+				t.Errorf("ValidateNutritionalData() errors = %v, wantErr %v", errors, tt.wantErr)
+			}
+			for _, err := range errors {
+				if err.Field != "salt" {
+					t.Errorf("Error field = %s, want salt", err.Field)
+				}
+			}
+		})
+	}
+}
+
+// TestInputValidator_Severity tests that implausible values are warnings and impossible values errors
+func TestInputValidator_Severity(t *testing.T) {
+	validator := NewInputValidator()
+
+	tests := []struct {
+		name         string
+		data         models.NutritionalData
+		wantErrors   int
+		wantWarnings int
+		wantField    string
+	}{
+		{"Plausible", models.NutritionalData{Energy: 1000, Sodium: 400, Fibre: 5}, 0, 0, ""},
+		{"Sodium At Warning Threshold", models.NutritionalData{Sodium: 5000}, 0, 0, ""},
+		{"Sodium Implausible", models.NutritionalData{Sodium: 6000}, 0, 1, "sodium"},
+		{"Salt Implausible", models.NutritionalData{Salt: 15}, 0, 1, "salt"},
+		{"Fibre Implausible", models.NutritionalData{Fibre: 40}, 0, 1, "fibre"},
+		{"Fibre Out Of Range", models.NutritionalData{Fibre: 60}, 1, 0, "fibre"},
+		{"Sodium Out Of Range", models.NutritionalData{Sodium: 12000}, 1, 0, "sodium"},
+		{"Error And Warning", models.NutritionalData{Energy: 5000, Fibre: 40}, 1, 1, "energy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collection := validator.CheckNutritionalData(tt.data)
+			if collection.ErrorCount != tt.wantErrors || collection.WarningCount != tt.wantWarnings {
+				t.Fatalf("CheckNutritionalData() = %d errors, %d warnings, want %d and %d: %v",
+					collection.ErrorCount, collection.WarningCount, tt.wantErrors, tt.wantWarnings, collection.Errors)
+			}
+			if tt.wantField != "" && collection.Errors[0].Field != tt.wantField {
+				t.Errorf("First result field = %s, want %s", collection.Errors[0].Field, tt.wantField)
+			}
+			if tt.wantErrors > 0 && collection.Summary == "" {
+				t.Error("Collection with errors should have a summary")
+			}
+			for _, warning := range collection.GetWarnings() {
+				if warning.Code != "VALIDATION_WARNING" {
+					t.Errorf("Warning code = %s, want VALIDATION_WARNING", warning.Code)
+				}
+			}
+		})
+	}
+
+	// A threshold of 0 disables the warning
+	rules := models.DefaultValidationRules()
+	rules.FibreWarning = 0
+	if collection := NewInputValidatorWithRules(rules).CheckNutritionalData(models.NutritionalData{Fibre: 40}); collection.HasWarnings() {
+		t.Errorf("CheckNutritionalData() with the fibre warning disabled = %v, want no warnings", collection.Errors)
+	}
+}
+
+// TestInputValidator_CrossField tests the consistency checks between nutrients
+func TestInputValidator_CrossField(t *testing.T) {
+	validator := NewInputValidator()
+
+	// Wholemeal bread: 3 fat*37 + 41 carbohydrate*17 + 10 protein*17 + 7 fibre*8 = 1034 kJ
+	bread := models.NutritionalData{Energy: 1050, TotalFat: 3, SaturatedFattyAcids: 0.6, Carbohydrate: 41,
+		Sugars: 3, Protein: 10, Fibre: 7}
+
+	tests := []struct {
+		name           string
+		change         func(d *models.NutritionalData)
+		wantField      string
+		wantSeverity   models.Severity
+		wantSuggestion string
+	}{
+		{"Consistent", func(d *models.NutritionalData) {}, "", "", ""},
+		{"Carbohydrate Not Declared", func(d *models.NutritionalData) { d.Carbohydrate = 0; d.Energy = 3000 }, "", "", ""},
+		{"Sugars Exceed Carbohydrate", func(d *models.NutritionalData) { d.Sugars = 45 }, "sugars", models.SeverityError, "swapped"},
+		{"Saturated Fat Exceeds Total Fat", func(d *models.NutritionalData) { d.SaturatedFattyAcids = 6 }, "saturated_fatty_acids", models.SeverityError, "swapped"},
+		{"Carbohydrate Negative", func(d *models.NutritionalData) { d.Carbohydrate = -5 }, "carbohydrate", models.SeverityError, ""},
+		{"Energy Typo", func(d *models.NutritionalData) { d.Energy = 105 }, "energy", models.SeverityWarning, "typos"},
+		{"Energy In Kilocalories", func(d *models.NutritionalData) { d.Energy = 251 }, "energy", models.SeverityWarning, "kcal"},
+		{"Energy Within Rounding", func(d *models.NutritionalData) {
+			*d = models.NutritionalData{Energy: 20, Carbohydrate: 0.4, Protein: 0.4}
+		}, "", "", ""},
+		// Sunflower oil: 100 fat*37 = 3700 kJ, the label's 884 kcal were entered as kJ
+		{"Fat Only In Kilocalories", func(d *models.NutritionalData) {
+			*d = models.NutritionalData{Energy: 884, TotalFat: 100, SaturatedFattyAcids: 11}
+		}, "energy", models.SeverityWarning, "kcal"},
+		{"Fat Only In Kilojoules", func(d *models.NutritionalData) {
+			*d = models.NutritionalData{Energy: 3700, TotalFat: 100, SaturatedFattyAcids: 11}
+		}, "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := bread
+			tt.change(&data)
+			results := validator.ValidateNutritionalData(data)
+			if tt.wantField == "" {
+				if len(results) > 0 {
+					t.Errorf("ValidateNutritionalData() = %v, want no results", results)
+				}
+				return
+			}
+			if len(results) != 1 {
+				t.Fatalf("ValidateNutritionalData() = %v, want one result", results)
+			}
+			result := results[0]
+			if result.Field != tt.wantField || result.Severity != tt.wantSeverity {
+				t.Errorf("Result = %s %s, want %s %s", result.Field, result.Severity, tt.wantField, tt.wantSeverity)
+			}
+			if tt.wantSuggestion != "" && (len(result.Suggestions) == 0 || !strings.Contains(result.Suggestions[0], tt.wantSuggestion)) {
+				t.Errorf("Suggestions = %v, want the first to mention %q", result.Suggestions, tt.wantSuggestion)
+			}
+		})
+	}
+}
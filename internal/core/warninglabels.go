@@ -0,0 +1,178 @@
+package core
+
+import (
+	"github.com/nutritional-score/pkg/models"
+)
+
+// Energy conversion factors used by the warning-label regulations
+const (
+	kilojoulesPerKilocalorie = 4.184
+	kilocaloriesPerGramSugar = 4
+	kilocaloriesPerGramFat   = 9
+)
+
+// kilocalories converts the declared energy to kcal
+func kilocalories(data models.NutritionalData) float64 {
+	return float64(data.Energy) / kilojoulesPerKilocalorie
+}
+
+// per100Unit returns the unit of per 100g or 100ml amounts
+func per100Unit(unit string, liquid bool) string {
+	if liquid {
+		return unit + " per 100ml"
+	}
+	return unit + " per 100g"
+}
+
+// newWarningSeal records a triggered seal with rounded values
+func newWarningSeal(seal, nutrient string, value, limit float64, unit string) models.WarningSeal {
+	return models.WarningSeal{
+		Seal:     seal,
+		Nutrient: nutrient,
+		Value:    roundDistance(value),
+		Limit:    limit,
+		Unit:     unit,
+	}
+}
+
+// ChileWarningEvaluator applies the Chilean food labelling law (Ley 20.606)
+// Products above the limits carry black "ALTO EN" seals. The evaluator cannot tell
+// whether sugars, sodium or saturated fat were added, so it assumes they were
+type ChileWarningEvaluator struct {
+	validator models.InputValidator
+}
+
+// NewChileWarningEvaluator creates an evaluator with the final (2019) Chilean limits
+func NewChileWarningEvaluator() *ChileWarningEvaluator {
+	return &ChileWarningEvaluator{validator: NewInputValidator()}
+}
+
+// Evaluate returns the "ALTO EN" seals the food must carry
+// Solids and liquids have separate limits; a seal is required above the limit
+func (cwe *ChileWarningEvaluator) Evaluate(food models.Food) (models.WarningLabelResult, error) {
+	data := food.NutritionalData
+	validation := cwe.validator.CheckFoodData(food)
+	if validation.HasErrors() {
+		return models.WarningLabelResult{}, validation
+	}
+	if err := requireKnownValues(data); err != nil {
+		return models.WarningLabelResult{}, err
+	}
+	data = data.ResolveSodium()
+
+	liquid := food.IsLiquid()
+	result := models.WarningLabelResult{
+		Market:     "CL",
+		Regulation: "Ley 20.606 (Decreto 13/2015)",
+		FoodID:     food.ID,
+		Liquid:     liquid,
+	}
+
+	limits := []struct {
+		seal     string
+		nutrient string
+		value    float64
+		solid    float64
+		liquid   float64
+		unit     string
+	}{
+		{"ALTO EN CALORÍAS", "energy", kilocalories(data), 275, 70, "kcal"},
+		{"ALTO EN AZÚCARES", "sugars", float64(data.Sugars), 10, 5, "g"},
+		{"ALTO EN GRASAS SATURADAS", "saturated_fat", float64(data.SaturatedFattyAcids), 4, 3, "g"},
+		{"ALTO EN SODIO", "sodium", float64(data.Sodium), 400, 100, "mg"},
+	}
+	for _, l := range limits {
+		limit := l.solid
+		if liquid {
+			limit = l.liquid
+		}
+		if l.value > limit {
+			result.Seals = append(result.Seals, newWarningSeal(l.seal, l.nutrient, l.value, limit, per100Unit(l.unit, liquid)))
+		}
+	}
+
+	return result, nil
+}
+
+// MexicoWarningEvaluator applies the Mexican labelling standard NOM-051-SCFI/SSA1-2010
+// (2020 amendment) and its black octagon "EXCESO" seals
+// Limits on sugars, saturated fat and trans fat are shares of the total energy; all
+// declared sugars are treated as free sugars
+type MexicoWarningEvaluator struct {
+	validator models.InputValidator
+}
+
+// NewMexicoWarningEvaluator creates an evaluator with the phase 3 (2025) NOM-051 limits
+func NewMexicoWarningEvaluator() *MexicoWarningEvaluator {
+	return &MexicoWarningEvaluator{validator: NewInputValidator()}
+}
+
+// Evaluate returns the "EXCESO" seals the food must carry
+// A seal is required when the value reaches the limit
+func (mwe *MexicoWarningEvaluator) Evaluate(food models.Food) (models.WarningLabelResult, error) {
+	data := food.NutritionalData
+	validation := mwe.validator.CheckFoodData(food)
+	if validation.HasErrors() {
+		return models.WarningLabelResult{}, validation
+	}
+	if err := requireKnownValues(data); err != nil {
+		return models.WarningLabelResult{}, err
+	}
+	data = data.ResolveSodium()
+
+	liquid := food.IsLiquid()
+	result := models.WarningLabelResult{
+		Market:     "MX",
+		Regulation: "NOM-051-SCFI/SSA1-2010",
+		FoodID:     food.ID,
+		Liquid:     liquid,
+	}
+
+	kcal := kilocalories(data)
+	sugarKcal := float64(data.Sugars) * kilocaloriesPerGramSugar
+
+	// Calories: 275 kcal per 100g for solids; 70 kcal or 10 kcal from free sugars per 100ml for liquids
+	switch {
+	case !liquid && kcal >= 275:
+		result.Seals = append(result.Seals, newWarningSeal("EXCESO CALORÍAS", "energy", kcal, 275, per100Unit("kcal", liquid)))
+	case liquid && kcal >= 70:
+		result.Seals = append(result.Seals, newWarningSeal("EXCESO CALORÍAS", "energy", kcal, 70, per100Unit("kcal", liquid)))
+	case liquid && sugarKcal >= 10:
+		result.Seals = append(result.Seals, newWarningSeal("EXCESO CALORÍAS", "energy", sugarKcal, 10, per100Unit("kcal from sugars", liquid)))
+	}
+
+	// Sugars, saturated fat and trans fat as a share of the total energy
+	if kcal > 0 {
+		shares := []struct {
+			seal     string
+			nutrient string
+			kcal     float64
+			limit    float64
+		}{
+			{"EXCESO AZÚCARES", "sugars", sugarKcal, 10},
+			{"EXCESO GRASAS SATURADAS", "saturated_fat", float64(data.SaturatedFattyAcids) * kilocaloriesPerGramFat, 10},
+			{"EXCESO GRASAS TRANS", "trans_fat", float64(data.TransFat) * kilocaloriesPerGramFat, 1},
+		}
+		for _, share := range shares {
+			percent := share.kcal / kcal * 100
+			if share.kcal > 0 && percent >= share.limit {
+				result.Seals = append(result.Seals, newWarningSeal(share.seal, share.nutrient, percent, share.limit, "% of energy"))
+			}
+		}
+	}
+
+	// Sodium: 300mg per 100g or 1mg per kcal; 45mg per 100ml for drinks without calories
+	sodium := float64(data.Sodium)
+	switch {
+	case liquid && kcal < 5:
+		if sodium >= 45 {
+			result.Seals = append(result.Seals, newWarningSeal("EXCESO SODIO", "sodium", sodium, 45, per100Unit("mg", liquid)))
+		}
+	case sodium >= 300:
+		result.Seals = append(result.Seals, newWarningSeal("EXCESO SODIO", "sodium", sodium, 300, per100Unit("mg", liquid)))
+	case kcal > 0 && sodium/kcal >= 1:
+		result.Seals = append(result.Seals, newWarningSeal("EXCESO SODIO", "sodium", sodium/kcal, 1, "mg per kcal"))
+	}
+
+	return result, nil
+}
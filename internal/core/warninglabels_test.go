@@ -0,0 +1,176 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+// sealNames returns the seal texts of a result in order
+func sealNames(result models.WarningLabelResult) []string {
+	var names []string
+	for _, seal := range result.Seals {
+		names = append(names, seal.Seal)
+	}
+	return names
+}
+
+// equalSeals reports whether two seal lists are identical
+func equalSeals(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// beverageAboveProfile is valid as a food but exceeds the energy and sugar limits of the beverage profile
+var beverageAboveProfile = models.Food{Category: "Beverages", NutritionalData: models.NutritionalData{Energy: 1500, Sugars: 85}}
+
+// TestChileWarningEvaluator tests the "ALTO EN" seals for solids and liquids
+func TestChileWarningEvaluator(t *testing.T) {
+	evaluator := NewChileWarningEvaluator()
+
+	tests := []struct {
+		name     string
+		food     models.Food
+		expected []string
+	}{
+		{
+			name: "Chocolate Cookies",
+			food: models.Food{ID: "cookies", NutritionalData: models.NutritionalData{
+				Energy: 2000, Sugars: 30, SaturatedFattyAcids: 10, TotalFat: 20, Sodium: 300}},
+			expected: []string{"ALTO EN CALORÍAS", "ALTO EN AZÚCARES", "ALTO EN GRASAS SATURADAS"},
+		},
+		{
+			name:     "Limits Are Exclusive",
+			food:     models.Food{ID: "bread", NutritionalData: models.NutritionalData{Energy: 1000, Sugars: 10, Sodium: 400}},
+			expected: nil,
+		},
+		{
+			name:     "Salt Is Converted To Sodium",
+			food:     models.Food{ID: "crackers", NutritionalData: models.NutritionalData{Energy: 1000, Salt: 1.5}},
+			expected: []string{"ALTO EN SODIO"}, // 600mg sodium
+		},
+		{
+			name:     "Cola Uses Liquid Limits",
+			food:     models.Food{ID: "cola", Category: "Beverages", NutritionalData: models.NutritionalData{Energy: 180, Sugars: 10.6, Sodium: 4}},
+			expected: []string{"ALTO EN AZÚCARES"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := evaluator.Evaluate(tt.food)
+			if err != nil {
+				t.Fatalf("Evaluate() unexpected error: %v", err)
+			}
+			if result.Market != "CL" || result.FoodID != tt.food.ID {
+				t.Errorf("Evaluate() market %s, food %s", result.Market, result.FoodID)
+			}
+			if names := sealNames(result); !equalSeals(names, tt.expected) {
+				t.Errorf("Seals = %v, want %v", names, tt.expected)
+			}
+		})
+	}
+
+	t.Run("Seal Records Trigger", func(t *testing.T) {
+		result, _ := evaluator.Evaluate(models.Food{Liquid: true, NutritionalData: models.NutritionalData{SaturatedFattyAcids: 3.5, TotalFat: 8}})
+		if len(result.Seals) != 1 {
+			t.Fatalf("Seals = %v, want saturated fat only", sealNames(result))
+		}
+		seal := result.Seals[0]
+		if seal.Nutrient != "saturated_fat" || seal.Value != 3.5 || seal.Limit != 3 || seal.Unit != "g per 100ml" {
+			t.Errorf("Seal = %+v, want 3.5 g per 100ml above 3", seal)
+		}
+	})
+
+	t.Run("Beverage Profile", func(t *testing.T) {
+		if _, err := evaluator.Evaluate(beverageAboveProfile); err == nil || !strings.Contains(err.Error(), "Energy cannot exceed 1000.0 kJ") {
+			t.Errorf("Evaluate() error = %v, want the beverage energy limit", err)
+		}
+	})
+}
+
+// TestMexicoWarningEvaluator tests the NOM-051 octagon seals
+func TestMexicoWarningEvaluator(t *testing.T) {
+	evaluator := NewMexicoWarningEvaluator()
+
+	tests := []struct {
+		name     string
+		food     models.Food
+		expected []string
+	}{
+		{
+			name: "Chocolate Cookies",
+			food: models.Food{NutritionalData: models.NutritionalData{
+				Energy: 2000, Sugars: 30, SaturatedFattyAcids: 10, TotalFat: 20, TransFat: 0.5, Sodium: 300}},
+			// Trans fat provides 0.94% of the energy
+			expected: []string{"EXCESO CALORÍAS", "EXCESO AZÚCARES", "EXCESO GRASAS SATURADAS", "EXCESO SODIO"},
+		},
+		{
+			name: "Margarine",
+			food: models.Food{NutritionalData: models.NutritionalData{
+				Energy: 3000, SaturatedFattyAcids: 20, TotalFat: 80, TransFat: 1, Sodium: 100}},
+			expected: []string{"EXCESO CALORÍAS", "EXCESO GRASAS SATURADAS", "EXCESO GRASAS TRANS"},
+		},
+		{
+			name:     "Cola",
+			food:     models.Food{Category: "Beverages", NutritionalData: models.NutritionalData{Energy: 180, Sugars: 10.6, Sodium: 4}},
+			expected: []string{"EXCESO CALORÍAS", "EXCESO AZÚCARES"},
+		},
+		{
+			name:     "Diet Soda Without Calories",
+			food:     models.Food{Category: "Beverages", NutritionalData: models.NutritionalData{Energy: 1, Sodium: 50}},
+			expected: []string{"EXCESO SODIO"},
+		},
+		{
+			name:     "Plain Water",
+			food:     models.Food{Liquid: true, NutritionalData: models.NutritionalData{}},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := evaluator.Evaluate(tt.food)
+			if err != nil {
+				t.Fatalf("Evaluate() unexpected error: %v", err)
+			}
+			if result.Market != "MX" {
+				t.Errorf("Market = %s, want MX", result.Market)
+			}
+			if names := sealNames(result); !equalSeals(names, tt.expected) {
+				t.Errorf("Seals = %v, want %v", names, tt.expected)
+			}
+		})
+	}
+
+	t.Run("Sodium Per Kilocalorie", func(t *testing.T) {
+		// 250mg sodium in 23.9 kcal exceeds 1mg per kcal
+		result, _ := evaluator.Evaluate(models.Food{NutritionalData: models.NutritionalData{Energy: 100, Sodium: 250}})
+		if len(result.Seals) != 1 {
+			t.Fatalf("Seals = %v, want sodium only", sealNames(result))
+		}
+		if seal := result.Seals[0]; seal.Unit != "mg per kcal" || seal.Value != 10.46 {
+			t.Errorf("Seal = %+v, want 10.46 mg per kcal", seal)
+		}
+	})
+
+	t.Run("Invalid Data", func(t *testing.T) {
+		if _, err := evaluator.Evaluate(models.Food{NutritionalData: models.NutritionalData{TotalFat: 1, TransFat: 2}}); err == nil {
+			t.Error("Evaluate() should reject trans fat above total fat")
+		}
+	})
+
+	t.Run("Beverage Profile", func(t *testing.T) {
+		if _, err := evaluator.Evaluate(beverageAboveProfile); err == nil || !strings.Contains(err.Error(), "Energy cannot exceed 1000.0 kJ") {
+			t.Errorf("Evaluate() error = %v, want the beverage energy limit", err)
+		}
+	})
+}
@@ -0,0 +1,215 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nutritional-score/pkg/models"
+)
+
+// DiaryData represents the structure of the food diary JSON file
+type DiaryData struct {
+	Version     string              `json:"version"`
+	LastUpdated time.Time           `json:"last_updated"`
+	Entries     []models.DiaryEntry `json:"entries"`
+}
+
+// JSONDiaryRepository implements the DiaryRepository interface using JSON file storage
+type JSONDiaryRepository struct {
+	data     *DiaryData
+	filePath string
+	loaded   bool
+}
+
+// NewJSONDiaryRepository creates a new instance of the JSON diary repository
+func NewJSONDiaryRepository(filePath string) *JSONDiaryRepository {
+	return &JSONDiaryRepository{
+		filePath: filePath,
+		loaded:   false,
+	}
+}
+
+// loadData loads diary entries from the JSON file
+func (repo *JSONDiaryRepository) loadData() error {
+	// Check if file exists
+	if _, err := os.Stat(repo.filePath); os.IsNotExist(err) {
+		// Create empty data structure if file doesn't exist
+		repo.data = &DiaryData{
+			Version:     "1.0",
+			LastUpdated: time.Now(),
+			Entries:     []models.DiaryEntry{},
+		}
+		repo.loaded = true
+		return repo.saveData()
+	}
+
+	// Read the file
+	fileData, err := os.ReadFile(repo.filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read diary file: %w", err)
+	}
+
+	// Parse JSON data
+	var data DiaryData
+	if err := json.Unmarshal(fileData, &data); err != nil {
+		return fmt.Errorf("failed to parse diary JSON: %w", err)
+	}
+
+	repo.data = &data
+	repo.loaded = true
+	return nil
+}
+
+// saveData saves diary entries to the JSON file
+func (repo *JSONDiaryRepository) saveData() error {
+	if repo.data == nil {
+		return fmt.Errorf("no data to save")
+	}
+
+	// Update last modified time
+	repo.data.LastUpdated = time.Now()
+
+	// Ensure directory exists
+	dir := filepath.Dir(repo.filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	// Marshal data to JSON
+	jsonData, err := json.MarshalIndent(repo.data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal diary data: %w", err)
+	}
+
+	// Write to file
+	if err := os.WriteFile(repo.filePath, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write diary file: %w", err)
+	}
+
+	return nil
+}
+
+// ensureLoaded ensures that the data is loaded before performing operations
+func (repo *JSONDiaryRepository) ensureLoaded() error {
+	if !repo.loaded {
+		return repo.loadData()
+	}
+	return nil
+}
+
+// SaveEntry stores a new diary entry or updates an existing one
+func (repo *JSONDiaryRepository) SaveEntry(ctx context.Context, entry models.DiaryEntry) error {
+	if err := repo.ensureLoaded(); err != nil {
+		return err
+	}
+
+	if entry.FoodID == "" {
+		return fmt.Errorf("food ID cannot be empty")
+	}
+	if entry.Grams <= 0 {
+		return fmt.Errorf("amount eaten must be greater than 0 g")
+	}
+
+	// Generate ID and default values if not provided
+	if entry.ID == "" {
+		entry.ID = uuid.New().String()
+	}
+	if entry.Meal == "" {
+		entry.Meal = models.Snack
+	}
+	if entry.EatenAt.IsZero() {
+		entry.EatenAt = time.Now()
+	}
+
+	// Check if entry already exists (update case)
+	for i, existingEntry := range repo.data.Entries {
+		if existingEntry.ID == entry.ID {
+			repo.data.Entries[i] = entry
+			return repo.saveData()
+		}
+	}
+
+	repo.data.Entries = append(repo.data.Entries, entry)
+	return repo.saveData()
+}
+
+// GetEntries retrieves diary entries with optional filtering, ordered by the time they were eaten
+func (repo *JSONDiaryRepository) GetEntries(ctx context.Context, filter models.DiaryFilter) ([]models.DiaryEntry, error) {
+	if err := repo.ensureLoaded(); err != nil {
+		return nil, err
+	}
+
+	var entries []models.DiaryEntry
+	for _, entry := range repo.data.Entries {
+		if filter.StartDate != nil && entry.EatenAt.Before(*filter.StartDate) {
+			continue
+		}
+		if filter.EndDate != nil && !entry.EatenAt.Before(*filter.EndDate) {
+			continue
+		}
+		if filter.Meal != "" && entry.Meal != filter.Meal {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].EatenAt.Before(entries[j].EatenAt)
+	})
+
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[:filter.Limit]
+	}
+
+	return entries, nil
+}
+
+// GetEntryByID retrieves a specific diary entry by its ID
+func (repo *JSONDiaryRepository) GetEntryByID(ctx context.Context, id string) (models.DiaryEntry, error) {
+	if err := repo.ensureLoaded(); err != nil {
+		return models.DiaryEntry{}, err
+	}
+
+	if id == "" {
+		return models.DiaryEntry{}, fmt.Errorf("diary entry ID cannot be empty")
+	}
+
+	for _, entry := range repo.data.Entries {
+		if entry.ID == id {
+			return entry, nil
+		}
+	}
+
+	return models.DiaryEntry{}, fmt.Errorf("diary entry not found with ID: %s", id)
+}
+
+// DeleteEntry removes a diary entry from storage
+func (repo *JSONDiaryRepository) DeleteEntry(ctx context.Context, id string) error {
+	if err := repo.ensureLoaded(); err != nil {
+		return err
+	}
+
+	if id == "" {
+		return fmt.Errorf("diary entry ID cannot be empty")
+	}
+
+	for i, entry := range repo.data.Entries {
+		if entry.ID == id {
+			repo.data.Entries = append(repo.data.Entries[:i], repo.data.Entries[i+1:]...)
+			return repo.saveData()
+		}
+	}
+
+	return fmt.Errorf("diary entry not found with ID: %s", id)
+}
+
+// GetDefaultDiaryPath returns the default path for food diary storage
+func GetDefaultDiaryPath() string {
+	return filepath.Join("data", "diary.json")
+}
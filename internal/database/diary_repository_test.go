@@ -0,0 +1,140 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nutritional-score/pkg/models"
+)
+
+func TestJSONDiaryRepository_SaveEntry(t *testing.T) {
+	tempDir := t.TempDir()
+	testFilePath := filepath.Join(tempDir, "diary.json")
+
+	repo := NewJSONDiaryRepository(testFilePath)
+	ctx := context.Background()
+
+	entry := models.DiaryEntry{
+		FoodID: "apple-001",
+		Grams:  150,
+		Meal:   models.Breakfast,
+	}
+
+	if err := repo.SaveEntry(ctx, entry); err != nil {
+		t.Fatalf("Failed to save entry: %v", err)
+	}
+
+	// A fresh repository reads the entry back from disk
+	reloaded := NewJSONDiaryRepository(testFilePath)
+	entries, err := reloaded.GetEntries(ctx, models.DiaryFilter{})
+	if err != nil {
+		t.Fatalf("Failed to get entries: %v", err)
+	}
+
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+
+	saved := entries[0]
+	if saved.ID == "" {
+		t.Error("Saved entry should have an ID")
+	}
+	if saved.EatenAt.IsZero() {
+		t.Error("Saved entry should default to the current time")
+	}
+	if saved.Grams != 150 || saved.Meal != models.Breakfast {
+		t.Errorf("Expected 150 g at breakfast, got %v g at %s", saved.Grams, saved.Meal)
+	}
+
+	// Saving with the same ID updates the entry
+	saved.Grams = 200
+	if err := reloaded.SaveEntry(ctx, saved); err != nil {
+		t.Fatalf("Failed to update entry: %v", err)
+	}
+	updated, err := reloaded.GetEntryByID(ctx, saved.ID)
+	if err != nil {
+		t.Fatalf("Failed to get entry by ID: %v", err)
+	}
+	if updated.Grams != 200 {
+		t.Errorf("Expected 200 g, got %v", updated.Grams)
+	}
+
+	if err := reloaded.DeleteEntry(ctx, saved.ID); err != nil {
+		t.Fatalf("Failed to delete entry: %v", err)
+	}
+	if _, err := reloaded.GetEntryByID(ctx, saved.ID); err == nil {
+		t.Error("Expected error getting deleted entry")
+	}
+}
+
+func TestJSONDiaryRepository_InvalidEntry(t *testing.T) {
+	repo := NewJSONDiaryRepository(filepath.Join(t.TempDir(), "diary.json"))
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		entry models.DiaryEntry
+	}{
+		{"missing food", models.DiaryEntry{Grams: 100}},
+		{"zero amount", models.DiaryEntry{FoodID: "apple-001"}},
+		{"negative amount", models.DiaryEntry{FoodID: "apple-001", Grams: -5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := repo.SaveEntry(ctx, tt.entry); err == nil {
+				t.Error("Expected error saving invalid entry")
+			}
+		})
+	}
+}
+
+func TestJSONDiaryRepository_GetEntries(t *testing.T) {
+	repo := NewJSONDiaryRepository(filepath.Join(t.TempDir(), "diary.json"))
+	ctx := context.Background()
+
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	for _, entry := range []models.DiaryEntry{
+		{FoodID: "salmon-001", Grams: 120, Meal: models.Dinner, EatenAt: day.Add(19 * time.Hour)},
+		{FoodID: "apple-001", Grams: 150, Meal: models.Breakfast, EatenAt: day.Add(8 * time.Hour)},
+		{FoodID: "almonds-001", Grams: 30, Meal: models.Snack, EatenAt: day.Add(16 * time.Hour)},
+		{FoodID: "banana-001", Grams: 120, Meal: models.Breakfast, EatenAt: day.Add(32 * time.Hour)},
+	} {
+		if err := repo.SaveEntry(ctx, entry); err != nil {
+			t.Fatalf("Failed to save entry: %v", err)
+		}
+	}
+
+	start := day
+	end := day.Add(24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		filter  models.DiaryFilter
+		wantIDs []string
+	}{
+		{"all entries in time order", models.DiaryFilter{}, []string{"apple-001", "almonds-001", "salmon-001", "banana-001"}},
+		{"one day", models.DiaryFilter{StartDate: &start, EndDate: &end}, []string{"apple-001", "almonds-001", "salmon-001"}},
+		{"one meal", models.DiaryFilter{Meal: models.Breakfast}, []string{"apple-001", "banana-001"}},
+		{"limit", models.DiaryFilter{Limit: 2}, []string{"apple-001", "almonds-001"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := repo.GetEntries(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("Failed to get entries: %v", err)
+			}
+			if len(entries) != len(tt.wantIDs) {
+				t.Fatalf("Expected %d entries, got %d", len(tt.wantIDs), len(entries))
+			}
+			for i, entry := range entries {
+				if entry.FoodID != tt.wantIDs[i] {
+					t.Errorf("Entry %d: expected %s, got %s", i, tt.wantIDs[i], entry.FoodID)
+				}
+			}
+		})
+	}
+}
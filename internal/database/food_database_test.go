@@ -322,4 +322,29 @@ func TestEmbeddedFoodDatabase_GetCategories(t *testing.T) {
 	if !categoryMap["Meat"] {
 		t.Error("Expected 'Meat' category")
 	}
-}
\ No newline at end of file
+}
+func TestEmbeddedFoodDatabase_SweetenerFlag(t *testing.T) {
+	// The shipped database records sweeteners for diet drinks
+	db := NewEmbeddedFoodDatabase(filepath.Join("..", "..", GetDefaultDatabasePath()))
+	ctx := context.Background()
+
+	if err := db.LoadDatabase(ctx); err != nil {
+		t.Fatalf("Failed to load database: %v", err)
+	}
+
+	dietCola, err := db.GetFoodByID(ctx, "coca-cola-zero-001")
+	if err != nil {
+		t.Fatalf("Failed to get food: %v", err)
+	}
+	if !dietCola.NutritionalData.NonNutritiveSweeteners {
+		t.Error("Diet cola should be flagged as containing non-nutritive sweeteners")
+	}
+
+	regularCola, err := db.GetFoodByID(ctx, "coca-cola-001")
+	if err != nil {
+		t.Fatalf("Failed to get food: %v", err)
+	}
+	if regularCola.NutritionalData.NonNutritiveSweeteners {
+		t.Error("Regular cola should not be flagged as containing non-nutritive sweeteners")
+	}
+}
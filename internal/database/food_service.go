@@ -202,6 +202,45 @@ func (fs *FoodService) DeleteUserFood(ctx context.Context, id string) error {
 	return fs.userFoodRepo.DeleteFood(ctx, id)
 }
 
+// GetRecipeIngredients retrieves the foods referenced by a recipe, keyed by food ID
+// Ingredients can come from the embedded database or the user foods
+func (fs *FoodService) GetRecipeIngredients(ctx context.Context, recipe models.Recipe) (map[string]models.Food, error) {
+	ids := make([]string, 0, len(recipe.Ingredients))
+	for _, ingredient := range recipe.Ingredients {
+		ids = append(ids, ingredient.FoodID)
+	}
+	foods, err := fs.getFoodsByID(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("recipe %q: %w", recipe.Name, err)
+	}
+	return foods, nil
+}
+
+// GetDiaryFoods retrieves the foods referenced by diary entries, keyed by food ID
+func (fs *FoodService) GetDiaryFoods(ctx context.Context, entries []models.DiaryEntry) (map[string]models.Food, error) {
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.FoodID)
+	}
+	return fs.getFoodsByID(ctx, ids)
+}
+
+// getFoodsByID retrieves each distinct food once, keyed by food ID
+func (fs *FoodService) getFoodsByID(ctx context.Context, ids []string) (map[string]models.Food, error) {
+	foods := make(map[string]models.Food, len(ids))
+	for _, id := range ids {
+		if _, found := foods[id]; found {
+			continue
+		}
+		food, err := fs.GetFoodByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		foods[id] = food
+	}
+	return foods, nil
+}
+
 // InitializeDatabase loads the embedded database
 func (fs *FoodService) InitializeDatabase(ctx context.Context) error {
 	return fs.embeddedDB.LoadDatabase(ctx)
@@ -409,4 +409,83 @@ func TestFoodService_GetFoodStats(t *testing.T) {
 	if categoriesCount, ok := stats["categories_count"].(int); !ok || categoriesCount != 2 {
 		t.Errorf("Expected categories_count to be 2, got %v", stats["categories_count"])
 	}
-}
\ No newline at end of file
+}
+func TestFoodService_GetRecipeIngredients(t *testing.T) {
+	tempDir := t.TempDir()
+
+	// Create embedded database
+	embeddedDBPath := filepath.Join(tempDir, "embedded_foods.json")
+	embeddedData := `{
+		"version": "1.0",
+		"last_updated": "2025-01-08T00:00:00Z",
+		"description": "Test embedded database",
+		"foods": [
+			{
+				"id": "embedded-apple-001",
+				"name": "Apple, red",
+				"category": "Fruits",
+				"nutritional_data": {
+					"energy": 218,
+					"sugars": 10.4,
+					"fruits": 100
+				},
+				"is_user_defined": false,
+				"created_at": "2025-01-08T00:00:00Z",
+				"updated_at": "2025-01-08T00:00:00Z",
+				"source": "USDA"
+			}
+		]
+	}`
+
+	if err := os.WriteFile(embeddedDBPath, []byte(embeddedData), 0644); err != nil {
+		t.Fatalf("Failed to create embedded database file: %v", err)
+	}
+
+	embeddedDB := NewEmbeddedFoodDatabase(embeddedDBPath)
+	userRepo := NewJSONUserFoodRepository(filepath.Join(tempDir, "user_foods.json"))
+	foodService := NewFoodService(embeddedDB, userRepo)
+
+	ctx := context.Background()
+	if err := foodService.InitializeDatabase(ctx); err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+
+	if err := foodService.SaveUserFood(ctx, models.Food{Name: "Homemade Crumble", Category: "Desserts"}); err != nil {
+		t.Fatalf("Failed to save user food: %v", err)
+	}
+	userFoods, err := foodService.GetUserFoods(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get user foods: %v", err)
+	}
+	crumbleID := userFoods[0].ID
+
+	// Ingredients can mix embedded and user foods
+	recipe := models.Recipe{
+		Name: "Apple Crumble",
+		Ingredients: []models.RecipeIngredient{
+			{FoodID: "embedded-apple-001", Grams: 300},
+			{FoodID: crumbleID, Grams: 150},
+			{FoodID: "embedded-apple-001", Grams: 50},
+		},
+	}
+
+	ingredients, err := foodService.GetRecipeIngredients(ctx, recipe)
+	if err != nil {
+		t.Fatalf("Failed to get recipe ingredients: %v", err)
+	}
+	if len(ingredients) != 2 {
+		t.Errorf("Expected 2 distinct ingredients, got %d", len(ingredients))
+	}
+	if ingredients["embedded-apple-001"].Name != "Apple, red" {
+		t.Errorf("Expected 'Apple, red', got '%s'", ingredients["embedded-apple-001"].Name)
+	}
+	if ingredients[crumbleID].Name != "Homemade Crumble" {
+		t.Errorf("Expected 'Homemade Crumble', got '%s'", ingredients[crumbleID].Name)
+	}
+
+	// A missing ingredient fails the lookup
+	recipe.Ingredients = append(recipe.Ingredients, models.RecipeIngredient{FoodID: "missing-001", Grams: 10})
+	if _, err := foodService.GetRecipeIngredients(ctx, recipe); err == nil {
+		t.Error("Expected error for a missing ingredient")
+	}
+}
@@ -15,9 +15,10 @@ import (
 
 // UserFoodData represents the structure of the user foods JSON file
 type UserFoodData struct {
-	Version     string        `json:"version"`
-	LastUpdated time.Time     `json:"last_updated"`
-	Foods       []models.Food `json:"foods"`
+	Version     string          `json:"version"`
+	LastUpdated time.Time       `json:"last_updated"`
+	Foods       []models.Food   `json:"foods"`
+	Recipes     []models.Recipe `json:"recipes,omitempty"`
 }
 
 // JSONUserFoodRepository implements the UserFoodRepository interface using JSON file storage
@@ -262,6 +263,89 @@ func (repo *JSONUserFoodRepository) GetUserFoodCount(ctx context.Context) (int,
 	return len(repo.data.Foods), nil
 }
 
+// SaveRecipe stores a new recipe or updates an existing one
+func (repo *JSONUserFoodRepository) SaveRecipe(ctx context.Context, recipe models.Recipe) error {
+	if err := repo.ensureLoaded(); err != nil {
+		return err
+	}
+
+	// Generate ID if not provided
+	if recipe.ID == "" {
+		recipe.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+
+	// Check if recipe already exists (update case)
+	for i, existingRecipe := range repo.data.Recipes {
+		if existingRecipe.ID == recipe.ID {
+			recipe.CreatedAt = existingRecipe.CreatedAt // Preserve original creation time
+			recipe.UpdatedAt = now
+			repo.data.Recipes[i] = recipe
+			return repo.saveData()
+		}
+	}
+
+	// New recipe case
+	recipe.CreatedAt = now
+	recipe.UpdatedAt = now
+	repo.data.Recipes = append(repo.data.Recipes, recipe)
+
+	return repo.saveData()
+}
+
+// GetRecipes retrieves all stored recipes
+func (repo *JSONUserFoodRepository) GetRecipes(ctx context.Context) ([]models.Recipe, error) {
+	if err := repo.ensureLoaded(); err != nil {
+		return nil, err
+	}
+
+	// Return a copy of the recipes slice to prevent external modification
+	recipes := make([]models.Recipe, len(repo.data.Recipes))
+	copy(recipes, repo.data.Recipes)
+
+	return recipes, nil
+}
+
+// GetRecipeByID retrieves a specific recipe by ID
+func (repo *JSONUserFoodRepository) GetRecipeByID(ctx context.Context, id string) (models.Recipe, error) {
+	if err := repo.ensureLoaded(); err != nil {
+		return models.Recipe{}, err
+	}
+
+	if id == "" {
+		return models.Recipe{}, fmt.Errorf("recipe ID cannot be empty")
+	}
+
+	for _, recipe := range repo.data.Recipes {
+		if recipe.ID == id {
+			return recipe, nil
+		}
+	}
+
+	return models.Recipe{}, fmt.Errorf("recipe not found with ID: %s", id)
+}
+
+// DeleteRecipe removes a recipe from storage
+func (repo *JSONUserFoodRepository) DeleteRecipe(ctx context.Context, id string) error {
+	if err := repo.ensureLoaded(); err != nil {
+		return err
+	}
+
+	if id == "" {
+		return fmt.Errorf("recipe ID cannot be empty")
+	}
+
+	for i, recipe := range repo.data.Recipes {
+		if recipe.ID == id {
+			repo.data.Recipes = append(repo.data.Recipes[:i], repo.data.Recipes[i+1:]...)
+			return repo.saveData()
+		}
+	}
+
+	return fmt.Errorf("recipe not found with ID: %s", id)
+}
+
 // GetDefaultUserFoodsPath returns the default path for user foods storage
 func GetDefaultUserFoodsPath() string {
 	return filepath.Join("data", "user_foods.json")
@@ -293,4 +293,109 @@ func TestJSONUserFoodRepository_SearchUserFoods(t *testing.T) {
 	if len(results) != 0 {
 		t.Errorf("Expected 0 results for 'nonexistent', got %d", len(results))
 	}
-}
\ No newline at end of file
+}
+func TestJSONUserFoodRepository_SweetenerFlag(t *testing.T) {
+	tempDir := t.TempDir()
+	testFilePath := filepath.Join(tempDir, "user_foods.json")
+
+	repo := NewJSONUserFoodRepository(testFilePath)
+	ctx := context.Background()
+
+	dietDrink := models.Food{
+		Name:     "Diet Lemonade",
+		Category: "Beverages",
+		NutritionalData: models.NutritionalData{
+			Energy:                 4,
+			Sodium:                 10,
+			NonNutritiveSweeteners: true,
+		},
+	}
+
+	if err := repo.SaveFood(ctx, dietDrink); err != nil {
+		t.Fatalf("Failed to save food: %v", err)
+	}
+
+	// A fresh repository reads the flag back from disk
+	reloaded := NewJSONUserFoodRepository(testFilePath)
+	foods, err := reloaded.GetUserFoods(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get user foods: %v", err)
+	}
+
+	if len(foods) != 1 {
+		t.Fatalf("Expected 1 food, got %d", len(foods))
+	}
+
+	if !foods[0].NutritionalData.NonNutritiveSweeteners {
+		t.Error("Non-nutritive sweetener flag should be persisted")
+	}
+}
+
+func TestJSONUserFoodRepository_Recipes(t *testing.T) {
+	tempDir := t.TempDir()
+	testFilePath := filepath.Join(tempDir, "user_foods.json")
+
+	repo := NewJSONUserFoodRepository(testFilePath)
+	ctx := context.Background()
+
+	porridge := models.Recipe{
+		Name:      "Porridge",
+		ScoreType: models.FoodType,
+		Ingredients: []models.RecipeIngredient{
+			{FoodID: "oats-001", Grams: 50},
+			{FoodID: "banana-001", Grams: 80},
+		},
+		YieldFactor: 0.9,
+	}
+
+	if err := repo.SaveRecipe(ctx, porridge); err != nil {
+		t.Fatalf("Failed to save recipe: %v", err)
+	}
+
+	recipes, err := repo.GetRecipes(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get recipes: %v", err)
+	}
+	if len(recipes) != 1 {
+		t.Fatalf("Expected 1 recipe, got %d", len(recipes))
+	}
+	saved := recipes[0]
+	if saved.ID == "" {
+		t.Error("Saved recipe should have an ID")
+	}
+	if saved.CreatedAt.IsZero() {
+		t.Error("Saved recipe should have a creation time")
+	}
+
+	// Saving with the same ID updates the recipe
+	saved.Ingredients = append(saved.Ingredients, models.RecipeIngredient{FoodID: "milk-whole-001", Grams: 200})
+	if err := repo.SaveRecipe(ctx, saved); err != nil {
+		t.Fatalf("Failed to update recipe: %v", err)
+	}
+
+	// A fresh repository reads the recipe back from disk
+	reloaded := NewJSONUserFoodRepository(testFilePath)
+	recipe, err := reloaded.GetRecipeByID(ctx, saved.ID)
+	if err != nil {
+		t.Fatalf("Failed to get recipe by ID: %v", err)
+	}
+	if len(recipe.Ingredients) != 3 {
+		t.Errorf("Expected 3 ingredients, got %d", len(recipe.Ingredients))
+	}
+	if recipe.YieldFactor != 0.9 {
+		t.Errorf("Expected yield factor 0.9, got %v", recipe.YieldFactor)
+	}
+	if !recipe.CreatedAt.Equal(saved.CreatedAt) {
+		t.Error("Updating a recipe should keep its creation time")
+	}
+
+	if err := reloaded.DeleteRecipe(ctx, saved.ID); err != nil {
+		t.Fatalf("Failed to delete recipe: %v", err)
+	}
+	if _, err := reloaded.GetRecipeByID(ctx, saved.ID); err == nil {
+		t.Error("Expected error getting deleted recipe")
+	}
+	if err := reloaded.DeleteRecipe(ctx, saved.ID); err == nil {
+		t.Error("Expected error deleting nonexistent recipe")
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
 )
 
 // main function - entry point for the nutritional score calculator
@@ -12,8 +13,20 @@ func main() {
 	var n NutritionalData
 	var st int
 	
+	var scheme string
+	
 	fmt.Println("=== Nutritional Score Calculator ===")
 	
+	// Pick the scoring scheme: Nutri-Score, the Australian/New Zealand Health Star Rating,
+	// or the profile view that runs every scheme side by side
+	fmt.Println("Enter scheme (nutriscore, hsr, profile):")
+	fmt.Scan(&scheme)
+	scheme = strings.ToLower(scheme)
+	if scheme != "nutriscore" && scheme != "hsr" && scheme != "profile" {
+		fmt.Println("Invalid scheme")
+		os.Exit(1)
+	}
+	
 	// Collect nutritional data from user input with clear prompts
 	fmt.Println("Enter Energy (kJ):")
 	fmt.Scan(&n.Energy)
@@ -21,8 +34,19 @@ func main() {
 	fmt.Scan(&n.Sugars)
 	fmt.Println("Enter Saturated Fatty Acids (g):")
 	fmt.Scan(&n.SaturatedFattyAcids)
-	fmt.Println("Enter Sodium (mg):")
+	fmt.Println("Enter Total Fat (g):")
+	fmt.Scan(&n.TotalFat)
+	fmt.Println("Enter Trans Fat (g):")
+	fmt.Scan(&n.TransFat)
+	fmt.Println("Enter Carbohydrate (g), or 0 if not declared:")
+	fmt.Scan(&n.Carbohydrate)
+	fmt.Println("Enter Sodium (mg), or 0 if the label declares salt:")
 	fmt.Scan(&n.Sodium)
+	if n.Sodium == 0 {
+		fmt.Println("Enter Salt (g):")
+		fmt.Scan(&n.Salt)
+	}
+	n = n.ResolveSodium()
 	fmt.Println("Enter Fruits (%):")
 	fmt.Scan(&n.Fruits)
 	fmt.Println("Enter Fibre (g):")
@@ -31,16 +55,235 @@ func main() {
 	fmt.Scan(&n.Protein)
 	
 	// Get score type from user with validation
-	fmt.Println("Enter Scoretype (0:Food, 1:Beverage, 2:Water, 3:Cheese):")
+	fmt.Println("Enter Scoretype (0:Food, 1:Beverage, 2:Water, 3:Cheese, 4:Fats/Oils):")
 	fmt.Scan(&st)
 	
 	// Validate score type input range
-	if st < 0 || st > 3 {
+	if st < 0 || st > 4 {
 		fmt.Println("Invalid Scoretype")
 		os.Exit(1)
 	}
 	
+	// Beverages are penalised for non-nutritive sweeteners
+	if ScoreType(st) == Beverage {
+		var sweeteners string
+		fmt.Println("Contains non-nutritive sweeteners? (y/n):")
+		fmt.Scan(&sweeteners)
+		n.NonNutritiveSweeteners = sweeteners == "y" || sweeteners == "Y"
+	}
+	
+	// Labels may declare the values per serving or per pack instead of per 100g
+	var basis, unit string
+	var size, density float64
+	fmt.Println("Values declared per (100, serving, pack):")
+	fmt.Scan(&basis)
+	if basis != "100" {
+		fmt.Println("Enter serving or pack size:")
+		fmt.Scan(&size)
+		fmt.Println("Enter unit (g or ml):")
+		fmt.Scan(&unit)
+		liquid := ScoreType(st) == Beverage || ScoreType(st) == Water
+		if (liquid && unit == "g") || (!liquid && unit == "ml") {
+			fmt.Println("Enter density (g per ml):")
+			fmt.Scan(&density)
+		}
+	}
+	food, err := GetDeclaredFood(n, ScoreType(st), basis, size, unit, density)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	n = food.NutritionalData
+	if table, err := GetServingTable(food, 0); err == nil {
+		servingUnit := "g"
+		if table.Liquid {
+			servingUnit = "ml"
+		}
+		fmt.Printf("Per 100%s and per %g %s serving:\n", servingUnit, table.ServingSize, servingUnit)
+		for _, nutrient := range table.Nutrients {
+			fmt.Printf("  %-10s %g %s / %g %s\n", nutrient.Nutrient, nutrient.Per100, nutrient.Unit, nutrient.PerServing, nutrient.Unit)
+		}
+	}
+	
+	// Nutrients missing from the label are estimated from similar foods instead of counting as 0
+	var unknown string
+	fmt.Println("Unknown nutrients (comma separated, e.g. fibre,fruits, or - if all are known):")
+	fmt.Scan(&unknown)
+	if unknown != "-" {
+		var category string
+		fmt.Println("Enter food category (e.g. Fruits, Grains, Dairy):")
+		fmt.Scan(&category)
+		result, err := GetImputedScore(n, ScoreType(st), category, strings.Split(strings.ToLower(unknown), ","))
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		for _, value := range result.Imputed {
+			fmt.Printf("Estimated %s: %g %s (median of %d foods in %s, range %g–%g)\n",
+				value.Nutrient, value.Value, value.Unit, value.Samples, value.Source, value.Min, value.Max)
+		}
+		fmt.Printf("Nutritional Score: %d (grade %s), confidence %.0f%%\n", result.Score.Value, result.Score.Grade, result.Confidence*100)
+		fmt.Printf("With the unknown values the score could be %d to %d (grades %s)\n",
+			result.MinScore, result.MaxScore, strings.Join(result.Grades, ", "))
+		return
+	}
+	
+	// The profile view shows every scheme at once and can export the report
+	if scheme == "profile" {
+		report := GetFoodProfileReport(food, ScoreType(st))
+		for _, row := range GetProfileRows(report) {
+			fmt.Printf("%-18s %-26s %-14s %-8s %s\n", row[0], row[1], row[2], row[3], row[4])
+		}
+		
+		var format string
+		fmt.Println("Export the profile (json, csv, or - to skip):")
+		fmt.Scan(&format)
+		if format != "-" {
+			data, err := ExportProfileReport(report, format)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+		}
+		return
+	}
+	
+	// The Health Star Rating only reports its stars and explanation
+	if scheme == "hsr" {
+		result, err := GetSchemeScore(n, ScoreType(st), scheme)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Printf("Health Star Rating: %g stars (score %d)\n", result.Stars, result.Value)
+		if explanation, err := GetSchemeExplanation(n, ScoreType(st), scheme); err == nil {
+			fmt.Println()
+			fmt.Println(explanation.Narrative)
+		}
+		return
+	}
+	
 	// Calculate and display the nutritional score using the corrected function name
 	result := GetNutritionalScore(n, ScoreType(st))
 	fmt.Printf("Nutritional Score: %+v\n", result)
+	for _, warning := range result.Warnings {
+		fmt.Println("Warning:", warning.Message)
+	}
+	
+	// Explain the score nutrient by nutrient
+	if explanation, err := GetScoreExplanation(n, ScoreType(st)); err == nil {
+		fmt.Println()
+		fmt.Println(explanation.Narrative)
+	}
+	
+	// Show how close the nutrients are to their band edges
+	if report, err := GetSensitivityReport(n, ScoreType(st)); err == nil && len(report.Nutrients) > 0 {
+		fmt.Println()
+		fmt.Println("Distance to band edges (lower / higher):")
+		for _, nutrient := range report.Nutrients {
+			lower, higher := "-", "-"
+			if nutrient.DistanceToLowerBand != nil {
+				lower = fmt.Sprintf("%g", *nutrient.DistanceToLowerBand)
+			}
+			if nutrient.DistanceToHigherBand != nil {
+				higher = fmt.Sprintf("%g", *nutrient.DistanceToHigherBand)
+			}
+			line := fmt.Sprintf("  %-14s %g %s: %s / %s", nutrient.Nutrient, nutrient.Value, nutrient.Unit, lower, higher)
+			if nutrient.GradeFlip {
+				line += fmt.Sprintf(" (grade %s within ±%g tolerance)", strings.Join(nutrient.FlipGrades, "–"), nutrient.Tolerance)
+			}
+			fmt.Println(line)
+		}
+	}
+	
+	// A lab test may find any value within the labelling tolerances
+	if tolerance, err := GetToleranceScore(n, ScoreType(st)); err == nil {
+		if tolerance.Robust {
+			fmt.Printf("Grade %s is robust within labelling tolerance\n", tolerance.GradeInterval)
+		} else {
+			fmt.Printf("Within labelling tolerance the grade could be %s: not robust to a lab test\n", tolerance.GradeInterval)
+		}
+	}
+	
+	// Show the UK front-of-pack traffic lights, per portion when a serving size is given
+	var servingSize float64
+	if food.Declaration != nil {
+		if table, err := GetServingTable(food, 0); err == nil {
+			servingSize = table.ServingSize
+		}
+	} else {
+		fmt.Println("Enter serving size for traffic lights (g or ml, 0 for per 100g only):")
+		fmt.Scan(&servingSize)
+	}
+	if label, err := GetTrafficLights(n, ScoreType(st), servingSize); err != nil {
+		fmt.Println(err)
+	} else {
+		fmt.Println("Traffic lights:")
+		for _, light := range label.Lights {
+			line := fmt.Sprintf("  %-10s %g g per 100g: %s", light.Label, light.Per100g, light.Colour)
+			if light.PerPortion != nil {
+				line += fmt.Sprintf(" (%g g per portion, %.0f%% of reference intake)", *light.PerPortion, *light.ReferenceIntakePercent)
+			}
+			if light.PortionOverride {
+				line += " - red because of the portion size"
+			}
+			fmt.Println(line)
+		}
+	}
+	
+	// Check the UK advertising rules (HFSS classification)
+	if verdict, err := GetHFSSVerdict(n, ScoreType(st)); err == nil {
+		fmt.Println("UK Nutrient Profiling Model:")
+		for _, reason := range verdict.Reasons {
+			fmt.Println("  " + reason)
+		}
+	}
+	
+	// Check the Chilean and Mexican warning seals
+	if results, err := GetWarningLabels(n, ScoreType(st)); err == nil {
+		for _, result := range results {
+			if len(result.Seals) == 0 {
+				fmt.Printf("%s (%s): no warning seals\n", result.Market, result.Regulation)
+				continue
+			}
+			fmt.Printf("%s (%s):\n", result.Market, result.Regulation)
+			for _, seal := range result.Seals {
+				fmt.Printf("  %s (%.1f %s, limit %g)\n", seal.Seal, seal.Value, seal.Unit, seal.Limit)
+			}
+		}
+	}
+	
+	// List the EU nutrition claims the product qualifies for
+	if report, err := GetNutritionClaims(n, ScoreType(st)); err == nil {
+		fmt.Println("EU nutrition claims:")
+		for _, claim := range report.EligibleClaims() {
+			fmt.Printf("  %s (%s, margin %g)\n", claim.Claim, claim.Rule, claim.Margin)
+		}
+	}
+	
+	// Suggest the smallest changes that reach a better grade
+	var target string
+	fmt.Println("Enter a target grade for reformulation advice (A-E, or - to skip):")
+	fmt.Scan(&target)
+	if target != "-" {
+		plan, err := GetReformulationPlan(n, ScoreType(st), strings.ToUpper(target))
+		switch {
+		case err != nil:
+			fmt.Println(err)
+		case !plan.Achievable:
+			fmt.Printf("Grade %s cannot be reached by changing the nutrients\n", plan.TargetGrade)
+		case len(plan.Changes) == 0:
+			fmt.Printf("The product already reaches grade %s\n", plan.TargetGrade)
+		default:
+			for _, change := range plan.Changes {
+				fmt.Printf("Change %s from %.1f %s to %.1f %s (%d points)\n",
+					change.Nutrient, change.From, change.Unit, change.To, change.Unit, change.PointsGain)
+			}
+			fmt.Printf("New score: %d (grade %s)\n", plan.Reformulated.Value, plan.Reformulated.Grade)
+			if plan.Truncated {
+				fmt.Println("The search limit was reached: these changes reach the grade but may not be the smallest")
+			}
+		}
+	}
 }
\ No newline at end of file
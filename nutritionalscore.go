@@ -1,8 +1,14 @@
 package main
 
 import (
-	"nutritional-score/internal/core"
-	"nutritional-score/pkg/models"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/nutritional-score/internal/core"
+	"github.com/nutritional-score/internal/database"
+	"github.com/nutritional-score/pkg/models"
 )
 
 // Legacy type aliases for backward compatibility with existing main.go
@@ -10,6 +16,7 @@ import (
 type ScoreType = models.ScoreType
 type NutritionalScore = models.NutritionalScore
 type NutritionalData = models.NutritionalData
+type ScoreExplanation = models.ScoreExplanation
 
 // Legacy constants for backward compatibility
 const (
@@ -17,6 +24,7 @@ const (
 	Beverage = models.BeverageType
 	Water    = models.WaterType
 	Cheese   = models.CheeseType
+	FatsOils = models.FatsOilsType
 )
 
 // Legacy type aliases for nutritional components
@@ -25,19 +33,47 @@ type EnergyKJ = models.EnergyKJ
 type SugarGram = models.SugarGram
 type SaturatedFattyAcids = models.SaturatedFattyAcids
 type SodiumMilligram = models.SodiumMilligram
+type SaltGram = models.SaltGram
+type TotalFatGram = models.TotalFatGram
+type CarbohydrateGram = models.CarbohydrateGram
+type TransFatGram = models.TransFatGram
 type FruitsPercent = models.FruitsPercent
 type FibreGram = models.FibreGram
 type ProteinGram = models.ProteinGram
 
+// nutriScorer is the Nutri-Score scorer shared by the CLI helpers
+// It uses the ruleset shipped in data/rulesets, loaded once at startup
+var nutriScorer = loadNutriScorer()
+
+// loadNutriScorer creates a scorer from the default ruleset file
+// The built-in rules are used, with a notice, if the file cannot be loaded
+func loadNutriScorer() *core.NutritionalScorer {
+	ruleset, err := core.LoadRuleset(core.GetDefaultRulesetPath())
+	if err == nil {
+		scorer, rulesetErr := core.NewNutritionalScorerWithRuleset(ruleset)
+		if rulesetErr == nil {
+			return scorer
+		}
+		err = rulesetErr
+	}
+	log.Printf("Using the built-in Nutri-Score rules, %s could not be loaded: %v", core.GetDefaultRulesetPath(), err)
+	return core.NewNutritionalScorer()
+}
+
+// schemeScorer returns the scorer for the named scheme, using the loaded ruleset for Nutri-Score
+func schemeScorer(scheme string) (models.NutritionalScorer, error) {
+	if scheme == models.SchemeNutriScore || scheme == "" {
+		return nutriScorer, nil
+	}
+	return core.NewScorer(scheme)
+}
+
 // GetNutritionalScore calculates the nutritional score using the enhanced scoring engine
 // This function now uses the official Nutri-Score algorithm with proper validation
 func GetNutritionalScore(n NutritionalData, st ScoreType) NutritionalScore {
-	// Create the enhanced nutritional scorer with official algorithm
-	scorer := core.NewNutritionalScorer()
-	
-	// Calculate the score using the official Nutri-Score algorithm
+	// Calculate the score using the Nutri-Score ruleset loaded at startup
 	// This includes proper validation and grade assignment
-	result, err := scorer.CalculateScore(n, st)
+	result, err := nutriScorer.CalculateScore(n, st)
 	if err != nil {
 		// If there's a validation error, return a default score with error indication
 		// In a real application, this error should be handled properly
@@ -52,9 +88,162 @@ func GetNutritionalScore(n NutritionalData, st ScoreType) NutritionalScore {
 	
 	return result
 }
-// 
-ValidateNutritionalData validates nutritional data and returns user-friendly error messages
+
+// GetSchemeScore calculates the score with the named scheme ("nutriscore" or "hsr")
+// Unlike GetNutritionalScore it returns validation and unknown scheme errors
+func GetSchemeScore(n NutritionalData, st ScoreType, scheme string) (NutritionalScore, error) {
+	scorer, err := schemeScorer(scheme)
+	if err != nil {
+		return NutritionalScore{}, err
+	}
+	return scorer.CalculateScore(n, st)
+}
+
+// GetSchemeExplanation explains how each nutrient contributed to the score of the named scheme
+func GetSchemeExplanation(n NutritionalData, st ScoreType, scheme string) (ScoreExplanation, error) {
+	scorer, err := schemeScorer(scheme)
+	if err != nil {
+		return ScoreExplanation{}, err
+	}
+	return scorer.Explain(n, st)
+}
+
+// GetScoreExplanation explains how each nutrient contributed to the nutritional score
+// Returns the validation or calculation error if the data cannot be scored
+func GetScoreExplanation(n NutritionalData, st ScoreType) (ScoreExplanation, error) {
+	return nutriScorer.Explain(n, st)
+}
+
+// GetReformulationPlan finds the smallest nutrient changes that reach the target grade
+// All nutrients may be changed; use the core scorer directly for bounds and locked nutrients
+func GetReformulationPlan(n NutritionalData, st ScoreType, targetGrade string) (models.ReformulationPlan, error) {
+	return nutriScorer.Reformulate(n, st, models.ReformulationOptions{TargetGrade: targetGrade})
+}
+
+// GetSensitivityReport shows how close each nutrient is to its band edges and whether
+// a change within labelling tolerance could change the grade
+func GetSensitivityReport(n NutritionalData, st ScoreType) (models.SensitivityReport, error) {
+	return nutriScorer.Sensitivity(n, st)
+}
+
+// GetToleranceScore scores a product at both ends of the EU labelling tolerances
+// Products whose grade is not robust could be challenged by a lab test
+func GetToleranceScore(n NutritionalData, st ScoreType) (models.ToleranceScore, error) {
+	return nutriScorer.CalculateScoreWithTolerance(n, st)
+}
+
+// GetTrafficLights rates fat, saturates, sugars and salt with the UK traffic light scheme
+// A serving size in g or ml above 0 also applies the per-portion red overrides
+func GetTrafficLights(n NutritionalData, st ScoreType, servingSize float64) (models.TrafficLightLabel, error) {
+	evaluator := core.NewTrafficLightEvaluator()
+	return evaluator.Evaluate(n, st, servingSize)
+}
+
+// GetHFSSVerdict classifies a product with the UK Nutrient Profiling Model 2004/05
+// The verdict lists the reasons so products can be checked before advertising
+func GetHFSSVerdict(n NutritionalData, st ScoreType) (models.HFSSVerdict, error) {
+	classifier := core.NewHFSSClassifier()
+	return classifier.Classify(n, st)
+}
+
+// GetWarningLabels evaluates the Chilean and Mexican warning seals of a product
+// Beverages and water are evaluated per 100ml
+func GetWarningLabels(n NutritionalData, st ScoreType) ([]models.WarningLabelResult, error) {
+	food := models.Food{NutritionalData: n, Liquid: st == Beverage || st == Water}
+	evaluators := []models.WarningLabelEvaluator{core.NewChileWarningEvaluator(), core.NewMexicoWarningEvaluator()}
+	
+	var results []models.WarningLabelResult
+	for _, evaluator := range evaluators {
+		result, err := evaluator.Evaluate(food)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// GetNutritionClaims checks which EU nutrition claims (Regulation 1924/2006) a product qualifies for
+// Beverages and water are checked per 100ml
+func GetNutritionClaims(n NutritionalData, st ScoreType) (models.ClaimReport, error) {
+	checker := core.NewClaimChecker()
+	return checker.Check(models.Food{NutritionalData: n, Liquid: st == Beverage || st == Water})
+}
+
+// GetProfileReport runs every profiling scheme on a product and consolidates the results
+// Schemes that cannot evaluate the product report their error in the result
+func GetProfileReport(n NutritionalData, st ScoreType) models.ProfileReport {
+	profiler := core.NewProfiler()
+	return profiler.Profile(models.Food{NutritionalData: n, Liquid: st == Beverage || st == Water}, st)
+}
+
+// GetDeclaredFood normalises values declared per "serving" or "pack" to per 100g (per 100ml for
+// beverages and water) and keeps the original declaration on the food
+// The size is in "g" or "ml"; the density in g per ml converts between the two
+func GetDeclaredFood(n NutritionalData, st ScoreType, basis string, size float64, unit string, density float64) (models.Food, error) {
+	declaration := models.NutritionDeclaration{
+		Basis:   models.DeclarationBasis(strings.ToLower(basis)),
+		Size:    size,
+		Unit:    models.ServingUnit(strings.ToLower(unit)),
+		Density: density,
+		Values:  n,
+	}
+	return core.ApplyDeclaration(models.Food{Liquid: st == Beverage || st == Water}, declaration)
+}
+
+// GetServingTable lists a food's nutrients per 100g and per serving
+// A serving size of 0 uses the serving declared on the label
+func GetServingTable(food models.Food, servingSize float64) (models.ServingTable, error) {
+	return core.NewServingTable(food, servingSize)
+}
+
+// GetFoodProfileReport runs every profiling scheme on a food, using its declared serving size
+func GetFoodProfileReport(food models.Food, st ScoreType) models.ProfileReport {
+	profiler := core.NewProfiler()
+	return profiler.Profile(food, st)
+}
+
+// GetImputedScore scores a product whose listed nutrients ("fibre", "fruits", ...) are unknown
+// Unknown values are estimated from the foods of the same category in the embedded database;
+// the result carries a confidence and the grades the product could get
+func GetImputedScore(n NutritionalData, st ScoreType, category string, unknown []string) (models.ImputedScore, error) {
+	ctx := context.Background()
+	db := database.NewEmbeddedFoodDatabase(database.GetDefaultDatabasePath())
+	if err := db.LoadDatabase(ctx); err != nil {
+		return models.ImputedScore{}, err
+	}
+	foods, err := db.GetAllFoods(ctx)
+	if err != nil {
+		return models.ImputedScore{}, err
+	}
+	
+	n.Unknown = unknown
+	food := models.Food{Category: category, NutritionalData: n, Liquid: st == Beverage || st == Water}
+	imputer := core.NewImputer(foods)
+	return imputer.Score(nutriScorer, food, st)
+}
+
+// GetProfileRows flattens a profile report into scheme, item, result, value and detail columns
+func GetProfileRows(report models.ProfileReport) [][]string {
+	return core.ProfileReportRows(report)
+}
+
+// ExportProfileReport exports a profile report as "json" or "csv"
+func ExportProfileReport(report models.ProfileReport, format string) ([]byte, error) {
+	switch strings.ToLower(format) {
+	case "json":
+		return core.ExportProfileReport(report, models.JSON)
+	case "csv":
+		return core.ExportProfileReport(report, models.CSV)
+	default:
+		return nil, models.NewExportError(fmt.Sprintf("Unsupported export format: %s", format),
+			"profile reports can be exported as json or csv")
+	}
+}
+
+// ValidateNutritionalData validates nutritional data and returns user-friendly error messages
 // This function provides a simple interface for validation in the CLI
+// Warnings about implausible values are prefixed with "Warning: "
 func ValidateNutritionalData(n NutritionalData) []string {
 	validator := core.NewInputValidator()
 	validationErrors := validator.ValidateNutritionalData(n)
@@ -62,6 +251,10 @@ func ValidateNutritionalData(n NutritionalData) []string {
 	// Convert validation errors to simple string messages for CLI display
 	var messages []string
 	for _, err := range validationErrors {
+		if err.IsWarning() {
+			messages = append(messages, "Warning: "+err.Message)
+			continue
+		}
 		messages = append(messages, err.Message)
 	}
 	
@@ -71,13 +264,11 @@ func ValidateNutritionalData(n NutritionalData) []string {
 // GetScoreGrade converts a numerical score to a letter grade using official thresholds
 // This provides a simple interface to the enhanced scoring system
 func GetScoreGrade(score int) string {
-	scorer := core.NewNutritionalScorer()
-	return scorer.GetScoreGrade(score)
+	return nutriScorer.GetScoreGrade(score)
 }
 
-// GetScoreThresholds returns the official Nutri-Score grade thresholds
+// GetScoreThresholds returns the official Nutri-Score grade thresholds per score type
 // Useful for displaying grade information to users
-func GetScoreThresholds() map[string]int {
-	scorer := core.NewNutritionalScorer()
-	return scorer.GetScoreThresholds()
+func GetScoreThresholds() map[ScoreType]map[string]int {
+	return nutriScorer.GetScoreThresholds()
 }
\ No newline at end of file
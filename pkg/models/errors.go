@@ -4,14 +4,24 @@ import (
 	"fmt"
 )
 
+// Severity tells whether a validation result stops processing
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // The value is impossible; the data is rejected
+	SeverityWarning Severity = "warning" // The value is implausible but possible; processing continues
+)
+
 // ValidationError represents a validation error for nutritional data
 // This struct provides detailed information about validation failures
 type ValidationError struct {
-	Field   string   `json:"field"`             // Name of the field that failed validation
-	Value   float64  `json:"value"`             // The invalid value
-	Message string   `json:"message"`           // Human-readable error message
-	Min     *float64 `json:"min,omitempty"`     // Minimum allowed value (if applicable)
-	Max     *float64 `json:"max,omitempty"`     // Maximum allowed value (if applicable)
+	Field       string   `json:"field"`                 // Name of the field that failed validation
+	Value       float64  `json:"value"`                 // The invalid value
+	Message     string   `json:"message"`               // Human-readable error message
+	Min         *float64 `json:"min,omitempty"`         // Minimum allowed value (if applicable)
+	Max         *float64 `json:"max,omitempty"`         // Maximum allowed value (if applicable)
+	Severity    Severity `json:"severity,omitempty"`    // Error or warning; an empty severity is an error
+	Suggestions []string `json:"suggestions,omitempty"` // Suggested actions to correct the value
 }
 
 // Error implements the error interface for ValidationError
@@ -19,6 +29,21 @@ func (ve ValidationError) Error() string {
 	return ve.Message
 }
 
+// IsWarning returns true if the validation result does not reject the data
+func (ve ValidationError) IsWarning() bool {
+	return ve.Severity == SeverityWarning
+}
+
+// ToNutritionalError converts the validation result into a structured validation error
+func (ve ValidationError) ToNutritionalError() NutritionalError {
+	err := NewValidationError(ve.Field, ve.Message, ve.Suggestions...)
+	if ve.IsWarning() {
+		err.Code = "VALIDATION_WARNING"
+		err.Severity = SeverityWarning
+	}
+	return err
+}
+
 // ErrorType represents the category of error that occurred
 // This enum helps classify errors for appropriate handling and user messaging
 type ErrorType string
@@ -46,14 +71,19 @@ type NutritionalError struct {
 	Details     string    `json:"details,omitempty"`      // Additional technical details
 	Suggestions []string  `json:"suggestions,omitempty"`  // Suggested actions to resolve the error
 	Timestamp   string    `json:"timestamp,omitempty"`    // When the error occurred
+	Severity    Severity  `json:"severity,omitempty"`     // Error or warning; an empty severity is an error
 }
 
 // Error implements the error interface for NutritionalError
 func (ne NutritionalError) Error() string {
+	kind := "error"
+	if ne.Severity == SeverityWarning {
+		kind = "warning"
+	}
 	if ne.Field != "" {
-		return fmt.Sprintf("%s error in field '%s': %s", ne.Type, ne.Field, ne.Message)
+		return fmt.Sprintf("%s %s in field '%s': %s", ne.Type, kind, ne.Field, ne.Message)
 	}
-	return fmt.Sprintf("%s error: %s", ne.Type, ne.Message)
+	return fmt.Sprintf("%s %s: %s", ne.Type, kind, ne.Message)
 }
 
 // NewValidationError creates a new validation error with helpful context
@@ -186,6 +216,37 @@ func (ec *ErrorCollection) AddError(err NutritionalError) {
 	ec.ErrorCount++
 }
 
+// AddWarning adds a non-critical warning to the collection
+func (ec *ErrorCollection) AddWarning(err NutritionalError) {
+	err.Severity = SeverityWarning
+	ec.Errors = append(ec.Errors, err)
+	ec.WarningCount++
+}
+
+// AddValidationError adds a validation result as an error or a warning depending on its severity
+// The summary is the message of the first error
+func (ec *ErrorCollection) AddValidationError(ve ValidationError) {
+	if ve.IsWarning() {
+		ec.AddWarning(ve.ToNutritionalError())
+		return
+	}
+	if ec.ErrorCount == 0 {
+		ec.Summary = ve.Message
+	}
+	ec.AddError(ve.ToNutritionalError())
+}
+
+// GetWarnings returns all warnings in the collection
+func (ec ErrorCollection) GetWarnings() []NutritionalError {
+	var filtered []NutritionalError
+	for _, err := range ec.Errors {
+		if err.Severity == SeverityWarning {
+			filtered = append(filtered, err)
+		}
+	}
+	return filtered
+}
+
 // GetErrorsByType returns all errors of a specific type
 func (ec ErrorCollection) GetErrorsByType(errorType ErrorType) []NutritionalError {
 	var filtered []NutritionalError
@@ -11,6 +11,10 @@ type NutritionalScorer interface {
 	// Returns the complete score breakdown including positive/negative points and letter grade
 	CalculateScore(data NutritionalData, foodType ScoreType) (NutritionalScore, error)
 	
+	// Explain computes the score and describes in plain language how each nutrient contributed
+	// The explanation can be embedded in analyses for display and export
+	Explain(data NutritionalData, foodType ScoreType) (ScoreExplanation, error)
+	
 	// ValidateNutritionalData checks if the provided nutritional data is within acceptable ranges
 	// Returns a slice of validation errors if any values are invalid
 	ValidateNutritionalData(data NutritionalData) []ValidationError
@@ -19,9 +23,16 @@ type NutritionalScorer interface {
 	// Lower scores get better grades (A is best, E is worst)
 	GetScoreGrade(score int) string
 	
-	// GetScoreThresholds returns the score thresholds for each letter grade
+	// GetScoreThresholds returns the score thresholds for each letter grade per score type
 	// Used for displaying grade boundaries to users
-	GetScoreThresholds() map[string]int
+	GetScoreThresholds() map[ScoreType]map[string]int
+}
+
+// WarningLabelEvaluator defines the interface for market-specific warning-label regulations
+// Each evaluator decides which front-of-pack warning seals a food must carry
+type WarningLabelEvaluator interface {
+	// Evaluate returns the seals the food must carry, with the values that triggered them
+	Evaluate(food Food) (WarningLabelResult, error)
 }
 
 // ScoreCalculator defines the interface for detailed score calculation logic
@@ -36,8 +47,16 @@ type ScoreCalculator interface {
 	CalculatePositivePoints(data NutritionalData, foodType ScoreType) int
 	
 	// GetFinalScore combines negative and positive points according to Nutri-Score rules
-	// Different food types may have different calculation rules
+	// The positive points must only include the components that count for the food type
 	GetFinalScore(negative, positive int, foodType ScoreType) int
+	
+	// CalculateBreakdown scores every nutrient individually
+	// Rules such as the protein exclusion need to know which nutrient each point came from
+	CalculateBreakdown(data NutritionalData, foodType ScoreType) ScoreBreakdown
+	
+	// GetFinalScoreFromBreakdown combines the individual components according to Nutri-Score rules
+	// This is the only way to apply rules that depend on specific components
+	GetFinalScoreFromBreakdown(breakdown ScoreBreakdown, foodType ScoreType) int
 }
 
 // FoodDatabase defines the interface for food database operations
@@ -87,6 +106,38 @@ type UserFoodRepository interface {
 	SearchUserFoods(ctx context.Context, query string) ([]Food, error)
 }
 
+// RecipeRepository defines the interface for recipe management
+// Recipes are stored alongside user-defined foods
+type RecipeRepository interface {
+	// SaveRecipe stores a new recipe or updates an existing one
+	SaveRecipe(ctx context.Context, recipe Recipe) error
+	
+	// GetRecipes retrieves all stored recipes
+	GetRecipes(ctx context.Context) ([]Recipe, error)
+	
+	// GetRecipeByID retrieves a specific recipe by ID
+	GetRecipeByID(ctx context.Context, id string) (Recipe, error)
+	
+	// DeleteRecipe removes a recipe from storage
+	DeleteRecipe(ctx context.Context, id string) error
+}
+
+// DiaryRepository defines the interface for food diary persistence
+// Entries reference foods by ID and are summarised per meal and per day
+type DiaryRepository interface {
+	// SaveEntry stores a new diary entry or updates an existing one
+	SaveEntry(ctx context.Context, entry DiaryEntry) error
+	
+	// GetEntries retrieves diary entries with optional filtering, ordered by the time they were eaten
+	GetEntries(ctx context.Context, filter DiaryFilter) ([]DiaryEntry, error)
+	
+	// GetEntryByID retrieves a specific diary entry by its ID
+	GetEntryByID(ctx context.Context, id string) (DiaryEntry, error)
+	
+	// DeleteEntry removes a diary entry from storage
+	DeleteEntry(ctx context.Context, id string) error
+}
+
 // StorageService defines the interface for data persistence operations
 // This interface handles all file-based storage operations
 type StorageService interface {
@@ -198,6 +249,20 @@ type InputValidator interface {
 	// ValidateNutritionalData validates nutritional data against defined rules
 	ValidateNutritionalData(data NutritionalData) []ValidationError
 	
+	// CheckNutritionalData sorts the validation results into errors and warnings
+	CheckNutritionalData(data NutritionalData) ErrorCollection
+	
+	// CheckNutritionalDataFor sorts the validation results into errors and warnings using
+	// the validation profile of a score type
+	CheckNutritionalDataFor(data NutritionalData, scoreType ScoreType) ErrorCollection
+	
+	// CheckFoodData sorts the validation results of a food's nutritional data into errors and
+	// warnings using the validation profile of its category, or of solids or liquids
+	CheckFoodData(food Food) ErrorCollection
+	
+	// RulesFor returns the validation rules of a food category scored as a score type
+	RulesFor(category string, scoreType ScoreType) NutritionalDataValidation
+	
 	// ValidateFood validates a complete food item
 	ValidateFood(food Food) []ValidationError
 	
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 )
 
@@ -13,6 +14,7 @@ const (
 	BeverageType                  // Liquid beverages
 	WaterType                     // Water (special case with no scoring)
 	CheeseType                    // Cheese products (may have different scoring rules)
+	FatsOilsType                  // Added fats, oils, nuts and seeds (saturated fat scored as a ratio of total fat)
 )
 
 // String returns the string representation of ScoreType for better display
@@ -26,6 +28,8 @@ func (st ScoreType) String() string {
 		return "Water"
 	case CheeseType:
 		return "Cheese"
+	case FatsOilsType:
+		return "Fats/Oils"
 	default:
 		return "Unknown"
 	}
@@ -34,11 +38,108 @@ func (st ScoreType) String() string {
 // NutritionalScore holds the calculated nutritional score and its components
 // This struct contains the final score calculation results and breakdown
 type NutritionalScore struct {
-	Value     int       `json:"value"`      // Final calculated score (negative - positive)
-	Grade     string    `json:"grade"`      // Letter grade (A, B, C, D, E)
-	Positive  int       `json:"positive"`   // Sum of positive nutritional points (beneficial nutrients)
-	Negative  int       `json:"negative"`   // Sum of negative nutritional points (nutrients to limit)
-	ScoreType ScoreType `json:"score_type"` // Category of the food/beverage being scored
+	Value            int                `json:"value"`                       // Final calculated score (negative - positive)
+	Grade            string             `json:"grade"`                       // Letter grade (A, B, C, D, E)
+	Positive         int                `json:"positive"`                    // Sum of positive nutritional points (beneficial nutrients)
+	Negative         int                `json:"negative"`                    // Sum of negative nutritional points (nutrients to limit)
+	ScoreType        ScoreType          `json:"score_type"`                  // Category of the food/beverage being scored
+	AlgorithmVersion string             `json:"algorithm_version,omitempty"` // Nutri-Score algorithm version that produced the score (e.g. "2017", "2023-food")
+	RulesetChecksum  string             `json:"ruleset_checksum,omitempty"`  // Checksum of the ruleset whose thresholds produced the score
+	Scheme           string             `json:"scheme,omitempty"`            // Scoring scheme that produced the score (see SchemeNutriScore, SchemeHealthStarRating)
+	Stars            float64            `json:"stars,omitempty"`             // Health Star Rating from 0.5 to 5 (HSR scheme only)
+	Breakdown        ScoreBreakdown     `json:"breakdown"`                   // Points awarded for each nutrient
+	Warnings         []NutritionalError `json:"warnings,omitempty"`          // Implausible values that did not stop the scoring
+}
+
+// Scoring schemes that can produce a NutritionalScore
+const (
+	SchemeNutriScore       = "nutriscore" // French/EU Nutri-Score (grades A to E)
+	SchemeHealthStarRating = "hsr"        // Australian/New Zealand Health Star Rating (0.5 to 5 stars)
+	SchemeUKNPM            = "uk-npm"     // UK Nutrient Profiling Model 2004/05 (HFSS classification)
+)
+
+// ComponentScore holds the points awarded for a single nutrient
+// The band describes the threshold range the value fell into: values above BandLower
+// and up to BandUpper earn the same points (the first band starts at 0)
+// Inclusive bands start at BandLower and stop below BandUpper instead
+type ComponentScore struct {
+	Value         float64  `json:"value"`                    // Nutrient amount that was scored
+	Points        int      `json:"points"`                   // Points awarded for this nutrient
+	MaxPoints     int      `json:"max_points"`               // Maximum points the nutrient can earn
+	BandLower     float64  `json:"band_lower"`               // Lower bound of the band (exclusive, except for the first band)
+	BandUpper     *float64 `json:"band_upper,omitempty"`     // Upper bound of the band (inclusive), nil for the top band
+	BandInclusive bool     `json:"band_inclusive,omitempty"` // Lower bound inclusive and upper bound exclusive
+}
+
+// ScoreBreakdown contains the points awarded for every scored nutrient
+// Negative components count against the product, positive components in its favour
+type ScoreBreakdown struct {
+	Energy       ComponentScore `json:"energy"`        // Negative: energy points
+	Sugars       ComponentScore `json:"sugars"`        // Negative: sugar points
+	SaturatedFat ComponentScore `json:"saturated_fat"` // Negative: saturated fat points
+	Sodium       ComponentScore `json:"sodium"`        // Negative: sodium points
+	Fruits       ComponentScore `json:"fruits"`        // Positive: fruits/vegetables/nuts points
+	Fibre        ComponentScore `json:"fibre"`         // Positive: fiber points
+	Protein      ComponentScore `json:"protein"`       // Positive: protein points
+
+	// ProteinExcluded is true when the protein points do not count towards the score
+	// (foods with too many negative points and too little fruit, see the Nutri-Score rules)
+	ProteinExcluded bool `json:"protein_excluded,omitempty"`
+
+	// FruitOnly is true when only the fruit points count towards the score
+	// (beverages scored with algorithms that ignore their fibre and protein)
+	FruitOnly bool `json:"fruit_only,omitempty"`
+
+	// SweetenerPoints are the negative points added for non-nutritive sweeteners in beverages
+	SweetenerPoints int `json:"sweetener_points,omitempty"`
+}
+
+// NegativePoints returns the sum of the negative components
+func (sb ScoreBreakdown) NegativePoints() int {
+	return sb.Energy.Points + sb.Sugars.Points + sb.SaturatedFat.Points + sb.Sodium.Points + sb.SweetenerPoints
+}
+
+// PositivePoints returns the sum of the positive components that count towards the score
+func (sb ScoreBreakdown) PositivePoints() int {
+	if sb.FruitOnly {
+		return sb.Fruits.Points
+	}
+	if sb.ProteinExcluded {
+		return sb.Fruits.Points + sb.Fibre.Points
+	}
+	return sb.Fruits.Points + sb.Fibre.Points + sb.Protein.Points
+}
+
+// ScoreExplanation describes in plain language why a product received its score
+// The same content is available as a narrative and as structured fields for JSON exports
+type ScoreExplanation struct {
+	Value            int                    `json:"value"`                       // Final score that is explained
+	Grade            string                 `json:"grade"`                       // Letter grade that is explained
+	ScoreType        ScoreType              `json:"score_type"`                  // Category the product was scored as
+	AlgorithmVersion string                 `json:"algorithm_version,omitempty"` // Algorithm version that produced the score
+	Summary          string                 `json:"summary"`                     // One-line summary of the score
+	Components       []ComponentExplanation `json:"components"`                  // Explanation of every scored nutrient
+	Notes            []string               `json:"notes,omitempty"`             // Rules that changed the score (exclusions, penalties)
+	Narrative        string                 `json:"narrative"`                   // Summary, components and notes as readable text
+}
+
+// ComponentExplanation describes how a single nutrient was scored
+// Improvement is set when changing the nutrient would gain at least one point
+type ComponentExplanation struct {
+	Nutrient    string            `json:"nutrient"`              // Breakdown key (e.g. "sugars")
+	Label       string            `json:"label"`                 // Display name (e.g. "Sugars")
+	Unit        string            `json:"unit"`                  // Unit of the scored value (e.g. "g")
+	Negative    bool              `json:"negative"`              // True for nutrients to limit
+	Counted     bool              `json:"counted"`               // False when the points do not count towards the score
+	Score       ComponentScore    `json:"score"`                 // Points and band the nutrient fell into
+	Improvement *ScoreImprovement `json:"improvement,omitempty"` // Nearest change that would gain a point
+	Text        string            `json:"text"`                  // Readable explanation of the component
+}
+
+// ScoreImprovement describes the nearest change to a nutrient that improves the score
+type ScoreImprovement struct {
+	TargetValue float64 `json:"target_value"` // Value to reach (at most for negative, more than for positive nutrients)
+	PointsGain  int     `json:"points_gain"`  // Number of points the change would gain
 }
 
 // EnergyKJ represents energy content in kilojoules
@@ -57,6 +158,44 @@ type SaturatedFattyAcids float64
 // Higher sodium content contributes to negative (unhealthy) points
 type SodiumMilligram float64
 
+// SaltGram represents salt content in grams, as declared on EU labels
+// Salt is converted to sodium for scoring (salt = sodium x 2.5)
+type SaltGram float64
+
+// SaltToSodiumFactor is the EU conversion factor between sodium and salt (Regulation 1169/2011)
+const SaltToSodiumFactor = 2.5
+
+// ToSodium converts a salt amount to sodium in milligrams
+func (s SaltGram) ToSodium() SodiumMilligram {
+	return SodiumMilligram(float64(s) * 1000 / SaltToSodiumFactor)
+}
+
+// ToSalt converts a sodium amount to salt in grams
+func (s SodiumMilligram) ToSalt() SaltGram {
+	return SaltGram(float64(s) * SaltToSodiumFactor / 1000)
+}
+
+// SodiumDeclaration records which of salt or sodium the label declared
+// The declared value is kept as entered, the other one is derived from it
+type SodiumDeclaration string
+
+const (
+	DeclaredAsSodium SodiumDeclaration = "sodium" // Sodium in mg was declared
+	DeclaredAsSalt   SodiumDeclaration = "salt"   // Salt in g was declared
+)
+
+// TotalFatGram represents total fat (lipid) content in grams
+// Used to score saturated fat as a ratio of total fat for fats and oils
+type TotalFatGram float64
+
+// TransFatGram represents trans fatty acid content in grams
+// Used by warning-label regulations that limit the energy from trans fat
+type TransFatGram float64
+
+// CarbohydrateGram represents available carbohydrate content in grams, including sugars
+// Not scored; used to check sugars and declared energy for consistency
+type CarbohydrateGram float64
+
 // FruitsPercent represents the percentage of fruits/vegetables/nuts
 // Higher fruit/vegetable content contributes to positive (healthy) points
 type FruitsPercent float64
@@ -72,39 +211,158 @@ type ProteinGram float64
 // NutritionalData contains all the nutritional information needed for scoring
 // This struct holds the complete nutritional profile of a food item per 100g
 type NutritionalData struct {
-	Energy              EnergyKJ            `json:"energy"`                // Energy content in kJ per 100g
-	Sugars              SugarGram           `json:"sugars"`                // Sugar content in grams per 100g
-	SaturatedFattyAcids SaturatedFattyAcids `json:"saturated_fatty_acids"` // Saturated fat content in grams per 100g
-	Sodium              SodiumMilligram     `json:"sodium"`                // Sodium content in milligrams per 100g
-	Fruits              FruitsPercent       `json:"fruits"`                // Fruits/vegetables/nuts percentage
-	Fibre               FibreGram           `json:"fibre"`                 // Fiber content in grams per 100g
-	Protein             ProteinGram         `json:"protein"`               // Protein content in grams per 100g
+	Energy                 EnergyKJ            `json:"energy"`                             // Energy content in kJ per 100g
+	Sugars                 SugarGram           `json:"sugars"`                             // Sugar content in grams per 100g
+	SaturatedFattyAcids    SaturatedFattyAcids `json:"saturated_fatty_acids"`              // Saturated fat content in grams per 100g
+	TotalFat               TotalFatGram        `json:"total_fat,omitempty"`                // Total fat content in grams per 100g
+	TransFat               TransFatGram        `json:"trans_fat,omitempty"`                // Trans fat content in grams per 100g
+	Carbohydrate           CarbohydrateGram    `json:"carbohydrate,omitempty"`             // Carbohydrate content in grams per 100g, including sugars
+	Sodium                 SodiumMilligram     `json:"sodium"`                             // Sodium content in milligrams per 100g
+	Salt                   SaltGram            `json:"salt,omitempty"`                     // Salt content in grams per 100g (alternative to sodium)
+	SodiumDeclaredAs       SodiumDeclaration   `json:"sodium_declared_as,omitempty"`       // Whether the label declared salt or sodium
+	Fruits                 FruitsPercent       `json:"fruits"`                             // Fruits/vegetables/nuts percentage
+	Fibre                  FibreGram           `json:"fibre"`                              // Fiber content in grams per 100g
+	Protein                ProteinGram         `json:"protein"`                            // Protein content in grams per 100g
+	RedMeat                bool                `json:"red_meat,omitempty"`                 // True for red meat products (protein points are capped from the 2023 algorithm on)
+	NonNutritiveSweeteners bool                `json:"non_nutritive_sweeteners,omitempty"` // True if the product contains non-nutritive sweeteners (penalised for beverages from the 2023 algorithm on)
+	Unknown                []string            `json:"unknown,omitempty"`                  // Nutrients whose value is not known ("fibre", "fruits", ...), as opposed to declared as 0
+}
+
+// IsUnknown reports whether the value of a nutrient ("fibre", "fruits", ...) is not known
+func (nd NutritionalData) IsUnknown(nutrient string) bool {
+	for _, unknown := range nd.Unknown {
+		if unknown == nutrient {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveSodium fills in whichever of salt and sodium was not declared
+// The declared value is left untouched and recorded in SodiumDeclaredAs; when both
+// values are given they are kept as they are
+func (nd NutritionalData) ResolveSodium() NutritionalData {
+	switch {
+	case nd.Salt > 0 && nd.Sodium == 0:
+		nd.Sodium = nd.Salt.ToSodium()
+		if nd.SodiumDeclaredAs == "" {
+			nd.SodiumDeclaredAs = DeclaredAsSalt
+		}
+	case nd.Sodium > 0 && nd.Salt == 0:
+		nd.Salt = nd.Sodium.ToSalt()
+		if nd.SodiumDeclaredAs == "" {
+			nd.SodiumDeclaredAs = DeclaredAsSodium
+		}
+	}
+	return nd
+}
+
+// DeclaredSodiumValue returns the salt or sodium value as it appeared on the label, with its unit
+func (nd NutritionalData) DeclaredSodiumValue() (float64, string) {
+	if nd.SodiumDeclaredAs == DeclaredAsSalt {
+		return float64(nd.Salt), "g salt"
+	}
+	return float64(nd.Sodium), "mg sodium"
 }
 
 // Food represents a food item with its nutritional data and metadata
 // This struct can represent both database foods and user-defined foods
 type Food struct {
-	ID               string          `json:"id"`                 // Unique identifier for the food
-	Name             string          `json:"name"`               // Display name of the food
-	Category         string          `json:"category"`           // Food category (e.g., "Fruits", "Dairy", "Grains")
-	Brand            string          `json:"brand,omitempty"`    // Brand name (optional, for packaged foods)
-	NutritionalData  NutritionalData `json:"nutritional_data"`   // Complete nutritional profile
-	IsUserDefined    bool            `json:"is_user_defined"`    // True if created by user, false if from database
-	CreatedAt        time.Time       `json:"created_at"`         // When the food was added to the system
-	UpdatedAt        time.Time       `json:"updated_at"`         // When the food was last modified
-	Source           string          `json:"source,omitempty"`   // Data source (e.g., "USDA", "User Input")
+	ID              string                `json:"id"`                    // Unique identifier for the food
+	Name            string                `json:"name"`                  // Display name of the food
+	Category        string                `json:"category"`              // Food category (e.g., "Fruits", "Dairy", "Grains")
+	Brand           string                `json:"brand,omitempty"`       // Brand name (optional, for packaged foods)
+	NutritionalData NutritionalData       `json:"nutritional_data"`      // Complete nutritional profile
+	IsUserDefined   bool                  `json:"is_user_defined"`       // True if created by user, false if from database
+	CreatedAt       time.Time             `json:"created_at"`            // When the food was added to the system
+	UpdatedAt       time.Time             `json:"updated_at"`            // When the food was last modified
+	Source          string                `json:"source,omitempty"`      // Data source (e.g., "USDA", "User Input")
+	Liquid          bool                  `json:"liquid,omitempty"`      // True if the nutritional data is declared per 100ml
+	Declaration     *NutritionDeclaration `json:"declaration,omitempty"` // Original label declaration when it was not per 100g or 100ml
+}
+
+// IsLiquid reports whether the food's nutritional data is declared per 100ml
+// Foods in the "Beverages" category are liquids even without the Liquid flag
+func (f Food) IsLiquid() bool {
+	return f.Liquid || strings.EqualFold(f.Category, "Beverages")
+}
+
+// DeclarationBasis is the quantity a label declares its nutrition values for
+type DeclarationBasis string
+
+const (
+	Per100     DeclarationBasis = "100"     // Values per 100g or 100ml
+	PerServing DeclarationBasis = "serving" // Values per declared serving
+	PerPack    DeclarationBasis = "pack"    // Values per whole pack
+)
+
+// ServingUnit is the unit a serving or pack size is declared in
+type ServingUnit string
+
+const (
+	Grams       ServingUnit = "g"  // Serving size in grams
+	Millilitres ServingUnit = "ml" // Serving size in millilitres
+)
+
+// NutritionDeclaration keeps the nutrition values as they were declared on the label
+// Values holds the amounts for one serving or one pack; the fruit percentage and the
+// flags do not depend on the amount
+type NutritionDeclaration struct {
+	Basis   DeclarationBasis `json:"basis"`             // Quantity the values are declared for
+	Size    float64          `json:"size,omitempty"`    // Serving or pack size in Unit
+	Unit    ServingUnit      `json:"unit,omitempty"`    // Unit of the serving or pack size
+	Density float64          `json:"density,omitempty"` // Density in g per ml, needed when the size is not in the unit of the per 100 basis
+	Values  NutritionalData  `json:"values"`            // Nutrition values as declared
+}
+
+// RecipeIngredient references a food by ID with the weight used in a recipe
+type RecipeIngredient struct {
+	FoodID string  `json:"food_id"` // ID of a database or user-defined food
+	Grams  float64 `json:"grams"`   // Raw weight of the ingredient in grams
+}
+
+// Recipe is a dish made of weighted ingredients
+// Its nutritional data per 100g is derived from the ingredients and the cooking yield
+type Recipe struct {
+	ID          string             `json:"id"`                     // Unique identifier for the recipe
+	Name        string             `json:"name"`                   // Display name of the dish
+	Category    string             `json:"category,omitempty"`     // Food category of the finished dish
+	ScoreType   ScoreType          `json:"score_type"`             // Category the dish is scored as
+	Ingredients []RecipeIngredient `json:"ingredients"`            // Ingredients with their raw weights
+	YieldFactor float64            `json:"yield_factor,omitempty"` // Cooked weight / raw weight (e.g. 0.8 for 20% cooking loss), 0 means no change
+	CreatedAt   time.Time          `json:"created_at"`             // When the recipe was created
+	UpdatedAt   time.Time          `json:"updated_at"`             // When the recipe was last modified
+}
+
+// RawWeight returns the total weight of the ingredients in grams
+func (r Recipe) RawWeight() float64 {
+	total := 0.0
+	for _, ingredient := range r.Ingredients {
+		total += ingredient.Grams
+	}
+	return total
+}
+
+// CookedWeight returns the weight of the finished dish in grams after applying the yield factor
+func (r Recipe) CookedWeight() float64 {
+	if r.YieldFactor <= 0 {
+		return r.RawWeight()
+	}
+	return r.RawWeight() * r.YieldFactor
 }
 
 // NutritionalAnalysis represents a complete analysis of a food item
 // This struct contains the food data, calculated score, and analysis metadata
 type NutritionalAnalysis struct {
-	ID              string           `json:"id"`               // Unique identifier for this analysis
-	Food            Food             `json:"food"`             // The food item that was analyzed
-	Score           NutritionalScore `json:"score"`            // Calculated nutritional score and breakdown
-	AnalyzedAt      time.Time        `json:"analyzed_at"`      // When the analysis was performed
-	Notes           string           `json:"notes,omitempty"`  // Optional user notes about the analysis
-	ServingSize     float64          `json:"serving_size"`     // Serving size in grams (default 100g)
-	UserID          string           `json:"user_id,omitempty"` // User who performed the analysis (for multi-user systems)
+	ID            string             `json:"id"`                       // Unique identifier for this analysis
+	Food          Food               `json:"food"`                     // The food item that was analyzed
+	Score         NutritionalScore   `json:"score"`                    // Calculated nutritional score and breakdown
+	AnalyzedAt    time.Time          `json:"analyzed_at"`              // When the analysis was performed
+	Notes         string             `json:"notes,omitempty"`          // Optional user notes about the analysis
+	ServingSize   float64            `json:"serving_size"`             // Serving size in grams (default 100g)
+	UserID        string             `json:"user_id,omitempty"`        // User who performed the analysis (for multi-user systems)
+	Explanation   *ScoreExplanation  `json:"explanation,omitempty"`    // Optional explanation of the score for display and export
+	TrafficLights *TrafficLightLabel `json:"traffic_lights,omitempty"` // Optional UK traffic light label for display and export
 }
 
 // FoodComparison represents a comparison between multiple food items
@@ -120,6 +378,237 @@ type FoodComparison struct {
 	UserID          string                `json:"user_id,omitempty"` // User who performed the comparison
 }
 
+// NutrientBound limits the values a nutrient may take during reformulation
+// A nil Min or Max leaves that side unbounded (apart from the validation ranges)
+type NutrientBound struct {
+	Min *float64 `json:"min,omitempty"` // Lowest acceptable value per 100g
+	Max *float64 `json:"max,omitempty"` // Highest acceptable value per 100g
+}
+
+// ReformulationOptions describes the goal and constraints of a reformulation search
+// Nutrients are named by their breakdown keys ("energy", "sugars", "saturated_fat",
+// "sodium", "fruits", "fibre", "protein")
+type ReformulationOptions struct {
+	TargetGrade string                   `json:"target_grade"`     // Grade to reach (or better)
+	Bounds      map[string]NutrientBound `json:"bounds,omitempty"` // Per-nutrient limits on the new values
+	Locked      []string                 `json:"locked,omitempty"` // Nutrients that must not change
+}
+
+// NutrientChange describes a single change proposed by the reformulation advisor
+type NutrientChange struct {
+	Nutrient   string  `json:"nutrient"`    // Breakdown key of the nutrient
+	Unit       string  `json:"unit"`        // Unit of the values (e.g. "g")
+	From       float64 `json:"from"`        // Current value per 100g
+	To         float64 `json:"to"`          // Proposed value per 100g
+	PointsGain int     `json:"points_gain"` // Points gained on this nutrient's component
+}
+
+// ReformulationPlan is the smallest set of changes found to reach a target grade
+// Achievable is false when no combination within the bounds reaches the grade
+type ReformulationPlan struct {
+	TargetGrade  string           `json:"target_grade"`           // Grade that was requested
+	Achievable   bool             `json:"achievable"`             // True if the target grade can be reached
+	Truncated    bool             `json:"truncated,omitempty"`    // True if the search limit was reached; the changes reach the target but may not be the smallest
+	Changes      []NutrientChange `json:"changes,omitempty"`      // Proposed changes (empty if already at the target)
+	Original     NutritionalScore `json:"original"`               // Score of the product as it is
+	Reformulated NutritionalScore `json:"reformulated,omitempty"` // Score after applying the changes
+	Data         NutritionalData  `json:"data"`                   // Nutritional data after applying the changes
+}
+
+// NutrientSensitivity describes how close a nutrient is to the edges of its point band
+// Distances are in the units the nutrient is scored in; a nil distance means there is
+// no band in that direction
+type NutrientSensitivity struct {
+	Nutrient             string   `json:"nutrient"`                          // Breakdown key of the nutrient
+	Unit                 string   `json:"unit"`                              // Unit of the scored value and distances
+	Value                float64  `json:"value"`                             // Scored value
+	Points               int      `json:"points"`                            // Points awarded for the value
+	DistanceToLowerBand  *float64 `json:"distance_to_lower_band,omitempty"`  // Decrease needed to reach the lower band
+	DistanceToHigherBand *float64 `json:"distance_to_higher_band,omitempty"` // Increase beyond which the higher band starts
+	Tolerance            float64  `json:"tolerance"`                         // Labelling tolerance for the declared value (0 if none applies)
+	MinPoints            int      `json:"min_points"`                        // Lowest points within the tolerance
+	MaxPoints            int      `json:"max_points"`                        // Highest points within the tolerance
+	GradeFlip            bool     `json:"grade_flip"`                        // True if a change within tolerance changes the grade
+	FlipGrades           []string `json:"flip_grades,omitempty"`             // Grades reachable within the tolerance, best first
+}
+
+// SensitivityReport shows how robust a score is to small changes of each nutrient
+type SensitivityReport struct {
+	Score          NutritionalScore      `json:"score"`           // Score of the declared values
+	Nutrients      []NutrientSensitivity `json:"nutrients"`       // Sensitivity of every scored nutrient
+	GradeCanFlip   bool                  `json:"grade_can_flip"`  // True if any change within tolerance changes the grade
+	PossibleGrades []string              `json:"possible_grades"` // Grades reachable when all nutrients vary within tolerance, best first
+}
+
+// ToleranceScore is a score evaluated at both ends of the EU labelling tolerances
+// A grade that is not robust could be challenged by a lab test within tolerance
+type ToleranceScore struct {
+	Score         NutritionalScore `json:"score"`          // Score of the declared values
+	BestCase      NutritionalScore `json:"best_case"`      // Every nutrient at its most favourable value within tolerance
+	WorstCase     NutritionalScore `json:"worst_case"`     // Every nutrient at its least favourable value within tolerance
+	Grades        []string         `json:"grades"`         // Grades from the best to the worst case
+	GradeInterval string           `json:"grade_interval"` // Best to worst grade ("B–C"), or the grade alone when it is robust
+	Robust        bool             `json:"robust"`         // True if every value within tolerance gives the same grade
+}
+
+// TrafficLightColour is the colour of a UK front-of-pack traffic light
+type TrafficLightColour string
+
+const (
+	TrafficLightGreen TrafficLightColour = "green" // Low
+	TrafficLightAmber TrafficLightColour = "amber" // Medium
+	TrafficLightRed   TrafficLightColour = "red"   // High
+)
+
+// NutrientTrafficLight is the traffic light of a single nutrient
+// Colour is the per 100g colour unless a per-portion red override applies
+type NutrientTrafficLight struct {
+	Nutrient               string             `json:"nutrient"`                           // Nutrient key ("fat", "saturates", "sugars", "salt")
+	Label                  string             `json:"label"`                              // Display name as printed on the label
+	Per100g                float64            `json:"per_100g"`                           // Amount in grams per 100g (or 100ml)
+	PerPortion             *float64           `json:"per_portion,omitempty"`              // Amount in grams per portion, nil without a serving size
+	ReferenceIntakePercent *float64           `json:"reference_intake_percent,omitempty"` // Share of the adult reference intake per portion
+	Per100gColour          TrafficLightColour `json:"per_100g_colour"`                    // Colour from the per 100g criteria
+	Colour                 TrafficLightColour `json:"colour"`                             // Colour shown on the label
+	PortionOverride        bool               `json:"portion_override,omitempty"`         // True if the portion criteria turned the light red
+}
+
+// TrafficLightLabel holds the UK front-of-pack traffic lights of a product
+type TrafficLightLabel struct {
+	ScoreType   ScoreType              `json:"score_type"`             // Category the product was evaluated as (drinks use drink criteria)
+	ServingSize float64                `json:"serving_size,omitempty"` // Portion size in grams or ml (0 if not given)
+	Lights      []NutrientTrafficLight `json:"lights"`                 // Fat, saturates, sugars and salt, in label order
+}
+
+// Light returns the traffic light of a nutrient ("fat", "saturates", "sugars", "salt")
+func (tl TrafficLightLabel) Light(nutrient string) (NutrientTrafficLight, bool) {
+	for _, light := range tl.Lights {
+		if light.Nutrient == nutrient {
+			return light, true
+		}
+	}
+	return NutrientTrafficLight{}, false
+}
+
+// HFSSVerdict is the UK Nutrient Profiling Model classification of a product
+// Products at or above the cut-off are high in fat, salt or sugar (HFSS, "less healthy")
+type HFSSVerdict struct {
+	Score     NutritionalScore `json:"score"`     // Nutrient profiling score and breakdown
+	Drink     bool             `json:"drink"`     // True if the drink cut-off was applied
+	Threshold int              `json:"threshold"` // Score from which the product is HFSS (4 for foods, 1 for drinks)
+	HFSS      bool             `json:"hfss"`      // True if the product is "less healthy"
+	Compliant bool             `json:"compliant"` // True if the product may be advertised without HFSS restrictions
+	Reasons   []string         `json:"reasons"`   // Why the product received its verdict
+}
+
+// WarningSeal is a front-of-pack warning a product must carry in a market
+// Value is the amount that triggered the seal, compared with Limit in the same unit
+type WarningSeal struct {
+	Seal     string  `json:"seal"`     // Text of the seal as printed (e.g. "ALTO EN AZÚCARES")
+	Nutrient string  `json:"nutrient"` // Nutrient key ("energy", "sugars", "saturated_fat", "sodium", "trans_fat")
+	Value    float64 `json:"value"`    // Value that triggered the seal
+	Limit    float64 `json:"limit"`    // Limit the value reached or exceeded
+	Unit     string  `json:"unit"`     // Unit of Value and Limit (e.g. "g per 100g", "% of energy")
+}
+
+// WarningLabelResult lists the warning seals a food must carry under a regulation
+type WarningLabelResult struct {
+	Market     string        `json:"market"`          // ISO country code of the market (e.g. "CL", "MX")
+	Regulation string        `json:"regulation"`      // Regulation that was applied
+	FoodID     string        `json:"food_id"`         // Food that was evaluated
+	Liquid     bool          `json:"liquid"`          // True if the liquid (per 100ml) limits were applied
+	Seals      []WarningSeal `json:"seals,omitempty"` // Seals the product must carry, empty if none
+}
+
+// ClaimResult is the eligibility of a food for one nutrition claim
+// Rule, Value, Limit and Margin describe the condition that decided the result; the
+// margin is how far the value is inside the limit (negative when the claim is not met)
+type ClaimResult struct {
+	Claim    string  `json:"claim"`    // Claim as worded in the regulation (e.g. "LOW FAT")
+	Nutrient string  `json:"nutrient"` // Nutrient the claim is about
+	Eligible bool    `json:"eligible"` // True if the food qualifies for the claim
+	Rule     string  `json:"rule"`     // Condition that was applied (e.g. "fat ≤ 3 g per 100g")
+	Value    float64 `json:"value"`    // Value of the food for the condition
+	Limit    float64 `json:"limit"`    // Limit of the condition
+	Unit     string  `json:"unit"`     // Unit of Value, Limit and Margin
+	Margin   float64 `json:"margin"`   // Distance to the limit, negative if the condition is not met
+}
+
+// ClaimReport lists the nutrition claims evaluated for a food
+type ClaimReport struct {
+	FoodID     string        `json:"food_id"`    // Food that was evaluated
+	Regulation string        `json:"regulation"` // Regulation that was applied
+	Liquid     bool          `json:"liquid"`     // True if the per 100ml conditions were applied
+	Claims     []ClaimResult `json:"claims"`     // Every claim that applies to the food, eligible or not
+}
+
+// EligibleClaims returns the claims the food qualifies for
+func (cr ClaimReport) EligibleClaims() []ClaimResult {
+	var eligible []ClaimResult
+	for _, claim := range cr.Claims {
+		if claim.Eligible {
+			eligible = append(eligible, claim)
+		}
+	}
+	return eligible
+}
+
+// ImputedValue records an unknown nutrient value that was estimated from reference foods
+type ImputedValue struct {
+	Nutrient string  `json:"nutrient"` // Nutrient that was unknown ("fibre", "fruits", ...)
+	Unit     string  `json:"unit"`     // Unit of the values
+	Value    float64 `json:"value"`    // Median of the reference foods, used for the score
+	Min      float64 `json:"min"`      // Lowest value among the reference foods
+	Max      float64 `json:"max"`      // Highest value among the reference foods
+	Source   string  `json:"source"`   // Reference foods of the estimate ("category Fruits", "all foods")
+	Samples  int     `json:"samples"`  // Number of reference foods
+}
+
+// ImputedScore is the score of a food whose unknown nutrient values were estimated
+// MinScore, MaxScore and Grades cover the unknown values anywhere in the range of the reference foods
+type ImputedScore struct {
+	Score      NutritionalScore `json:"score"`             // Score with the estimated values
+	Imputed    []ImputedValue   `json:"imputed,omitempty"` // Estimated values, empty if everything was known
+	Confidence float64          `json:"confidence"`        // Share of the scorable points that rest on known values (0 to 1)
+	MinScore   int              `json:"min_score"`         // Lowest score the unknown values could lead to
+	MaxScore   int              `json:"max_score"`         // Highest score the unknown values could lead to
+	Grades     []string         `json:"grades"`            // Possible grades, from the lowest to the highest score
+}
+
+// ServingNutrient is a nutrient amount per 100g (or 100ml) and per serving
+type ServingNutrient struct {
+	Nutrient   string  `json:"nutrient"`    // Nutrient name ("energy", "fat", "saturates", ...)
+	Unit       string  `json:"unit"`        // Unit of both amounts
+	Per100     float64 `json:"per_100"`     // Amount per 100g or 100ml
+	PerServing float64 `json:"per_serving"` // Amount per serving
+}
+
+// ServingTable lists the nutrients of a food per 100g (or 100ml) and per serving
+// for display and export next to each other
+type ServingTable struct {
+	FoodID      string            `json:"food_id"`         // Food the table describes
+	Liquid      bool              `json:"liquid"`          // True if the amounts are per 100ml
+	ServingSize float64           `json:"serving_size"`    // Serving size in g, or ml for liquids
+	Basis       DeclarationBasis  `json:"basis,omitempty"` // Basis of the original label declaration
+	Nutrients   []ServingNutrient `json:"nutrients"`       // Nutrients in label order
+}
+
+// ProfileReport consolidates every profiling scheme evaluated for one food
+// Schemes that could not evaluate the food record their error instead of a result
+type ProfileReport struct {
+	FoodID        string               `json:"food_id"`                  // Food that was profiled
+	FoodName      string               `json:"food_name,omitempty"`      // Display name of the food
+	ScoreType     ScoreType            `json:"score_type"`               // Category the food was profiled as
+	GeneratedAt   time.Time            `json:"generated_at"`             // When the report was produced
+	Nutrition     *ServingTable        `json:"nutrition,omitempty"`      // Nutrients per 100g and per serving, when a serving size is declared
+	Scores        []NutritionalScore   `json:"scores,omitempty"`         // Nutri-Score versions and Health Star Rating
+	TrafficLights *TrafficLightLabel   `json:"traffic_lights,omitempty"` // UK front-of-pack traffic lights
+	HFSS          *HFSSVerdict         `json:"hfss,omitempty"`           // UK Nutrient Profiling Model verdict
+	Claims        *ClaimReport         `json:"claims,omitempty"`         // EU nutrition claims
+	WarningLabels []WarningLabelResult `json:"warning_labels,omitempty"` // Warning seals per market
+	Errors        map[string]string    `json:"errors,omitempty"`         // Error message per scheme that failed
+}
+
 // HistoryFilter represents filtering options for analysis history
 // This struct is used to filter historical analyses by various criteria
 type HistoryFilter struct {
@@ -131,6 +620,59 @@ type HistoryFilter struct {
 	Limit        int        `json:"limit,omitempty"`         // Maximum number of results to return
 }
 
+// MealType identifies the meal a diary entry was eaten at
+type MealType string
+
+const (
+	Breakfast MealType = "breakfast" // First meal of the day
+	Lunch     MealType = "lunch"     // Midday meal
+	Dinner    MealType = "dinner"    // Evening meal
+	Snack     MealType = "snack"     // Anything eaten between meals
+)
+
+// Meals lists the meal types in the order they are reported
+var Meals = []MealType{Breakfast, Lunch, Dinner, Snack}
+
+// DiaryEntry records an amount of a food eaten at a meal
+type DiaryEntry struct {
+	ID        string    `json:"id"`              // Unique identifier for the entry
+	FoodID    string    `json:"food_id"`         // ID of a database or user-defined food
+	Grams     float64   `json:"grams"`           // Amount eaten in grams (ml for liquids)
+	Meal      MealType  `json:"meal"`            // Meal the food was eaten at
+	ScoreType ScoreType `json:"score_type"`      // Category the food is scored as
+	EatenAt   time.Time `json:"eaten_at"`        // When the food was eaten
+	Notes     string    `json:"notes,omitempty"` // Optional user notes
+}
+
+// DiaryFilter represents filtering options for diary entries
+type DiaryFilter struct {
+	StartDate *time.Time `json:"start_date,omitempty"` // Entries eaten at or after this time
+	EndDate   *time.Time `json:"end_date,omitempty"`   // Entries eaten before this time
+	Meal      MealType   `json:"meal,omitempty"`       // Filter by meal
+	Limit     int        `json:"limit,omitempty"`      // Maximum number of results to return
+}
+
+// IntakeSummary aggregates the diary entries of a meal or a whole day
+// Intake holds absolute amounts; its Fruits field is the weight in grams of fruit,
+// vegetables and nuts. Density holds the same amounts per 100g eaten
+type IntakeSummary struct {
+	Meal           MealType        `json:"meal,omitempty"`  // Meal summarised, empty for a whole day
+	Entries        int             `json:"entries"`         // Number of diary entries
+	Grams          float64         `json:"grams"`           // Total amount eaten in grams
+	Intake         NutritionalData `json:"intake"`          // Absolute nutrient amounts eaten
+	Density        NutritionalData `json:"density"`         // Nutrient amounts per 100g eaten
+	AverageScore   float64         `json:"average_score"`   // Score of the entries weighted by the energy they provided
+	EnergyWeighted bool            `json:"energy_weighted"` // False if nothing provided energy and the average is weighted by grams
+}
+
+// DailySummary aggregates a day of diary entries per meal and for the whole day
+type DailySummary struct {
+	Date     string                `json:"date"`     // Day in YYYY-MM-DD format
+	Total    IntakeSummary         `json:"total"`    // Aggregate of the whole day
+	Meals    []IntakeSummary       `json:"meals"`    // Aggregate per meal with entries, in meal order
+	Analyses []NutritionalAnalysis `json:"analyses"` // Scored entries, the serving size being the amount eaten
+}
+
 // ScoreRange represents a range of nutritional scores for filtering
 // Used to filter analyses by score values (e.g., only show healthy foods)
 type ScoreRange struct {
@@ -191,39 +733,101 @@ type ExportData struct {
 // NutritionalDataValidation contains validation rules for nutritional data
 // This struct defines the acceptable ranges for each nutritional component
 type NutritionalDataValidation struct {
-	EnergyMin              float64 `json:"energy_min"`                // Minimum energy in kJ per 100g
-	EnergyMax              float64 `json:"energy_max"`                // Maximum energy in kJ per 100g
-	SugarsMin              float64 `json:"sugars_min"`                // Minimum sugar in g per 100g
-	SugarsMax              float64 `json:"sugars_max"`                // Maximum sugar in g per 100g
-	SaturatedFatMin        float64 `json:"saturated_fat_min"`         // Minimum saturated fat in g per 100g
-	SaturatedFatMax        float64 `json:"saturated_fat_max"`         // Maximum saturated fat in g per 100g
-	SodiumMin              float64 `json:"sodium_min"`                // Minimum sodium in mg per 100g
-	SodiumMax              float64 `json:"sodium_max"`                // Maximum sodium in mg per 100g
-	FruitsMin              float64 `json:"fruits_min"`                // Minimum fruits percentage
-	FruitsMax              float64 `json:"fruits_max"`                // Maximum fruits percentage
-	FibreMin               float64 `json:"fibre_min"`                 // Minimum fiber in g per 100g
-	FibreMax               float64 `json:"fibre_max"`                 // Maximum fiber in g per 100g
-	ProteinMin             float64 `json:"protein_min"`               // Minimum protein in g per 100g
-	ProteinMax             float64 `json:"protein_max"`               // Maximum protein in g per 100g
+	EnergyMin           float64 `json:"energy_min"`            // Minimum energy in kJ per 100g
+	EnergyMax           float64 `json:"energy_max"`            // Maximum energy in kJ per 100g
+	SugarsMin           float64 `json:"sugars_min"`            // Minimum sugar in g per 100g
+	SugarsMax           float64 `json:"sugars_max"`            // Maximum sugar in g per 100g
+	SaturatedFatMin     float64 `json:"saturated_fat_min"`     // Minimum saturated fat in g per 100g
+	SaturatedFatMax     float64 `json:"saturated_fat_max"`     // Maximum saturated fat in g per 100g
+	TotalFatMin         float64 `json:"total_fat_min"`         // Minimum total fat in g per 100g
+	TotalFatMax         float64 `json:"total_fat_max"`         // Maximum total fat in g per 100g
+	SodiumMin           float64 `json:"sodium_min"`            // Minimum sodium in mg per 100g
+	SodiumMax           float64 `json:"sodium_max"`            // Maximum sodium in mg per 100g
+	SodiumWarning       float64 `json:"sodium_warning"`        // Sodium in mg per 100g above which a warning is given (0 disables)
+	SaltMin             float64 `json:"salt_min"`              // Minimum salt in g per 100g
+	SaltMax             float64 `json:"salt_max"`              // Maximum salt in g per 100g
+	SaltWarning         float64 `json:"salt_warning"`          // Salt in g per 100g above which a warning is given (0 disables)
+	TransFatMin         float64 `json:"trans_fat_min"`         // Minimum trans fat in g per 100g
+	TransFatMax         float64 `json:"trans_fat_max"`         // Maximum trans fat in g per 100g
+	CarbohydrateMin     float64 `json:"carbohydrate_min"`      // Minimum carbohydrate in g per 100g
+	CarbohydrateMax     float64 `json:"carbohydrate_max"`      // Maximum carbohydrate in g per 100g
+	EnergyTolerance     float64 `json:"energy_tolerance"`      // Allowed relative difference between declared and calculated energy (0 disables the check)
+	SaltSodiumTolerance float64 `json:"salt_sodium_tolerance"` // Allowed relative difference when both salt and sodium are given
+	FruitsMin           float64 `json:"fruits_min"`            // Minimum fruits percentage
+	FruitsMax           float64 `json:"fruits_max"`            // Maximum fruits percentage
+	FibreMin            float64 `json:"fibre_min"`             // Minimum fiber in g per 100g
+	FibreMax            float64 `json:"fibre_max"`             // Maximum fiber in g per 100g
+	FibreWarning        float64 `json:"fibre_warning"`         // Fiber in g per 100g above which a warning is given (0 disables)
+	ProteinMin          float64 `json:"protein_min"`           // Minimum protein in g per 100g
+	ProteinMax          float64 `json:"protein_max"`           // Maximum protein in g per 100g
 }
 
 // DefaultValidationRules returns the default validation rules for nutritional data
 // These rules are based on realistic ranges for food nutritional content
 func DefaultValidationRules() NutritionalDataValidation {
 	return NutritionalDataValidation{
-		EnergyMin:       0,     // 0 kJ per 100g (minimum)
-		EnergyMax:       4000,  // 4000 kJ per 100g (very high energy foods like oils)
-		SugarsMin:       0,     // 0g per 100g
-		SugarsMax:       100,   // 100g per 100g (pure sugar)
-		SaturatedFatMin: 0,     // 0g per 100g
-		SaturatedFatMax: 100,   // 100g per 100g (pure fat)
-		SodiumMin:       0,     // 0mg per 100g
-		SodiumMax:       10000, // 10000mg per 100g (very high sodium foods)
-		FruitsMin:       0,     // 0% fruits/vegetables/nuts
-		FruitsMax:       100,   // 100% fruits/vegetables/nuts
-		FibreMin:        0,     // 0g per 100g
-		FibreMax:        50,    // 50g per 100g (very high fiber foods)
-		ProteinMin:      0,     // 0g per 100g
-		ProteinMax:      100,   // 100g per 100g (pure protein)
-	}
-}
\ No newline at end of file
+		EnergyMin:           0,     // 0 kJ per 100g (minimum)
+		EnergyMax:           4000,  // 4000 kJ per 100g (very high energy foods like oils)
+		SugarsMin:           0,     // 0g per 100g
+		SugarsMax:           100,   // 100g per 100g (pure sugar)
+		SaturatedFatMin:     0,     // 0g per 100g
+		SaturatedFatMax:     100,   // 100g per 100g (pure fat)
+		TotalFatMin:         0,     // 0g per 100g
+		TotalFatMax:         100,   // 100g per 100g (pure fat)
+		SodiumMin:           0,     // 0mg per 100g
+		SodiumMax:           10000, // 10000mg per 100g (very high sodium foods)
+		SodiumWarning:       5000,  // 5000mg per 100g (only stock cubes, soy sauce and the like)
+		SaltMin:             0,     // 0g per 100g
+		SaltMax:             25,    // 25g per 100g (equivalent to the sodium maximum)
+		SaltWarning:         12.5,  // 12.5g per 100g (equivalent to the sodium warning)
+		SaltSodiumTolerance: 0.05,  // 5% to allow for label rounding
+		TransFatMin:         0,     // 0g per 100g
+		TransFatMax:         100,   // 100g per 100g (pure fat)
+		CarbohydrateMin:     0,     // 0g per 100g
+		CarbohydrateMax:     100,   // 100g per 100g (pure sugar or starch)
+		EnergyTolerance:     0.15,  // 15% for label rounding and energy from alcohol, polyols and organic acids
+		FruitsMin:           0,     // 0% fruits/vegetables/nuts
+		FruitsMax:           100,   // 100% fruits/vegetables/nuts
+		FibreMin:            0,     // 0g per 100g
+		FibreMax:            50,    // 50g per 100g (very high fiber foods)
+		FibreWarning:        30,    // 30g per 100g (only bran and fibre supplements)
+		ProteinMin:          0,     // 0g per 100g
+		ProteinMax:          100,   // 100g per 100g (pure protein)
+	}
+}
+
+// ValidationProfiles holds validation rules for particular food categories and score types
+// A category profile takes precedence over a score type profile; foods matching neither
+// are validated with the default rules
+type ValidationProfiles struct {
+	Default    NutritionalDataValidation               `json:"default"`               // Rules for foods without a profile
+	Categories map[string]NutritionalDataValidation    `json:"categories,omitempty"`  // Rules keyed by lower-case food category
+	ScoreTypes map[ScoreType]NutritionalDataValidation `json:"score_types,omitempty"` // Rules keyed by score type
+}
+
+// RulesFor returns the validation rules of a food category and score type
+// Categories match case-insensitively as long as the profile keys are lower case
+func (vp ValidationProfiles) RulesFor(category string, scoreType ScoreType) NutritionalDataValidation {
+	if rules, found := vp.Categories[CategoryKey(category)]; found {
+		return rules
+	}
+	if rules, found := vp.ScoreTypes[scoreType]; found {
+		return rules
+	}
+	return vp.Default
+}
+
+// CategoryKey returns the key a food category is stored under in ValidationProfiles
+func CategoryKey(category string) string {
+	return strings.ToLower(strings.TrimSpace(category))
+}
+
+// WithCategoryKeys returns a copy of the profiles with every category stored under its CategoryKey
+func (vp ValidationProfiles) WithCategoryKeys() ValidationProfiles {
+	categories := make(map[string]NutritionalDataValidation, len(vp.Categories))
+	for category, rules := range vp.Categories {
+		categories[CategoryKey(category)] = rules
+	}
+	vp.Categories = categories
+	return vp
+}
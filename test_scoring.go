@@ -1,8 +1,10 @@
+//go:build ignore
+
 package main
 
 import (
 	"fmt"
-	"nutritional-score/pkg/models"
+	"github.com/nutritional-score/pkg/models"
 )
 
 // This is a simple test file to demonstrate the enhanced scoring system
@@ -70,7 +72,7 @@ func testScoring() {
 	
 	// Show grade thresholds
 	fmt.Printf("\n=== Nutri-Score Grade Thresholds ===\n")
-	thresholds := GetScoreThresholds()
+	thresholds := GetScoreThresholds()[models.FoodType]
 	fmt.Printf("Grade A: Score ≤ %d (Best)\n", thresholds["A"])
 	fmt.Printf("Grade B: Score ≤ %d\n", thresholds["B"])
 	fmt.Printf("Grade C: Score ≤ %d\n", thresholds["C"])